@@ -13,20 +13,47 @@ type Config struct {
 	Logger    Logger     `mapstructure:"logger"`
 }
 type Provider struct {
+	ID               string           `mapstructure:"id"` // instance ID, defaults to Name
 	Name             string           `mapstructure:"name"`
 	Enabled          bool             `mapstructure:"enabled"`
 	ServiceEndpoints ServiceEndpoints `mapstructure:"api_access"`
 	Credentials      *Credentials     `mapstructure:"credentials"`
+	ScanTimeout      string           `mapstructure:"scan_timeout"`
+	Filters          Filters          `mapstructure:"filters"`
+	Executable       string           `mapstructure:"executable"`
+	Record           string           `mapstructure:"record"`
+	Path             string           `mapstructure:"path"`
+	Loop             bool             `mapstructure:"loop"`
+	Mode             string           `mapstructure:"mode"`
+	Namespace        string           `mapstructure:"namespace"`
+	Region           string           `mapstructure:"region"`
+	MaxEvents        int              `mapstructure:"max_events"`
+}
+
+type Filters struct {
+	Include           []string `mapstructure:"include"`
+	Exclude           []string `mapstructure:"exclude"`
+	Namespaces        []string `mapstructure:"namespaces"`
+	ExcludeNamespaces []string `mapstructure:"exclude_namespaces"`
+	Projects          []string `mapstructure:"projects"`
+	ExcludeProjects   []string `mapstructure:"exclude_projects"`
 }
 
 type ServiceEndpoints struct {
 	IdentityAPI string `mapstructure:"identity_api"`
 	ComputeAPI  string `mapstructure:"compute_api"`
+	StorageAPI  string `mapstructure:"storage_api"`
+	NetworkAPI  string `mapstructure:"network_api"`
+	ImageAPI    string `mapstructure:"image_api"`
+	ObjectAPI   string `mapstructure:"object_api"`
 }
 type Credentials struct {
 	OSAuthType           string `mapstructure:"os_auth_type"`
 	AppCredentialsID     string `mapstructure:"app_credentials_id"`
 	AppCredentialsSecret string `mapstructure:"app_credentials_secret"`
+	AccessKeyID          string `mapstructure:"access_key_id"`
+	SecretAccessKey      string `mapstructure:"secret_access_key"`
+	SessionToken         string `mapstructure:"session_token"`
 }
 
 type Logger struct {
@@ -66,6 +93,12 @@ func setDefaults() {
 	viper.SetDefault("NEO4J_USER", "neo4j")
 	viper.SetDefault("NEO4J_PASS", "1985ycdibiy")
 	viper.SetDefault("NEO4J_PROTO", "bolt")
+	viper.SetDefault("NEO4J_BATCH_SIZE", 500)
+	viper.SetDefault("NEO4J_STORAGE", "versioned")
+	viper.SetDefault("SCAN_TIMEOUT", "2m")
+	viper.SetDefault("SCAN_SCHEDULE", "@every 3m")
+	viper.SetDefault("PLUGINS_DIR", "plugins")
+	viper.SetDefault("MAPPINGS_DIR", "mappings")
 }
 
 func setConfigPath() error {
@@ -23,6 +23,7 @@ import (
 // NewExecutableSchema creates an ExecutableSchema from the ResolverRoot interface.
 func NewExecutableSchema(cfg Config) graphql.ExecutableSchema {
 	return &executableSchema{
+		schema:     cfg.Schema,
 		resolvers:  cfg.Resolvers,
 		directives: cfg.Directives,
 		complexity: cfg.Complexity,
@@ -30,12 +31,14 @@ func NewExecutableSchema(cfg Config) graphql.ExecutableSchema {
 }
 
 type Config struct {
+	Schema     *ast.Schema
 	Resolvers  ResolverRoot
 	Directives DirectiveRoot
 	Complexity ComplexityRoot
 }
 
 type ResolverRoot interface {
+	Mutation() MutationResolver
 	Query() QueryResolver
 }
 
@@ -48,6 +51,7 @@ type ComplexityRoot struct {
 		ID                  func(childComplexity int) int
 		Name                func(childComplexity int) int
 		Pods                func(childComplexity int) int
+		Provider            func(childComplexity int) int
 		ProvisionedInstance func(childComplexity int) int
 		Type                func(childComplexity int) int
 		UUID                func(childComplexity int) int
@@ -61,6 +65,20 @@ type ComplexityRoot struct {
 		Storage      func(childComplexity int) int
 	}
 
+	Finding struct {
+		Annotation    func(childComplexity int) int
+		ID            func(childComplexity int) int
+		Kind          func(childComplexity int) int
+		Name          func(childComplexity int) int
+		Provider      func(childComplexity int) int
+		SchemaVersion func(childComplexity int) int
+		Subject       func(childComplexity int) int
+		SubjectType   func(childComplexity int) int
+		Type          func(childComplexity int) int
+		UUID          func(childComplexity int) int
+		Violations    func(childComplexity int) int
+	}
+
 	Instance struct {
 		AvailabilityZone func(childComplexity int) int
 		Created          func(childComplexity int) int
@@ -68,7 +86,9 @@ type ComplexityRoot struct {
 		ID               func(childComplexity int) int
 		Name             func(childComplexity int) int
 		PhysicalHost     func(childComplexity int) int
+		Provider         func(childComplexity int) int
 		Status           func(childComplexity int) int
+		SubnetID         func(childComplexity int) int
 		TenantID         func(childComplexity int) int
 		Type             func(childComplexity int) int
 		UUID             func(childComplexity int) int
@@ -76,19 +96,31 @@ type ComplexityRoot struct {
 		UserID           func(childComplexity int) int
 		Volumes          func(childComplexity int) int
 		VolumesAttached  func(childComplexity int) int
+		VpcID            func(childComplexity int) int
 	}
 
 	Metadata struct {
+		Complete      func(childComplexity int) int
 		Projects      func(childComplexity int) int
+		ScanResult    func(childComplexity int) int
+		ScanStatus    func(childComplexity int) int
 		ScanTimestamp func(childComplexity int) int
+		Status        func(childComplexity int) int
 		Version       func(childComplexity int) int
 	}
 
+	Mutation struct {
+		ScanNow func(childComplexity int, providers []string) int
+	}
+
 	PDIndicator struct {
 		DataCategories func(childComplexity int) int
+		DeclaredBy     func(childComplexity int) int
+		DeclaredOn     func(childComplexity int) int
 		ID             func(childComplexity int) int
 		Name           func(childComplexity int) int
 		Pods           func(childComplexity int) int
+		Provider       func(childComplexity int) int
 		Type           func(childComplexity int) int
 		UUID           func(childComplexity int) int
 	}
@@ -98,6 +130,8 @@ type ComplexityRoot struct {
 		ID                    func(childComplexity int) int
 		Name                  func(childComplexity int) int
 		PersistentVolumeClaim func(childComplexity int) int
+		Provider              func(childComplexity int) int
+		Source                func(childComplexity int) int
 		StoredVolume          func(childComplexity int) int
 		Type                  func(childComplexity int) int
 		UUID                  func(childComplexity int) int
@@ -108,6 +142,7 @@ type ComplexityRoot struct {
 		Name             func(childComplexity int) int
 		PersistentVolume func(childComplexity int) int
 		Pods             func(childComplexity int) int
+		Provider         func(childComplexity int) int
 		Type             func(childComplexity int) int
 		UUID             func(childComplexity int) int
 	}
@@ -117,6 +152,7 @@ type ComplexityRoot struct {
 		ID               func(childComplexity int) int
 		Instances        func(childComplexity int) int
 		Name             func(childComplexity int) int
+		Provider         func(childComplexity int) int
 		Type             func(childComplexity int) int
 		UUID             func(childComplexity int) int
 	}
@@ -126,8 +162,10 @@ type ComplexityRoot struct {
 		CreatedAt              func(childComplexity int) int
 		ID                     func(childComplexity int) int
 		Name                   func(childComplexity int) int
+		Namespace              func(childComplexity int) int
 		PdIndicators           func(childComplexity int) int
 		PersistentVolumeClaims func(childComplexity int) int
+		Provider               func(childComplexity int) int
 		Storage                func(childComplexity int) int
 		Type                   func(childComplexity int) int
 		UUID                   func(childComplexity int) int
@@ -140,23 +178,27 @@ type ComplexityRoot struct {
 		ID               func(childComplexity int) int
 		Instances        func(childComplexity int) int
 		Name             func(childComplexity int) int
+		Provider         func(childComplexity int) int
 		Type             func(childComplexity int) int
 		UUID             func(childComplexity int) int
 	}
 
 	Query struct {
-		GetClusterNode           func(childComplexity int, id string) int
-		GetDataCategory          func(childComplexity int, name string) int
-		GetInstance              func(childComplexity int, id string) int
-		GetMetadata              func(childComplexity int, version string) int
-		GetPDIndicator           func(childComplexity int, id string) int
-		GetPdsWithCategory       func(childComplexity int, version string, categoryName string) int
-		GetPersistentVolume      func(childComplexity int, id string) int
-		GetPersistentVolumeClaim func(childComplexity int, id string) int
-		GetPhysicalHost          func(childComplexity int, id string) int
-		GetPod                   func(childComplexity int, id string) int
-		GetProject               func(childComplexity int, uuid string) int
-		GetVolume                func(childComplexity int, id string) int
+		GetClusterNode            func(childComplexity int, id string) int
+		GetDataCategory           func(childComplexity int, name string) int
+		GetExposedPdsWithCategory func(childComplexity int, version *string, categoryName string) int
+		GetFindings               func(childComplexity int, version *string) int
+		GetInstance               func(childComplexity int, id string) int
+		GetMetadata               func(childComplexity int, version *string) int
+		GetPDIndicator            func(childComplexity int, id string) int
+		GetPdsWithCategory        func(childComplexity int, version *string, categoryName string) int
+		GetPersistentVolume       func(childComplexity int, id string) int
+		GetPersistentVolumeClaim  func(childComplexity int, id string) int
+		GetPhysicalHost           func(childComplexity int, id string) int
+		GetPod                    func(childComplexity int, id string) int
+		GetProject                func(childComplexity int, uuid string) int
+		GetPublicInstancesWithPds func(childComplexity int, version *string) int
+		GetVolume                 func(childComplexity int, id string) int
 	}
 
 	Volume struct {
@@ -169,6 +211,7 @@ type ComplexityRoot struct {
 		Multiattach      func(childComplexity int) int
 		Name             func(childComplexity int) int
 		PersistentVolume func(childComplexity int) int
+		Provider         func(childComplexity int) int
 		Size             func(childComplexity int) int
 		SrcSnapshot      func(childComplexity int) int
 		Status           func(childComplexity int) int
@@ -177,8 +220,11 @@ type ComplexityRoot struct {
 	}
 }
 
+type MutationResolver interface {
+	ScanNow(ctx context.Context, providers []string) ([]string, error)
+}
 type QueryResolver interface {
-	GetMetadata(ctx context.Context, version string) (*model.Metadata, error)
+	GetMetadata(ctx context.Context, version *string) (*model.Metadata, error)
 	GetProject(ctx context.Context, uuid string) (*model.Project, error)
 	GetInstance(ctx context.Context, id string) (*model.Instance, error)
 	GetVolume(ctx context.Context, id string) (*model.Volume, error)
@@ -189,16 +235,23 @@ type QueryResolver interface {
 	GetPersistentVolumeClaim(ctx context.Context, id string) (*model.PersistentVolumeClaim, error)
 	GetPDIndicator(ctx context.Context, id string) (*model.PDIndicator, error)
 	GetDataCategory(ctx context.Context, name string) (*model.DataCategory, error)
-	GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error)
+	GetPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error)
+	GetExposedPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error)
+	GetPublicInstancesWithPds(ctx context.Context, version *string) ([]*model.Instance, error)
+	GetFindings(ctx context.Context, version *string) ([]*model.Finding, error)
 }
 
 type executableSchema struct {
+	schema     *ast.Schema
 	resolvers  ResolverRoot
 	directives DirectiveRoot
 	complexity ComplexityRoot
 }
 
 func (e *executableSchema) Schema() *ast.Schema {
+	if e.schema != nil {
+		return e.schema
+	}
 	return parsedSchema
 }
 
@@ -235,6 +288,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ClusterNode.Pods(childComplexity), true
 
+	case "ClusterNode.provider":
+		if e.complexity.ClusterNode.Provider == nil {
+			break
+		}
+
+		return e.complexity.ClusterNode.Provider(childComplexity), true
+
 	case "ClusterNode.provisionedInstance":
 		if e.complexity.ClusterNode.ProvisionedInstance == nil {
 			break
@@ -291,6 +351,83 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.DataCategory.Storage(childComplexity), true
 
+	case "Finding.annotation":
+		if e.complexity.Finding.Annotation == nil {
+			break
+		}
+
+		return e.complexity.Finding.Annotation(childComplexity), true
+
+	case "Finding.id":
+		if e.complexity.Finding.ID == nil {
+			break
+		}
+
+		return e.complexity.Finding.ID(childComplexity), true
+
+	case "Finding.kind":
+		if e.complexity.Finding.Kind == nil {
+			break
+		}
+
+		return e.complexity.Finding.Kind(childComplexity), true
+
+	case "Finding.name":
+		if e.complexity.Finding.Name == nil {
+			break
+		}
+
+		return e.complexity.Finding.Name(childComplexity), true
+
+	case "Finding.provider":
+		if e.complexity.Finding.Provider == nil {
+			break
+		}
+
+		return e.complexity.Finding.Provider(childComplexity), true
+
+	case "Finding.schemaVersion":
+		if e.complexity.Finding.SchemaVersion == nil {
+			break
+		}
+
+		return e.complexity.Finding.SchemaVersion(childComplexity), true
+
+	case "Finding.subject":
+		if e.complexity.Finding.Subject == nil {
+			break
+		}
+
+		return e.complexity.Finding.Subject(childComplexity), true
+
+	case "Finding.subjectType":
+		if e.complexity.Finding.SubjectType == nil {
+			break
+		}
+
+		return e.complexity.Finding.SubjectType(childComplexity), true
+
+	case "Finding.type":
+		if e.complexity.Finding.Type == nil {
+			break
+		}
+
+		return e.complexity.Finding.Type(childComplexity), true
+
+	case "Finding.uuid":
+		if e.complexity.Finding.UUID == nil {
+			break
+		}
+
+		return e.complexity.Finding.UUID(childComplexity), true
+
+	case "Finding.violations":
+		if e.complexity.Finding.Violations == nil {
+			break
+		}
+
+		return e.complexity.Finding.Violations(childComplexity), true
+
 	case "Instance.availabilityZone":
 		if e.complexity.Instance.AvailabilityZone == nil {
 			break
@@ -333,6 +470,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Instance.PhysicalHost(childComplexity), true
 
+	case "Instance.provider":
+		if e.complexity.Instance.Provider == nil {
+			break
+		}
+
+		return e.complexity.Instance.Provider(childComplexity), true
+
 	case "Instance.status":
 		if e.complexity.Instance.Status == nil {
 			break
@@ -340,6 +484,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Instance.Status(childComplexity), true
 
+	case "Instance.subnetID":
+		if e.complexity.Instance.SubnetID == nil {
+			break
+		}
+
+		return e.complexity.Instance.SubnetID(childComplexity), true
+
 	case "Instance.tenantID":
 		if e.complexity.Instance.TenantID == nil {
 			break
@@ -389,6 +540,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Instance.VolumesAttached(childComplexity), true
 
+	case "Instance.vpcID":
+		if e.complexity.Instance.VpcID == nil {
+			break
+		}
+
+		return e.complexity.Instance.VpcID(childComplexity), true
+
+	case "Metadata.complete":
+		if e.complexity.Metadata.Complete == nil {
+			break
+		}
+
+		return e.complexity.Metadata.Complete(childComplexity), true
+
 	case "Metadata.projects":
 		if e.complexity.Metadata.Projects == nil {
 			break
@@ -396,6 +561,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Metadata.Projects(childComplexity), true
 
+	case "Metadata.scanResult":
+		if e.complexity.Metadata.ScanResult == nil {
+			break
+		}
+
+		return e.complexity.Metadata.ScanResult(childComplexity), true
+
+	case "Metadata.scanStatus":
+		if e.complexity.Metadata.ScanStatus == nil {
+			break
+		}
+
+		return e.complexity.Metadata.ScanStatus(childComplexity), true
+
 	case "Metadata.scanTimestamp":
 		if e.complexity.Metadata.ScanTimestamp == nil {
 			break
@@ -403,6 +582,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Metadata.ScanTimestamp(childComplexity), true
 
+	case "Metadata.status":
+		if e.complexity.Metadata.Status == nil {
+			break
+		}
+
+		return e.complexity.Metadata.Status(childComplexity), true
+
 	case "Metadata.version":
 		if e.complexity.Metadata.Version == nil {
 			break
@@ -410,6 +596,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Metadata.Version(childComplexity), true
 
+	case "Mutation.scanNow":
+		if e.complexity.Mutation.ScanNow == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_scanNow_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ScanNow(childComplexity, args["providers"].([]string)), true
+
 	case "PDIndicator.dataCategories":
 		if e.complexity.PDIndicator.DataCategories == nil {
 			break
@@ -417,6 +615,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PDIndicator.DataCategories(childComplexity), true
 
+	case "PDIndicator.declaredBy":
+		if e.complexity.PDIndicator.DeclaredBy == nil {
+			break
+		}
+
+		return e.complexity.PDIndicator.DeclaredBy(childComplexity), true
+
+	case "PDIndicator.declaredOn":
+		if e.complexity.PDIndicator.DeclaredOn == nil {
+			break
+		}
+
+		return e.complexity.PDIndicator.DeclaredOn(childComplexity), true
+
 	case "PDIndicator.id":
 		if e.complexity.PDIndicator.ID == nil {
 			break
@@ -438,6 +650,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PDIndicator.Pods(childComplexity), true
 
+	case "PDIndicator.provider":
+		if e.complexity.PDIndicator.Provider == nil {
+			break
+		}
+
+		return e.complexity.PDIndicator.Provider(childComplexity), true
+
 	case "PDIndicator.type":
 		if e.complexity.PDIndicator.Type == nil {
 			break
@@ -480,6 +699,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PersistentVolume.PersistentVolumeClaim(childComplexity), true
 
+	case "PersistentVolume.provider":
+		if e.complexity.PersistentVolume.Provider == nil {
+			break
+		}
+
+		return e.complexity.PersistentVolume.Provider(childComplexity), true
+
+	case "PersistentVolume.source":
+		if e.complexity.PersistentVolume.Source == nil {
+			break
+		}
+
+		return e.complexity.PersistentVolume.Source(childComplexity), true
+
 	case "PersistentVolume.storedVolume":
 		if e.complexity.PersistentVolume.StoredVolume == nil {
 			break
@@ -529,6 +762,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PersistentVolumeClaim.Pods(childComplexity), true
 
+	case "PersistentVolumeClaim.provider":
+		if e.complexity.PersistentVolumeClaim.Provider == nil {
+			break
+		}
+
+		return e.complexity.PersistentVolumeClaim.Provider(childComplexity), true
+
 	case "PersistentVolumeClaim.type":
 		if e.complexity.PersistentVolumeClaim.Type == nil {
 			break
@@ -571,6 +811,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PhysicalHost.Name(childComplexity), true
 
+	case "PhysicalHost.provider":
+		if e.complexity.PhysicalHost.Provider == nil {
+			break
+		}
+
+		return e.complexity.PhysicalHost.Provider(childComplexity), true
+
 	case "PhysicalHost.type":
 		if e.complexity.PhysicalHost.Type == nil {
 			break
@@ -613,6 +860,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Pod.Name(childComplexity), true
 
+	case "Pod.namespace":
+		if e.complexity.Pod.Namespace == nil {
+			break
+		}
+
+		return e.complexity.Pod.Namespace(childComplexity), true
+
 	case "Pod.pdIndicators":
 		if e.complexity.Pod.PdIndicators == nil {
 			break
@@ -627,6 +881,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Pod.PersistentVolumeClaims(childComplexity), true
 
+	case "Pod.provider":
+		if e.complexity.Pod.Provider == nil {
+			break
+		}
+
+		return e.complexity.Pod.Provider(childComplexity), true
+
 	case "Pod.storage":
 		if e.complexity.Pod.Storage == nil {
 			break
@@ -690,6 +951,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Project.Name(childComplexity), true
 
+	case "Project.provider":
+		if e.complexity.Project.Provider == nil {
+			break
+		}
+
+		return e.complexity.Project.Provider(childComplexity), true
+
 	case "Project.type":
 		if e.complexity.Project.Type == nil {
 			break
@@ -728,6 +996,30 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.GetDataCategory(childComplexity, args["name"].(string)), true
 
+	case "Query.getExposedPdsWithCategory":
+		if e.complexity.Query.GetExposedPdsWithCategory == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getExposedPdsWithCategory_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetExposedPdsWithCategory(childComplexity, args["version"].(*string), args["categoryName"].(string)), true
+
+	case "Query.getFindings":
+		if e.complexity.Query.GetFindings == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getFindings_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetFindings(childComplexity, args["version"].(*string)), true
+
 	case "Query.getInstance":
 		if e.complexity.Query.GetInstance == nil {
 			break
@@ -750,7 +1042,7 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Query.GetMetadata(childComplexity, args["version"].(string)), true
+		return e.complexity.Query.GetMetadata(childComplexity, args["version"].(*string)), true
 
 	case "Query.getPDIndicator":
 		if e.complexity.Query.GetPDIndicator == nil {
@@ -774,7 +1066,7 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Query.GetPdsWithCategory(childComplexity, args["version"].(string), args["categoryName"].(string)), true
+		return e.complexity.Query.GetPdsWithCategory(childComplexity, args["version"].(*string), args["categoryName"].(string)), true
 
 	case "Query.getPersistentVolume":
 		if e.complexity.Query.GetPersistentVolume == nil {
@@ -836,6 +1128,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.GetProject(childComplexity, args["uuid"].(string)), true
 
+	case "Query.getPublicInstancesWithPds":
+		if e.complexity.Query.GetPublicInstancesWithPds == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getPublicInstancesWithPds_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetPublicInstancesWithPds(childComplexity, args["version"].(*string)), true
+
 	case "Query.getVolume":
 		if e.complexity.Query.GetVolume == nil {
 			break
@@ -911,6 +1215,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Volume.PersistentVolume(childComplexity), true
 
+	case "Volume.provider":
+		if e.complexity.Volume.Provider == nil {
+			break
+		}
+
+		return e.complexity.Volume.Provider(childComplexity), true
+
 	case "Volume.size":
 		if e.complexity.Volume.Size == nil {
 			break
@@ -987,6 +1298,21 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 
 			return &response
 		}
+	case ast.Mutation:
+		return func(ctx context.Context) *graphql.Response {
+			if !first {
+				return nil
+			}
+			first = false
+			ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
+			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
+			var buf bytes.Buffer
+			data.MarshalGQL(&buf)
+
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
 
 	default:
 		return graphql.OneShot(graphql.ErrorResponse(ctx, "unsupported GraphQL operation"))
@@ -1024,19 +1350,20 @@ func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
 	if ec.DisableIntrospection {
 		return nil, errors.New("introspection disabled")
 	}
-	return introspection.WrapSchema(parsedSchema), nil
+	return introspection.WrapSchema(ec.Schema()), nil
 }
 
 func (ec *executionContext) introspectType(name string) (*introspection.Type, error) {
 	if ec.DisableIntrospection {
 		return nil, errors.New("introspection disabled")
 	}
-	return introspection.WrapTypeFromDef(parsedSchema, parsedSchema.Types[name]), nil
+	return introspection.WrapTypeFromDef(ec.Schema(), ec.Schema().Types[name]), nil
 }
 
 var sources = []*ast.Source{
-	{Name: "../schema.graphqls", Input: `type Query {
-    getMetadata(version: String!): Metadata
+	{Name: "../schema.graphqls", Input: `# Queries taking a version default to the latest complete version
+type Query {
+    getMetadata(version: String): Metadata
     getProject(uuid: String!): Project
     getInstance(id: String!): Instance
     getVolume(id: String!): Volume
@@ -1047,18 +1374,35 @@ var sources = []*ast.Source{
     getPersistentVolumeClaim(id: String!): PersistentVolumeClaim
     getPDIndicator(id: String!): PDIndicator
     getDataCategory(name: String!): DataCategory
-    getPdsWithCategory(version: String!, categoryName: String!): [Pod]
+    getPdsWithCategory(version: String, categoryName: String!): [Pod]
+    # Pods of a data category reachable through an Ingress or an exposed Service
+    getExposedPdsWithCategory(version: String, categoryName: String!): [Pod]
+    # Instances running pods with personal data that have a floating IP
+    getPublicInstancesWithPds(version: String): [Instance]
+    # Problems found in a version, e.g. has_pd annotations not matching their schema
+    getFindings(version: String): [Finding]
+}
+
+type Mutation {
+    # Starts a scan of the given provider instances, all providers if none are given
+    scanNow(providers: [String!]): [String!]!
 }
 
 type Metadata {
     version: String!
     scanTimestamp: String!
+    # pending while scanning, complete once published or failed
+    status: String
+    scanStatus: String
+    complete: Boolean!
+    scanResult: String
     projects: [Project!]!
 }
 
 type Project {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     availabilityZone: String!
@@ -1070,6 +1414,7 @@ type Project {
 type Instance {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     availabilityZone: String!
@@ -1080,6 +1425,8 @@ type Instance {
     updated: String!
     volumesAttached: [String!]!
     status: String!
+    vpcID: String
+    subnetID: String
     physicalHost: PhysicalHost
     volumes: [Volume!]!
 }
@@ -1087,6 +1434,7 @@ type Instance {
 type PhysicalHost {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     availabilityZone: String!
@@ -1096,6 +1444,7 @@ type PhysicalHost {
 type Volume {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     availabilityZone: String!
@@ -1113,6 +1462,7 @@ type Volume {
 type ClusterNode {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     createdAt: String!
@@ -1123,8 +1473,10 @@ type ClusterNode {
 type Pod {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
+    namespace: String
     createdAt: String!
     storage: String!
     clusterNode: ClusterNode!
@@ -1135,9 +1487,11 @@ type Pod {
 type PersistentVolume {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     createdAt: String!
+    source: String
     storedVolume: Volume!
     persistentVolumeClaim: PersistentVolumeClaim!
 }
@@ -1145,6 +1499,7 @@ type PersistentVolume {
 type PersistentVolumeClaim {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
     persistentVolume: PersistentVolume!
@@ -1154,12 +1509,30 @@ type PersistentVolumeClaim {
 type PDIndicator {
     uuid: String!
     id: String!
+    provider: String
     name: String!
     type: String!
+    # Kind and name of the object the has_pd annotation was declared on
+    declaredOn: String
+    declaredBy: String
     dataCategories: [DataCategory!]!
     pods: [Pod!]!
 }
 
+type Finding {
+    uuid: String!
+    id: String!
+    provider: String
+    name: String!
+    type: String!
+    kind: String!
+    schemaVersion: String
+    violations: [String!]!
+    annotation: String
+    subject: String!
+    subjectType: String!
+}
+
 type DataCategory {
     name: String!
     purpose: String!
@@ -1175,6 +1548,21 @@ var parsedSchema = gqlparser.MustLoadSchema(sources...)
 
 // region    ***************************** args.gotpl *****************************
 
+func (ec *executionContext) field_Mutation_scanNow_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 []string
+	if tmp, ok := rawArgs["providers"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("providers"))
+		arg0, err = ec.unmarshalOString2ᚕstringᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["providers"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1220,6 +1608,45 @@ func (ec *executionContext) field_Query_getDataCategory_args(ctx context.Context
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_getExposedPdsWithCategory_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["version"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["version"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["categoryName"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("categoryName"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["categoryName"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_getFindings_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["version"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["version"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_getInstance_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1238,10 +1665,10 @@ func (ec *executionContext) field_Query_getInstance_args(ctx context.Context, ra
 func (ec *executionContext) field_Query_getMetadata_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 string
+	var arg0 *string
 	if tmp, ok := rawArgs["version"]; ok {
 		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
@@ -1268,10 +1695,10 @@ func (ec *executionContext) field_Query_getPDIndicator_args(ctx context.Context,
 func (ec *executionContext) field_Query_getPdsWithCategory_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 string
+	var arg0 *string
 	if tmp, ok := rawArgs["version"]; ok {
 		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
@@ -1364,6 +1791,21 @@ func (ec *executionContext) field_Query_getProject_args(ctx context.Context, raw
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_getPublicInstancesWithPds_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["version"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["version"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_getVolume_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1505,8 +1947,8 @@ func (ec *executionContext) fieldContext_ClusterNode_id(ctx context.Context, fie
 	return fc, nil
 }
 
-func (ec *executionContext) _ClusterNode_name(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ClusterNode_name(ctx, field)
+func (ec *executionContext) _ClusterNode_provider(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ClusterNode_provider(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1519,24 +1961,21 @@ func (ec *executionContext) _ClusterNode_name(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Provider, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ClusterNode_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ClusterNode_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "ClusterNode",
 		Field:      field,
@@ -1549,8 +1988,8 @@ func (ec *executionContext) fieldContext_ClusterNode_name(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _ClusterNode_type(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ClusterNode_type(ctx, field)
+func (ec *executionContext) _ClusterNode_name(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ClusterNode_name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1563,7 +2002,7 @@ func (ec *executionContext) _ClusterNode_type(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1580,7 +2019,7 @@ func (ec *executionContext) _ClusterNode_type(ctx context.Context, field graphql
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ClusterNode_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ClusterNode_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "ClusterNode",
 		Field:      field,
@@ -1593,8 +2032,8 @@ func (ec *executionContext) fieldContext_ClusterNode_type(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _ClusterNode_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ClusterNode_createdAt(ctx, field)
+func (ec *executionContext) _ClusterNode_type(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ClusterNode_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1607,7 +2046,51 @@ func (ec *executionContext) _ClusterNode_createdAt(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CreatedAt, nil
+		return obj.Type, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ClusterNode_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ClusterNode",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ClusterNode_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.ClusterNode) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ClusterNode_createdAt(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.CreatedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1680,6 +2163,8 @@ func (ec *executionContext) fieldContext_ClusterNode_provisionedInstance(ctx con
 				return ec.fieldContext_Instance_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Instance_name(ctx, field)
 			case "type":
@@ -1700,6 +2185,10 @@ func (ec *executionContext) fieldContext_ClusterNode_provisionedInstance(ctx con
 				return ec.fieldContext_Instance_volumesAttached(ctx, field)
 			case "status":
 				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
 			case "physicalHost":
 				return ec.fieldContext_Instance_physicalHost(ctx, field)
 			case "volumes":
@@ -1754,10 +2243,14 @@ func (ec *executionContext) fieldContext_ClusterNode_pods(ctx context.Context, f
 				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
 				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Pod_createdAt(ctx, field)
 			case "storage":
@@ -1994,10 +2487,16 @@ func (ec *executionContext) fieldContext_DataCategory_pdIndicators(ctx context.C
 				return ec.fieldContext_PDIndicator_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PDIndicator_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PDIndicator_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PDIndicator_name(ctx, field)
 			case "type":
 				return ec.fieldContext_PDIndicator_type(ctx, field)
+			case "declaredOn":
+				return ec.fieldContext_PDIndicator_declaredOn(ctx, field)
+			case "declaredBy":
+				return ec.fieldContext_PDIndicator_declaredBy(ctx, field)
 			case "dataCategories":
 				return ec.fieldContext_PDIndicator_dataCategories(ctx, field)
 			case "pods":
@@ -2009,8 +2508,8 @@ func (ec *executionContext) fieldContext_DataCategory_pdIndicators(ctx context.C
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_uuid(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_uuid(ctx, field)
+func (ec *executionContext) _Finding_uuid(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_uuid(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2040,9 +2539,9 @@ func (ec *executionContext) _Instance_uuid(ctx context.Context, field graphql.Co
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_uuid(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_uuid(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2053,8 +2552,8 @@ func (ec *executionContext) fieldContext_Instance_uuid(ctx context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_id(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_id(ctx, field)
+func (ec *executionContext) _Finding_id(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_id(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2084,9 +2583,9 @@ func (ec *executionContext) _Instance_id(ctx context.Context, field graphql.Coll
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2097,8 +2596,8 @@ func (ec *executionContext) fieldContext_Instance_id(ctx context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_name(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_name(ctx, field)
+func (ec *executionContext) _Finding_provider(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_provider(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2111,26 +2610,23 @@ func (ec *executionContext) _Instance_name(ctx context.Context, field graphql.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Provider, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2141,8 +2637,8 @@ func (ec *executionContext) fieldContext_Instance_name(ctx context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_type(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_type(ctx, field)
+func (ec *executionContext) _Finding_name(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2155,7 +2651,7 @@ func (ec *executionContext) _Instance_type(ctx context.Context, field graphql.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2172,9 +2668,9 @@ func (ec *executionContext) _Instance_type(ctx context.Context, field graphql.Co
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2185,8 +2681,8 @@ func (ec *executionContext) fieldContext_Instance_type(ctx context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_availabilityZone(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_availabilityZone(ctx, field)
+func (ec *executionContext) _Finding_type(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2199,7 +2695,7 @@ func (ec *executionContext) _Instance_availabilityZone(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.AvailabilityZone, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2216,9 +2712,9 @@ func (ec *executionContext) _Instance_availabilityZone(ctx context.Context, fiel
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_availabilityZone(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2229,8 +2725,8 @@ func (ec *executionContext) fieldContext_Instance_availabilityZone(ctx context.C
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_userID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_userID(ctx, field)
+func (ec *executionContext) _Finding_kind(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_kind(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2243,7 +2739,7 @@ func (ec *executionContext) _Instance_userID(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.UserID, nil
+		return obj.Kind, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2260,9 +2756,9 @@ func (ec *executionContext) _Instance_userID(ctx context.Context, field graphql.
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_userID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_kind(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2273,8 +2769,8 @@ func (ec *executionContext) fieldContext_Instance_userID(ctx context.Context, fi
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_hostID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_hostID(ctx, field)
+func (ec *executionContext) _Finding_schemaVersion(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_schemaVersion(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2287,26 +2783,23 @@ func (ec *executionContext) _Instance_hostID(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.HostID, nil
+		return obj.SchemaVersion, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_hostID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_schemaVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2317,8 +2810,8 @@ func (ec *executionContext) fieldContext_Instance_hostID(ctx context.Context, fi
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_tenantID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_tenantID(ctx, field)
+func (ec *executionContext) _Finding_violations(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_violations(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2331,7 +2824,7 @@ func (ec *executionContext) _Instance_tenantID(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.TenantID, nil
+		return obj.Violations, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2343,14 +2836,14 @@ func (ec *executionContext) _Instance_tenantID(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.([]string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_tenantID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_violations(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2361,8 +2854,8 @@ func (ec *executionContext) fieldContext_Instance_tenantID(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_created(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_created(ctx, field)
+func (ec *executionContext) _Finding_annotation(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_annotation(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2375,26 +2868,23 @@ func (ec *executionContext) _Instance_created(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Created, nil
+		return obj.Annotation, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_created(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_annotation(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2405,8 +2895,8 @@ func (ec *executionContext) fieldContext_Instance_created(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_updated(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_updated(ctx, field)
+func (ec *executionContext) _Finding_subject(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_subject(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2419,7 +2909,7 @@ func (ec *executionContext) _Instance_updated(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Updated, nil
+		return obj.Subject, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2436,9 +2926,9 @@ func (ec *executionContext) _Instance_updated(ctx context.Context, field graphql
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_updated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_subject(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2449,8 +2939,8 @@ func (ec *executionContext) fieldContext_Instance_updated(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_volumesAttached(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_volumesAttached(ctx, field)
+func (ec *executionContext) _Finding_subjectType(ctx context.Context, field graphql.CollectedField, obj *model.Finding) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Finding_subjectType(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2463,7 +2953,7 @@ func (ec *executionContext) _Instance_volumesAttached(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.VolumesAttached, nil
+		return obj.SubjectType, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2475,14 +2965,14 @@ func (ec *executionContext) _Instance_volumesAttached(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]string)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_volumesAttached(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Finding_subjectType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Instance",
+		Object:     "Finding",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2493,8 +2983,8 @@ func (ec *executionContext) fieldContext_Instance_volumesAttached(ctx context.Co
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_status(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_status(ctx, field)
+func (ec *executionContext) _Instance_uuid(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_uuid(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2507,7 +2997,7 @@ func (ec *executionContext) _Instance_status(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Status, nil
+		return obj.UUID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2524,7 +3014,7 @@ func (ec *executionContext) _Instance_status(ctx context.Context, field graphql.
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_status(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_uuid(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Instance",
 		Field:      field,
@@ -2537,8 +3027,8 @@ func (ec *executionContext) fieldContext_Instance_status(ctx context.Context, fi
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_physicalHost(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_physicalHost(ctx, field)
+func (ec *executionContext) _Instance_id(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_id(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2551,49 +3041,38 @@ func (ec *executionContext) _Instance_physicalHost(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PhysicalHost, nil
+		return obj.ID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*model.PhysicalHost)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOPhysicalHost2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPhysicalHost(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_physicalHost(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Instance",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "uuid":
-				return ec.fieldContext_PhysicalHost_uuid(ctx, field)
-			case "id":
-				return ec.fieldContext_PhysicalHost_id(ctx, field)
-			case "name":
-				return ec.fieldContext_PhysicalHost_name(ctx, field)
-			case "type":
-				return ec.fieldContext_PhysicalHost_type(ctx, field)
-			case "availabilityZone":
-				return ec.fieldContext_PhysicalHost_availabilityZone(ctx, field)
-			case "instances":
-				return ec.fieldContext_PhysicalHost_instances(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PhysicalHost", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Instance_volumes(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Instance_volumes(ctx, field)
+func (ec *executionContext) _Instance_provider(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_provider(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2606,68 +3085,35 @@ func (ec *executionContext) _Instance_volumes(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Volumes, nil
+		return obj.Provider, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*model.Volume)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNVolume2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐVolumeᚄ(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Instance_volumes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Instance",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "uuid":
-				return ec.fieldContext_Volume_uuid(ctx, field)
-			case "id":
-				return ec.fieldContext_Volume_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Volume_name(ctx, field)
-			case "type":
-				return ec.fieldContext_Volume_type(ctx, field)
-			case "availabilityZone":
-				return ec.fieldContext_Volume_availabilityZone(ctx, field)
-			case "status":
-				return ec.fieldContext_Volume_status(ctx, field)
-			case "size":
-				return ec.fieldContext_Volume_size(ctx, field)
-			case "bootable":
-				return ec.fieldContext_Volume_bootable(ctx, field)
-			case "encrypted":
-				return ec.fieldContext_Volume_encrypted(ctx, field)
-			case "multiattach":
-				return ec.fieldContext_Volume_multiattach(ctx, field)
-			case "device":
-				return ec.fieldContext_Volume_device(ctx, field)
-			case "srcSnapshot":
-				return ec.fieldContext_Volume_srcSnapshot(ctx, field)
-			case "instances":
-				return ec.fieldContext_Volume_instances(ctx, field)
-			case "persistentVolume":
-				return ec.fieldContext_Volume_persistentVolume(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Volume", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Metadata_version(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Metadata_version(ctx, field)
+func (ec *executionContext) _Instance_name(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2680,7 +3126,7 @@ func (ec *executionContext) _Metadata_version(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Version, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2697,9 +3143,9 @@ func (ec *executionContext) _Metadata_version(ctx context.Context, field graphql
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Metadata_version(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Metadata",
+		Object:     "Instance",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2710,8 +3156,8 @@ func (ec *executionContext) fieldContext_Metadata_version(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _Metadata_scanTimestamp(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Metadata_scanTimestamp(ctx, field)
+func (ec *executionContext) _Instance_type(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2724,7 +3170,7 @@ func (ec *executionContext) _Metadata_scanTimestamp(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ScanTimestamp, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2741,9 +3187,9 @@ func (ec *executionContext) _Metadata_scanTimestamp(ctx context.Context, field g
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Metadata_scanTimestamp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Metadata",
+		Object:     "Instance",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2754,8 +3200,8 @@ func (ec *executionContext) fieldContext_Metadata_scanTimestamp(ctx context.Cont
 	return fc, nil
 }
 
-func (ec *executionContext) _Metadata_projects(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Metadata_projects(ctx, field)
+func (ec *executionContext) _Instance_availabilityZone(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_availabilityZone(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2768,7 +3214,7 @@ func (ec *executionContext) _Metadata_projects(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Projects, nil
+		return obj.AvailabilityZone, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2780,30 +3226,854 @@ func (ec *executionContext) _Metadata_projects(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]*model.Project)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNProject2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐProjectᚄ(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Metadata_projects(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Instance_availabilityZone(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Metadata",
+		Object:     "Instance",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "uuid":
-				return ec.fieldContext_Project_uuid(ctx, field)
-			case "id":
-				return ec.fieldContext_Project_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Project_name(ctx, field)
-			case "type":
-				return ec.fieldContext_Project_type(ctx, field)
-			case "availabilityZone":
-				return ec.fieldContext_Project_availabilityZone(ctx, field)
-			case "enabled":
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_userID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_userID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UserID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_userID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_hostID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_hostID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HostID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_hostID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_tenantID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_tenantID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.TenantID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_tenantID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_created(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_created(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Created, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_created(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_updated(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_updated(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Updated, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_updated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_volumesAttached(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_volumesAttached(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.VolumesAttached, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]string)
+	fc.Result = res
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_volumesAttached(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_status(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_status(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Status, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_status(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_vpcID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_vpcID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.VpcID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_vpcID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_subnetID(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_subnetID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.SubnetID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_subnetID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_physicalHost(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_physicalHost(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PhysicalHost, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.PhysicalHost)
+	fc.Result = res
+	return ec.marshalOPhysicalHost2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPhysicalHost(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_physicalHost(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_PhysicalHost_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_PhysicalHost_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PhysicalHost_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_PhysicalHost_name(ctx, field)
+			case "type":
+				return ec.fieldContext_PhysicalHost_type(ctx, field)
+			case "availabilityZone":
+				return ec.fieldContext_PhysicalHost_availabilityZone(ctx, field)
+			case "instances":
+				return ec.fieldContext_PhysicalHost_instances(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PhysicalHost", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Instance_volumes(ctx context.Context, field graphql.CollectedField, obj *model.Instance) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Instance_volumes(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Volumes, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Volume)
+	fc.Result = res
+	return ec.marshalNVolume2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐVolumeᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Instance_volumes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Instance",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_Volume_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_Volume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Volume_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_Volume_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Volume_type(ctx, field)
+			case "availabilityZone":
+				return ec.fieldContext_Volume_availabilityZone(ctx, field)
+			case "status":
+				return ec.fieldContext_Volume_status(ctx, field)
+			case "size":
+				return ec.fieldContext_Volume_size(ctx, field)
+			case "bootable":
+				return ec.fieldContext_Volume_bootable(ctx, field)
+			case "encrypted":
+				return ec.fieldContext_Volume_encrypted(ctx, field)
+			case "multiattach":
+				return ec.fieldContext_Volume_multiattach(ctx, field)
+			case "device":
+				return ec.fieldContext_Volume_device(ctx, field)
+			case "srcSnapshot":
+				return ec.fieldContext_Volume_srcSnapshot(ctx, field)
+			case "instances":
+				return ec.fieldContext_Volume_instances(ctx, field)
+			case "persistentVolume":
+				return ec.fieldContext_Volume_persistentVolume(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Volume", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_version(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_version(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Version, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_version(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_scanTimestamp(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_scanTimestamp(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ScanTimestamp, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_scanTimestamp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_status(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_status(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Status, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_status(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_scanStatus(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_scanStatus(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ScanStatus, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_scanStatus(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_complete(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_complete(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Complete, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_complete(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_scanResult(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_scanResult(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ScanResult, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_scanResult(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Metadata_projects(ctx context.Context, field graphql.CollectedField, obj *model.Metadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Metadata_projects(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Projects, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Project)
+	fc.Result = res
+	return ec.marshalNProject2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐProjectᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Metadata_projects(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Metadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_Project_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_Project_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Project_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_Project_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Project_type(ctx, field)
+			case "availabilityZone":
+				return ec.fieldContext_Project_availabilityZone(ctx, field)
+			case "enabled":
 				return ec.fieldContext_Project_enabled(ctx, field)
 			case "description":
 				return ec.fieldContext_Project_description(ctx, field)
@@ -2816,6 +4086,61 @@ func (ec *executionContext) fieldContext_Metadata_projects(ctx context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _Mutation_scanNow(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_scanNow(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().ScanNow(rctx, fc.Args["providers"].([]string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]string)
+	fc.Result = res
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_scanNow(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_scanNow_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _PDIndicator_uuid(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_PDIndicator_uuid(ctx, field)
 	if err != nil {
@@ -2874,7 +4199,136 @@ func (ec *executionContext) _PDIndicator_id(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.ID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PDIndicator_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PDIndicator",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PDIndicator_provider(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PDIndicator_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PDIndicator_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PDIndicator",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PDIndicator_name(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PDIndicator_name(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PDIndicator_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PDIndicator",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PDIndicator_type(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PDIndicator_type(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2891,7 +4345,7 @@ func (ec *executionContext) _PDIndicator_id(ctx context.Context, field graphql.C
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PDIndicator_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PDIndicator_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "PDIndicator",
 		Field:      field,
@@ -2904,8 +4358,8 @@ func (ec *executionContext) fieldContext_PDIndicator_id(ctx context.Context, fie
 	return fc, nil
 }
 
-func (ec *executionContext) _PDIndicator_name(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PDIndicator_name(ctx, field)
+func (ec *executionContext) _PDIndicator_declaredOn(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PDIndicator_declaredOn(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2918,24 +4372,21 @@ func (ec *executionContext) _PDIndicator_name(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.DeclaredOn, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PDIndicator_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PDIndicator_declaredOn(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "PDIndicator",
 		Field:      field,
@@ -2948,8 +4399,8 @@ func (ec *executionContext) fieldContext_PDIndicator_name(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _PDIndicator_type(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PDIndicator_type(ctx, field)
+func (ec *executionContext) _PDIndicator_declaredBy(ctx context.Context, field graphql.CollectedField, obj *model.PDIndicator) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PDIndicator_declaredBy(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2962,24 +4413,21 @@ func (ec *executionContext) _PDIndicator_type(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.DeclaredBy, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PDIndicator_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PDIndicator_declaredBy(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "PDIndicator",
 		Field:      field,
@@ -3091,10 +4539,14 @@ func (ec *executionContext) fieldContext_PDIndicator_pods(ctx context.Context, f
 				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
 				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Pod_createdAt(ctx, field)
 			case "storage":
@@ -3200,6 +4652,47 @@ func (ec *executionContext) fieldContext_PersistentVolume_id(ctx context.Context
 	return fc, nil
 }
 
+func (ec *executionContext) _PersistentVolume_provider(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolume) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PersistentVolume_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PersistentVolume_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PersistentVolume",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _PersistentVolume_name(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolume) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_PersistentVolume_name(ctx, field)
 	if err != nil {
@@ -3332,6 +4825,47 @@ func (ec *executionContext) fieldContext_PersistentVolume_createdAt(ctx context.
 	return fc, nil
 }
 
+func (ec *executionContext) _PersistentVolume_source(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolume) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PersistentVolume_source(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Source, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PersistentVolume_source(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PersistentVolume",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _PersistentVolume_storedVolume(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolume) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_PersistentVolume_storedVolume(ctx, field)
 	if err != nil {
@@ -3375,6 +4909,8 @@ func (ec *executionContext) fieldContext_PersistentVolume_storedVolume(ctx conte
 				return ec.fieldContext_Volume_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Volume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Volume_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Volume_name(ctx, field)
 			case "type":
@@ -3449,6 +4985,8 @@ func (ec *executionContext) fieldContext_PersistentVolume_persistentVolumeClaim(
 				return ec.fieldContext_PersistentVolumeClaim_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PersistentVolumeClaim_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolumeClaim_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PersistentVolumeClaim_name(ctx, field)
 			case "type":
@@ -3552,6 +5090,47 @@ func (ec *executionContext) fieldContext_PersistentVolumeClaim_id(ctx context.Co
 	return fc, nil
 }
 
+func (ec *executionContext) _PersistentVolumeClaim_provider(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolumeClaim) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PersistentVolumeClaim_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PersistentVolumeClaim_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PersistentVolumeClaim",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _PersistentVolumeClaim_name(ctx context.Context, field graphql.CollectedField, obj *model.PersistentVolumeClaim) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_PersistentVolumeClaim_name(ctx, field)
 	if err != nil {
@@ -3683,12 +5262,16 @@ func (ec *executionContext) fieldContext_PersistentVolumeClaim_persistentVolume(
 				return ec.fieldContext_PersistentVolume_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PersistentVolume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolume_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PersistentVolume_name(ctx, field)
 			case "type":
 				return ec.fieldContext_PersistentVolume_type(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_PersistentVolume_createdAt(ctx, field)
+			case "source":
+				return ec.fieldContext_PersistentVolume_source(ctx, field)
 			case "storedVolume":
 				return ec.fieldContext_PersistentVolume_storedVolume(ctx, field)
 			case "persistentVolumeClaim":
@@ -3743,10 +5326,14 @@ func (ec *executionContext) fieldContext_PersistentVolumeClaim_pods(ctx context.
 				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
 				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Pod_createdAt(ctx, field)
 			case "storage":
@@ -3852,6 +5439,47 @@ func (ec *executionContext) fieldContext_PhysicalHost_id(ctx context.Context, fi
 	return fc, nil
 }
 
+func (ec *executionContext) _PhysicalHost_provider(ctx context.Context, field graphql.CollectedField, obj *model.PhysicalHost) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PhysicalHost_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PhysicalHost_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PhysicalHost",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _PhysicalHost_name(ctx context.Context, field graphql.CollectedField, obj *model.PhysicalHost) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_PhysicalHost_name(ctx, field)
 	if err != nil {
@@ -4027,6 +5655,8 @@ func (ec *executionContext) fieldContext_PhysicalHost_instances(ctx context.Cont
 				return ec.fieldContext_Instance_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Instance_name(ctx, field)
 			case "type":
@@ -4047,6 +5677,10 @@ func (ec *executionContext) fieldContext_PhysicalHost_instances(ctx context.Cont
 				return ec.fieldContext_Instance_volumesAttached(ctx, field)
 			case "status":
 				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
 			case "physicalHost":
 				return ec.fieldContext_Instance_physicalHost(ctx, field)
 			case "volumes":
@@ -4116,7 +5750,92 @@ func (ec *executionContext) _Pod_id(ctx context.Context, field graphql.Collected
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.ID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Pod_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Pod",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Pod_provider(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Pod_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Pod_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Pod",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Pod_name(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Pod_name(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4133,7 +5852,7 @@ func (ec *executionContext) _Pod_id(ctx context.Context, field graphql.Collected
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Pod_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Pod_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Pod",
 		Field:      field,
@@ -4146,8 +5865,8 @@ func (ec *executionContext) fieldContext_Pod_id(ctx context.Context, field graph
 	return fc, nil
 }
 
-func (ec *executionContext) _Pod_name(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Pod_name(ctx, field)
+func (ec *executionContext) _Pod_type(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Pod_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4160,7 +5879,7 @@ func (ec *executionContext) _Pod_name(ctx context.Context, field graphql.Collect
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4177,7 +5896,7 @@ func (ec *executionContext) _Pod_name(ctx context.Context, field graphql.Collect
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Pod_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Pod_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Pod",
 		Field:      field,
@@ -4190,8 +5909,8 @@ func (ec *executionContext) fieldContext_Pod_name(ctx context.Context, field gra
 	return fc, nil
 }
 
-func (ec *executionContext) _Pod_type(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Pod_type(ctx, field)
+func (ec *executionContext) _Pod_namespace(ctx context.Context, field graphql.CollectedField, obj *model.Pod) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Pod_namespace(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4204,24 +5923,21 @@ func (ec *executionContext) _Pod_type(ctx context.Context, field graphql.Collect
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.Namespace, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Pod_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Pod_namespace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Pod",
 		Field:      field,
@@ -4365,6 +6081,8 @@ func (ec *executionContext) fieldContext_Pod_clusterNode(ctx context.Context, fi
 				return ec.fieldContext_ClusterNode_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_ClusterNode_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_ClusterNode_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_ClusterNode_name(ctx, field)
 			case "type":
@@ -4425,6 +6143,8 @@ func (ec *executionContext) fieldContext_Pod_persistentVolumeClaims(ctx context.
 				return ec.fieldContext_PersistentVolumeClaim_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PersistentVolumeClaim_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolumeClaim_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PersistentVolumeClaim_name(ctx, field)
 			case "type":
@@ -4483,10 +6203,16 @@ func (ec *executionContext) fieldContext_Pod_pdIndicators(ctx context.Context, f
 				return ec.fieldContext_PDIndicator_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PDIndicator_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PDIndicator_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PDIndicator_name(ctx, field)
 			case "type":
 				return ec.fieldContext_PDIndicator_type(ctx, field)
+			case "declaredOn":
+				return ec.fieldContext_PDIndicator_declaredOn(ctx, field)
+			case "declaredBy":
+				return ec.fieldContext_PDIndicator_declaredBy(ctx, field)
 			case "dataCategories":
 				return ec.fieldContext_PDIndicator_dataCategories(ctx, field)
 			case "pods":
@@ -4586,6 +6312,47 @@ func (ec *executionContext) fieldContext_Project_id(ctx context.Context, field g
 	return fc, nil
 }
 
+func (ec *executionContext) _Project_provider(ctx context.Context, field graphql.CollectedField, obj *model.Project) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Project_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Project_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Project",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Project_name(ctx context.Context, field graphql.CollectedField, obj *model.Project) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_Project_name(ctx, field)
 	if err != nil {
@@ -4849,6 +6616,8 @@ func (ec *executionContext) fieldContext_Project_instances(ctx context.Context,
 				return ec.fieldContext_Instance_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Instance_name(ctx, field)
 			case "type":
@@ -4869,6 +6638,10 @@ func (ec *executionContext) fieldContext_Project_instances(ctx context.Context,
 				return ec.fieldContext_Instance_volumesAttached(ctx, field)
 			case "status":
 				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
 			case "physicalHost":
 				return ec.fieldContext_Instance_physicalHost(ctx, field)
 			case "volumes":
@@ -4894,7 +6667,7 @@ func (ec *executionContext) _Query_getMetadata(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GetMetadata(rctx, fc.Args["version"].(string))
+		return ec.resolvers.Query().GetMetadata(rctx, fc.Args["version"].(*string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4920,6 +6693,14 @@ func (ec *executionContext) fieldContext_Query_getMetadata(ctx context.Context,
 				return ec.fieldContext_Metadata_version(ctx, field)
 			case "scanTimestamp":
 				return ec.fieldContext_Metadata_scanTimestamp(ctx, field)
+			case "status":
+				return ec.fieldContext_Metadata_status(ctx, field)
+			case "scanStatus":
+				return ec.fieldContext_Metadata_scanStatus(ctx, field)
+			case "complete":
+				return ec.fieldContext_Metadata_complete(ctx, field)
+			case "scanResult":
+				return ec.fieldContext_Metadata_scanResult(ctx, field)
 			case "projects":
 				return ec.fieldContext_Metadata_projects(ctx, field)
 			}
@@ -4980,6 +6761,8 @@ func (ec *executionContext) fieldContext_Query_getProject(ctx context.Context, f
 				return ec.fieldContext_Project_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Project_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Project_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Project_name(ctx, field)
 			case "type":
@@ -5050,6 +6833,8 @@ func (ec *executionContext) fieldContext_Query_getInstance(ctx context.Context,
 				return ec.fieldContext_Instance_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Instance_name(ctx, field)
 			case "type":
@@ -5070,6 +6855,10 @@ func (ec *executionContext) fieldContext_Query_getInstance(ctx context.Context,
 				return ec.fieldContext_Instance_volumesAttached(ctx, field)
 			case "status":
 				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
 			case "physicalHost":
 				return ec.fieldContext_Instance_physicalHost(ctx, field)
 			case "volumes":
@@ -5132,6 +6921,8 @@ func (ec *executionContext) fieldContext_Query_getVolume(ctx context.Context, fi
 				return ec.fieldContext_Volume_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Volume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Volume_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Volume_name(ctx, field)
 			case "type":
@@ -5214,6 +7005,8 @@ func (ec *executionContext) fieldContext_Query_getPhysicalHost(ctx context.Conte
 				return ec.fieldContext_PhysicalHost_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PhysicalHost_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PhysicalHost_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PhysicalHost_name(ctx, field)
 			case "type":
@@ -5280,6 +7073,8 @@ func (ec *executionContext) fieldContext_Query_getClusterNode(ctx context.Contex
 				return ec.fieldContext_ClusterNode_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_ClusterNode_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_ClusterNode_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_ClusterNode_name(ctx, field)
 			case "type":
@@ -5348,10 +7143,14 @@ func (ec *executionContext) fieldContext_Query_getPod(ctx context.Context, field
 				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
 				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Pod_createdAt(ctx, field)
 			case "storage":
@@ -5420,18 +7219,226 @@ func (ec *executionContext) fieldContext_Query_getPersistentVolume(ctx context.C
 				return ec.fieldContext_PersistentVolume_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PersistentVolume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolume_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_PersistentVolume_name(ctx, field)
+			case "type":
+				return ec.fieldContext_PersistentVolume_type(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_PersistentVolume_createdAt(ctx, field)
+			case "source":
+				return ec.fieldContext_PersistentVolume_source(ctx, field)
+			case "storedVolume":
+				return ec.fieldContext_PersistentVolume_storedVolume(ctx, field)
+			case "persistentVolumeClaim":
+				return ec.fieldContext_PersistentVolume_persistentVolumeClaim(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PersistentVolume", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_getPersistentVolume_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_getPersistentVolumeClaim(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getPersistentVolumeClaim(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().GetPersistentVolumeClaim(rctx, fc.Args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.PersistentVolumeClaim)
+	fc.Result = res
+	return ec.marshalOPersistentVolumeClaim2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPersistentVolumeClaim(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_getPersistentVolumeClaim(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_PersistentVolumeClaim_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_PersistentVolumeClaim_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolumeClaim_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_PersistentVolumeClaim_name(ctx, field)
+			case "type":
+				return ec.fieldContext_PersistentVolumeClaim_type(ctx, field)
+			case "persistentVolume":
+				return ec.fieldContext_PersistentVolumeClaim_persistentVolume(ctx, field)
+			case "pods":
+				return ec.fieldContext_PersistentVolumeClaim_pods(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PersistentVolumeClaim", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_getPersistentVolumeClaim_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_getPDIndicator(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getPDIndicator(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().GetPDIndicator(rctx, fc.Args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.PDIndicator)
+	fc.Result = res
+	return ec.marshalOPDIndicator2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPDIndicator(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_getPDIndicator(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_PDIndicator_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_PDIndicator_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PDIndicator_provider(ctx, field)
+			case "name":
+				return ec.fieldContext_PDIndicator_name(ctx, field)
+			case "type":
+				return ec.fieldContext_PDIndicator_type(ctx, field)
+			case "declaredOn":
+				return ec.fieldContext_PDIndicator_declaredOn(ctx, field)
+			case "declaredBy":
+				return ec.fieldContext_PDIndicator_declaredBy(ctx, field)
+			case "dataCategories":
+				return ec.fieldContext_PDIndicator_dataCategories(ctx, field)
+			case "pods":
+				return ec.fieldContext_PDIndicator_pods(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PDIndicator", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_getPDIndicator_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_getDataCategory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getDataCategory(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().GetDataCategory(rctx, fc.Args["name"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.DataCategory)
+	fc.Result = res
+	return ec.marshalODataCategory2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐDataCategory(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_getDataCategory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
 			case "name":
-				return ec.fieldContext_PersistentVolume_name(ctx, field)
-			case "type":
-				return ec.fieldContext_PersistentVolume_type(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_PersistentVolume_createdAt(ctx, field)
-			case "storedVolume":
-				return ec.fieldContext_PersistentVolume_storedVolume(ctx, field)
-			case "persistentVolumeClaim":
-				return ec.fieldContext_PersistentVolume_persistentVolumeClaim(ctx, field)
+				return ec.fieldContext_DataCategory_name(ctx, field)
+			case "purpose":
+				return ec.fieldContext_DataCategory_purpose(ctx, field)
+			case "legalBasis":
+				return ec.fieldContext_DataCategory_legalBasis(ctx, field)
+			case "storage":
+				return ec.fieldContext_DataCategory_storage(ctx, field)
+			case "pdIndicators":
+				return ec.fieldContext_DataCategory_pdIndicators(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PersistentVolume", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type DataCategory", field.Name)
 		},
 	}
 	defer func() {
@@ -5441,15 +7448,15 @@ func (ec *executionContext) fieldContext_Query_getPersistentVolume(ctx context.C
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_getPersistentVolume_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_getDataCategory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_getPersistentVolumeClaim(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_getPersistentVolumeClaim(ctx, field)
+func (ec *executionContext) _Query_getPdsWithCategory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getPdsWithCategory(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5462,7 +7469,7 @@ func (ec *executionContext) _Query_getPersistentVolumeClaim(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GetPersistentVolumeClaim(rctx, fc.Args["id"].(string))
+		return ec.resolvers.Query().GetPdsWithCategory(rctx, fc.Args["version"].(*string), fc.Args["categoryName"].(string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5471,12 +7478,12 @@ func (ec *executionContext) _Query_getPersistentVolumeClaim(ctx context.Context,
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*model.PersistentVolumeClaim)
+	res := resTmp.([]*model.Pod)
 	fc.Result = res
-	return ec.marshalOPersistentVolumeClaim2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPersistentVolumeClaim(ctx, field.Selections, res)
+	return ec.marshalOPod2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPod(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_getPersistentVolumeClaim(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_getPdsWithCategory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -5485,19 +7492,29 @@ func (ec *executionContext) fieldContext_Query_getPersistentVolumeClaim(ctx cont
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "uuid":
-				return ec.fieldContext_PersistentVolumeClaim_uuid(ctx, field)
+				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
-				return ec.fieldContext_PersistentVolumeClaim_id(ctx, field)
+				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
-				return ec.fieldContext_PersistentVolumeClaim_name(ctx, field)
+				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
-				return ec.fieldContext_PersistentVolumeClaim_type(ctx, field)
-			case "persistentVolume":
-				return ec.fieldContext_PersistentVolumeClaim_persistentVolume(ctx, field)
-			case "pods":
-				return ec.fieldContext_PersistentVolumeClaim_pods(ctx, field)
+				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Pod_createdAt(ctx, field)
+			case "storage":
+				return ec.fieldContext_Pod_storage(ctx, field)
+			case "clusterNode":
+				return ec.fieldContext_Pod_clusterNode(ctx, field)
+			case "persistentVolumeClaims":
+				return ec.fieldContext_Pod_persistentVolumeClaims(ctx, field)
+			case "pdIndicators":
+				return ec.fieldContext_Pod_pdIndicators(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PersistentVolumeClaim", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Pod", field.Name)
 		},
 	}
 	defer func() {
@@ -5507,15 +7524,15 @@ func (ec *executionContext) fieldContext_Query_getPersistentVolumeClaim(ctx cont
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_getPersistentVolumeClaim_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_getPdsWithCategory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_getPDIndicator(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_getPDIndicator(ctx, field)
+func (ec *executionContext) _Query_getExposedPdsWithCategory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getExposedPdsWithCategory(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5528,7 +7545,7 @@ func (ec *executionContext) _Query_getPDIndicator(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GetPDIndicator(rctx, fc.Args["id"].(string))
+		return ec.resolvers.Query().GetExposedPdsWithCategory(rctx, fc.Args["version"].(*string), fc.Args["categoryName"].(string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5537,12 +7554,12 @@ func (ec *executionContext) _Query_getPDIndicator(ctx context.Context, field gra
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*model.PDIndicator)
+	res := resTmp.([]*model.Pod)
 	fc.Result = res
-	return ec.marshalOPDIndicator2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPDIndicator(ctx, field.Selections, res)
+	return ec.marshalOPod2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPod(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_getPDIndicator(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_getExposedPdsWithCategory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -5551,19 +7568,29 @@ func (ec *executionContext) fieldContext_Query_getPDIndicator(ctx context.Contex
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "uuid":
-				return ec.fieldContext_PDIndicator_uuid(ctx, field)
+				return ec.fieldContext_Pod_uuid(ctx, field)
 			case "id":
-				return ec.fieldContext_PDIndicator_id(ctx, field)
+				return ec.fieldContext_Pod_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Pod_provider(ctx, field)
 			case "name":
-				return ec.fieldContext_PDIndicator_name(ctx, field)
+				return ec.fieldContext_Pod_name(ctx, field)
 			case "type":
-				return ec.fieldContext_PDIndicator_type(ctx, field)
-			case "dataCategories":
-				return ec.fieldContext_PDIndicator_dataCategories(ctx, field)
-			case "pods":
-				return ec.fieldContext_PDIndicator_pods(ctx, field)
+				return ec.fieldContext_Pod_type(ctx, field)
+			case "namespace":
+				return ec.fieldContext_Pod_namespace(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Pod_createdAt(ctx, field)
+			case "storage":
+				return ec.fieldContext_Pod_storage(ctx, field)
+			case "clusterNode":
+				return ec.fieldContext_Pod_clusterNode(ctx, field)
+			case "persistentVolumeClaims":
+				return ec.fieldContext_Pod_persistentVolumeClaims(ctx, field)
+			case "pdIndicators":
+				return ec.fieldContext_Pod_pdIndicators(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PDIndicator", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Pod", field.Name)
 		},
 	}
 	defer func() {
@@ -5573,15 +7600,15 @@ func (ec *executionContext) fieldContext_Query_getPDIndicator(ctx context.Contex
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_getPDIndicator_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_getExposedPdsWithCategory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_getDataCategory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_getDataCategory(ctx, field)
+func (ec *executionContext) _Query_getPublicInstancesWithPds(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getPublicInstancesWithPds(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5594,7 +7621,7 @@ func (ec *executionContext) _Query_getDataCategory(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GetDataCategory(rctx, fc.Args["name"].(string))
+		return ec.resolvers.Query().GetPublicInstancesWithPds(rctx, fc.Args["version"].(*string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5603,12 +7630,12 @@ func (ec *executionContext) _Query_getDataCategory(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*model.DataCategory)
+	res := resTmp.([]*model.Instance)
 	fc.Result = res
-	return ec.marshalODataCategory2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐDataCategory(ctx, field.Selections, res)
+	return ec.marshalOInstance2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐInstance(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_getDataCategory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_getPublicInstancesWithPds(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -5616,18 +7643,42 @@ func (ec *executionContext) fieldContext_Query_getDataCategory(ctx context.Conte
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
+			case "uuid":
+				return ec.fieldContext_Instance_uuid(ctx, field)
+			case "id":
+				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
-				return ec.fieldContext_DataCategory_name(ctx, field)
-			case "purpose":
-				return ec.fieldContext_DataCategory_purpose(ctx, field)
-			case "legalBasis":
-				return ec.fieldContext_DataCategory_legalBasis(ctx, field)
-			case "storage":
-				return ec.fieldContext_DataCategory_storage(ctx, field)
-			case "pdIndicators":
-				return ec.fieldContext_DataCategory_pdIndicators(ctx, field)
+				return ec.fieldContext_Instance_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Instance_type(ctx, field)
+			case "availabilityZone":
+				return ec.fieldContext_Instance_availabilityZone(ctx, field)
+			case "userID":
+				return ec.fieldContext_Instance_userID(ctx, field)
+			case "hostID":
+				return ec.fieldContext_Instance_hostID(ctx, field)
+			case "tenantID":
+				return ec.fieldContext_Instance_tenantID(ctx, field)
+			case "created":
+				return ec.fieldContext_Instance_created(ctx, field)
+			case "updated":
+				return ec.fieldContext_Instance_updated(ctx, field)
+			case "volumesAttached":
+				return ec.fieldContext_Instance_volumesAttached(ctx, field)
+			case "status":
+				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
+			case "physicalHost":
+				return ec.fieldContext_Instance_physicalHost(ctx, field)
+			case "volumes":
+				return ec.fieldContext_Instance_volumes(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type DataCategory", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Instance", field.Name)
 		},
 	}
 	defer func() {
@@ -5637,15 +7688,15 @@ func (ec *executionContext) fieldContext_Query_getDataCategory(ctx context.Conte
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_getDataCategory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_getPublicInstancesWithPds_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_getPdsWithCategory(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_getPdsWithCategory(ctx, field)
+func (ec *executionContext) _Query_getFindings(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_getFindings(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5658,7 +7709,7 @@ func (ec *executionContext) _Query_getPdsWithCategory(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GetPdsWithCategory(rctx, fc.Args["version"].(string), fc.Args["categoryName"].(string))
+		return ec.resolvers.Query().GetFindings(rctx, fc.Args["version"].(*string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5667,12 +7718,12 @@ func (ec *executionContext) _Query_getPdsWithCategory(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*model.Pod)
+	res := resTmp.([]*model.Finding)
 	fc.Result = res
-	return ec.marshalOPod2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐPod(ctx, field.Selections, res)
+	return ec.marshalOFinding2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐFinding(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_getPdsWithCategory(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_getFindings(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -5681,25 +7732,29 @@ func (ec *executionContext) fieldContext_Query_getPdsWithCategory(ctx context.Co
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "uuid":
-				return ec.fieldContext_Pod_uuid(ctx, field)
+				return ec.fieldContext_Finding_uuid(ctx, field)
 			case "id":
-				return ec.fieldContext_Pod_id(ctx, field)
+				return ec.fieldContext_Finding_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Finding_provider(ctx, field)
 			case "name":
-				return ec.fieldContext_Pod_name(ctx, field)
+				return ec.fieldContext_Finding_name(ctx, field)
 			case "type":
-				return ec.fieldContext_Pod_type(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Pod_createdAt(ctx, field)
-			case "storage":
-				return ec.fieldContext_Pod_storage(ctx, field)
-			case "clusterNode":
-				return ec.fieldContext_Pod_clusterNode(ctx, field)
-			case "persistentVolumeClaims":
-				return ec.fieldContext_Pod_persistentVolumeClaims(ctx, field)
-			case "pdIndicators":
-				return ec.fieldContext_Pod_pdIndicators(ctx, field)
+				return ec.fieldContext_Finding_type(ctx, field)
+			case "kind":
+				return ec.fieldContext_Finding_kind(ctx, field)
+			case "schemaVersion":
+				return ec.fieldContext_Finding_schemaVersion(ctx, field)
+			case "violations":
+				return ec.fieldContext_Finding_violations(ctx, field)
+			case "annotation":
+				return ec.fieldContext_Finding_annotation(ctx, field)
+			case "subject":
+				return ec.fieldContext_Finding_subject(ctx, field)
+			case "subjectType":
+				return ec.fieldContext_Finding_subjectType(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Pod", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Finding", field.Name)
 		},
 	}
 	defer func() {
@@ -5709,7 +7764,7 @@ func (ec *executionContext) fieldContext_Query_getPdsWithCategory(ctx context.Co
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_getPdsWithCategory_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_getFindings_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
@@ -5933,6 +7988,47 @@ func (ec *executionContext) fieldContext_Volume_id(ctx context.Context, field gr
 	return fc, nil
 }
 
+func (ec *executionContext) _Volume_provider(ctx context.Context, field graphql.CollectedField, obj *model.Volume) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Volume_provider(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Provider, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Volume_provider(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Volume",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Volume_name(ctx context.Context, field graphql.CollectedField, obj *model.Volume) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_Volume_name(ctx, field)
 	if err != nil {
@@ -6416,6 +8512,8 @@ func (ec *executionContext) fieldContext_Volume_instances(ctx context.Context, f
 				return ec.fieldContext_Instance_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_Instance_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_Instance_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_Instance_name(ctx, field)
 			case "type":
@@ -6436,6 +8534,10 @@ func (ec *executionContext) fieldContext_Volume_instances(ctx context.Context, f
 				return ec.fieldContext_Instance_volumesAttached(ctx, field)
 			case "status":
 				return ec.fieldContext_Instance_status(ctx, field)
+			case "vpcID":
+				return ec.fieldContext_Instance_vpcID(ctx, field)
+			case "subnetID":
+				return ec.fieldContext_Instance_subnetID(ctx, field)
 			case "physicalHost":
 				return ec.fieldContext_Instance_physicalHost(ctx, field)
 			case "volumes":
@@ -6487,12 +8589,16 @@ func (ec *executionContext) fieldContext_Volume_persistentVolume(ctx context.Con
 				return ec.fieldContext_PersistentVolume_uuid(ctx, field)
 			case "id":
 				return ec.fieldContext_PersistentVolume_id(ctx, field)
+			case "provider":
+				return ec.fieldContext_PersistentVolume_provider(ctx, field)
 			case "name":
 				return ec.fieldContext_PersistentVolume_name(ctx, field)
 			case "type":
 				return ec.fieldContext_PersistentVolume_type(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_PersistentVolume_createdAt(ctx, field)
+			case "source":
+				return ec.fieldContext_PersistentVolume_source(ctx, field)
 			case "storedVolume":
 				return ec.fieldContext_PersistentVolume_storedVolume(ctx, field)
 			case "persistentVolumeClaim":
@@ -8306,6 +10412,8 @@ func (ec *executionContext) _ClusterNode(ctx context.Context, sel ast.SelectionS
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._ClusterNode_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._ClusterNode_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8316,18 +10424,77 @@ func (ec *executionContext) _ClusterNode(ctx context.Context, sel ast.SelectionS
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "createdAt":
-			out.Values[i] = ec._ClusterNode_createdAt(ctx, field, obj)
+		case "createdAt":
+			out.Values[i] = ec._ClusterNode_createdAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "provisionedInstance":
+			out.Values[i] = ec._ClusterNode_provisionedInstance(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "pods":
+			out.Values[i] = ec._ClusterNode_pods(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var dataCategoryImplementors = []string{"DataCategory"}
+
+func (ec *executionContext) _DataCategory(ctx context.Context, sel ast.SelectionSet, obj *model.DataCategory) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, dataCategoryImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DataCategory")
+		case "name":
+			out.Values[i] = ec._DataCategory_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "purpose":
+			out.Values[i] = ec._DataCategory_purpose(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "legalBasis":
+			out.Values[i] = ec._DataCategory_legalBasis(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "provisionedInstance":
-			out.Values[i] = ec._ClusterNode_provisionedInstance(ctx, field, obj)
+		case "storage":
+			out.Values[i] = ec._DataCategory_storage(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "pods":
-			out.Values[i] = ec._ClusterNode_pods(ctx, field, obj)
+		case "pdIndicators":
+			out.Values[i] = ec._DataCategory_pdIndicators(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
@@ -8354,39 +10521,60 @@ func (ec *executionContext) _ClusterNode(ctx context.Context, sel ast.SelectionS
 	return out
 }
 
-var dataCategoryImplementors = []string{"DataCategory"}
+var findingImplementors = []string{"Finding"}
 
-func (ec *executionContext) _DataCategory(ctx context.Context, sel ast.SelectionSet, obj *model.DataCategory) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, dataCategoryImplementors)
+func (ec *executionContext) _Finding(ctx context.Context, sel ast.SelectionSet, obj *model.Finding) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, findingImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("DataCategory")
+			out.Values[i] = graphql.MarshalString("Finding")
+		case "uuid":
+			out.Values[i] = ec._Finding_uuid(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "id":
+			out.Values[i] = ec._Finding_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "provider":
+			out.Values[i] = ec._Finding_provider(ctx, field, obj)
 		case "name":
-			out.Values[i] = ec._DataCategory_name(ctx, field, obj)
+			out.Values[i] = ec._Finding_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "purpose":
-			out.Values[i] = ec._DataCategory_purpose(ctx, field, obj)
+		case "type":
+			out.Values[i] = ec._Finding_type(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "legalBasis":
-			out.Values[i] = ec._DataCategory_legalBasis(ctx, field, obj)
+		case "kind":
+			out.Values[i] = ec._Finding_kind(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "storage":
-			out.Values[i] = ec._DataCategory_storage(ctx, field, obj)
+		case "schemaVersion":
+			out.Values[i] = ec._Finding_schemaVersion(ctx, field, obj)
+		case "violations":
+			out.Values[i] = ec._Finding_violations(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "pdIndicators":
-			out.Values[i] = ec._DataCategory_pdIndicators(ctx, field, obj)
+		case "annotation":
+			out.Values[i] = ec._Finding_annotation(ctx, field, obj)
+		case "subject":
+			out.Values[i] = ec._Finding_subject(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "subjectType":
+			out.Values[i] = ec._Finding_subjectType(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
@@ -8434,6 +10622,8 @@ func (ec *executionContext) _Instance(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._Instance_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._Instance_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8484,6 +10674,10 @@ func (ec *executionContext) _Instance(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "vpcID":
+			out.Values[i] = ec._Instance_vpcID(ctx, field, obj)
+		case "subnetID":
+			out.Values[i] = ec._Instance_subnetID(ctx, field, obj)
 		case "physicalHost":
 			out.Values[i] = ec._Instance_physicalHost(ctx, field, obj)
 		case "volumes":
@@ -8535,6 +10729,17 @@ func (ec *executionContext) _Metadata(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "status":
+			out.Values[i] = ec._Metadata_status(ctx, field, obj)
+		case "scanStatus":
+			out.Values[i] = ec._Metadata_scanStatus(ctx, field, obj)
+		case "complete":
+			out.Values[i] = ec._Metadata_complete(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "scanResult":
+			out.Values[i] = ec._Metadata_scanResult(ctx, field, obj)
 		case "projects":
 			out.Values[i] = ec._Metadata_projects(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8563,6 +10768,55 @@ func (ec *executionContext) _Metadata(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
+var mutationImplementors = []string{"Mutation"}
+
+func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Mutation",
+	})
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		innerCtx := graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{
+			Object: field.Name,
+			Field:  field,
+		})
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Mutation")
+		case "scanNow":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_scanNow(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var pDIndicatorImplementors = []string{"PDIndicator"}
 
 func (ec *executionContext) _PDIndicator(ctx context.Context, sel ast.SelectionSet, obj *model.PDIndicator) graphql.Marshaler {
@@ -8584,6 +10838,8 @@ func (ec *executionContext) _PDIndicator(ctx context.Context, sel ast.SelectionS
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._PDIndicator_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._PDIndicator_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8594,6 +10850,10 @@ func (ec *executionContext) _PDIndicator(ctx context.Context, sel ast.SelectionS
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "declaredOn":
+			out.Values[i] = ec._PDIndicator_declaredOn(ctx, field, obj)
+		case "declaredBy":
+			out.Values[i] = ec._PDIndicator_declaredBy(ctx, field, obj)
 		case "dataCategories":
 			out.Values[i] = ec._PDIndicator_dataCategories(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8648,6 +10908,8 @@ func (ec *executionContext) _PersistentVolume(ctx context.Context, sel ast.Selec
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._PersistentVolume_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._PersistentVolume_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8663,6 +10925,8 @@ func (ec *executionContext) _PersistentVolume(ctx context.Context, sel ast.Selec
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "source":
+			out.Values[i] = ec._PersistentVolume_source(ctx, field, obj)
 		case "storedVolume":
 			out.Values[i] = ec._PersistentVolume_storedVolume(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8717,6 +10981,8 @@ func (ec *executionContext) _PersistentVolumeClaim(ctx context.Context, sel ast.
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._PersistentVolumeClaim_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._PersistentVolumeClaim_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8781,6 +11047,8 @@ func (ec *executionContext) _PhysicalHost(ctx context.Context, sel ast.Selection
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._PhysicalHost_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._PhysicalHost_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8845,6 +11113,8 @@ func (ec *executionContext) _Pod(ctx context.Context, sel ast.SelectionSet, obj
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._Pod_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._Pod_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8855,6 +11125,8 @@ func (ec *executionContext) _Pod(ctx context.Context, sel ast.SelectionSet, obj
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "namespace":
+			out.Values[i] = ec._Pod_namespace(ctx, field, obj)
 		case "createdAt":
 			out.Values[i] = ec._Pod_createdAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -8924,6 +11196,8 @@ func (ec *executionContext) _Project(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._Project_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._Project_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -9223,6 +11497,63 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "getExposedPdsWithCategory":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_getExposedPdsWithCategory(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "getPublicInstancesWithPds":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_getPublicInstancesWithPds(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "getFindings":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_getFindings(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -9276,6 +11607,8 @@ func (ec *executionContext) _Volume(ctx context.Context, sel ast.SelectionSet, o
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "provider":
+			out.Values[i] = ec._Volume_provider(ctx, field, obj)
 		case "name":
 			out.Values[i] = ec._Volume_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -10450,6 +12783,95 @@ func (ec *executionContext) marshalODataCategory2ᚖgithubᚗcomᚋregulatoryᚑ
 	return ec._DataCategory(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOFinding2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐFinding(ctx context.Context, sel ast.SelectionSet, v []*model.Finding) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOFinding2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐFinding(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalOFinding2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐFinding(ctx context.Context, sel ast.SelectionSet, v *model.Finding) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Finding(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalOInstance2ᚕᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐInstance(ctx context.Context, sel ast.SelectionSet, v []*model.Instance) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOInstance2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐInstance(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
 func (ec *executionContext) marshalOInstance2ᚖgithubᚗcomᚋregulatoryᚑtransparencyᚑmonitorᚋgraphᚑbuilderᚋgraphᚋmodelᚐInstance(ctx context.Context, sel ast.SelectionSet, v *model.Instance) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -10547,6 +12969,44 @@ func (ec *executionContext) marshalOProject2ᚖgithubᚗcomᚋregulatoryᚑtrans
 	return ec._Project(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalOString2ᚕstringᚄ(ctx context.Context, v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNString2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalOString2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalOString2ᚖstring(ctx context.Context, v interface{}) (*string, error) {
 	if v == nil {
 		return nil, nil
@@ -5,6 +5,7 @@ package model
 type ClusterNode struct {
 	UUID                string    `json:"uuid"`
 	ID                  string    `json:"id"`
+	Provider            *string   `json:"provider,omitempty"`
 	Name                string    `json:"name"`
 	Type                string    `json:"type"`
 	CreatedAt           string    `json:"createdAt"`
@@ -20,9 +21,24 @@ type DataCategory struct {
 	PdIndicators []*PDIndicator `json:"pdIndicators"`
 }
 
+type Finding struct {
+	UUID          string   `json:"uuid"`
+	ID            string   `json:"id"`
+	Provider      *string  `json:"provider,omitempty"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Kind          string   `json:"kind"`
+	SchemaVersion *string  `json:"schemaVersion,omitempty"`
+	Violations    []string `json:"violations"`
+	Annotation    *string  `json:"annotation,omitempty"`
+	Subject       string   `json:"subject"`
+	SubjectType   string   `json:"subjectType"`
+}
+
 type Instance struct {
 	UUID             string        `json:"uuid"`
 	ID               string        `json:"id"`
+	Provider         *string       `json:"provider,omitempty"`
 	Name             string        `json:"name"`
 	Type             string        `json:"type"`
 	AvailabilityZone string        `json:"availabilityZone"`
@@ -33,6 +49,8 @@ type Instance struct {
 	Updated          string        `json:"updated"`
 	VolumesAttached  []string      `json:"volumesAttached"`
 	Status           string        `json:"status"`
+	VpcID            *string       `json:"vpcID,omitempty"`
+	SubnetID         *string       `json:"subnetID,omitempty"`
 	PhysicalHost     *PhysicalHost `json:"physicalHost,omitempty"`
 	Volumes          []*Volume     `json:"volumes"`
 }
@@ -40,14 +58,21 @@ type Instance struct {
 type Metadata struct {
 	Version       string     `json:"version"`
 	ScanTimestamp string     `json:"scanTimestamp"`
+	Status        *string    `json:"status,omitempty"`
+	ScanStatus    *string    `json:"scanStatus,omitempty"`
+	Complete      bool       `json:"complete"`
+	ScanResult    *string    `json:"scanResult,omitempty"`
 	Projects      []*Project `json:"projects"`
 }
 
 type PDIndicator struct {
 	UUID           string          `json:"uuid"`
 	ID             string          `json:"id"`
+	Provider       *string         `json:"provider,omitempty"`
 	Name           string          `json:"name"`
 	Type           string          `json:"type"`
+	DeclaredOn     *string         `json:"declaredOn,omitempty"`
+	DeclaredBy     *string         `json:"declaredBy,omitempty"`
 	DataCategories []*DataCategory `json:"dataCategories"`
 	Pods           []*Pod          `json:"pods"`
 }
@@ -55,9 +80,11 @@ type PDIndicator struct {
 type PersistentVolume struct {
 	UUID                  string                 `json:"uuid"`
 	ID                    string                 `json:"id"`
+	Provider              *string                `json:"provider,omitempty"`
 	Name                  string                 `json:"name"`
 	Type                  string                 `json:"type"`
 	CreatedAt             string                 `json:"createdAt"`
+	Source                *string                `json:"source,omitempty"`
 	StoredVolume          *Volume                `json:"storedVolume"`
 	PersistentVolumeClaim *PersistentVolumeClaim `json:"persistentVolumeClaim"`
 }
@@ -65,6 +92,7 @@ type PersistentVolume struct {
 type PersistentVolumeClaim struct {
 	UUID             string            `json:"uuid"`
 	ID               string            `json:"id"`
+	Provider         *string           `json:"provider,omitempty"`
 	Name             string            `json:"name"`
 	Type             string            `json:"type"`
 	PersistentVolume *PersistentVolume `json:"persistentVolume"`
@@ -74,6 +102,7 @@ type PersistentVolumeClaim struct {
 type PhysicalHost struct {
 	UUID             string      `json:"uuid"`
 	ID               string      `json:"id"`
+	Provider         *string     `json:"provider,omitempty"`
 	Name             string      `json:"name"`
 	Type             string      `json:"type"`
 	AvailabilityZone string      `json:"availabilityZone"`
@@ -83,8 +112,10 @@ type PhysicalHost struct {
 type Pod struct {
 	UUID                   string                   `json:"uuid"`
 	ID                     string                   `json:"id"`
+	Provider               *string                  `json:"provider,omitempty"`
 	Name                   string                   `json:"name"`
 	Type                   string                   `json:"type"`
+	Namespace              *string                  `json:"namespace,omitempty"`
 	CreatedAt              string                   `json:"createdAt"`
 	Storage                string                   `json:"storage"`
 	ClusterNode            *ClusterNode             `json:"clusterNode"`
@@ -95,6 +126,7 @@ type Pod struct {
 type Project struct {
 	UUID             string      `json:"uuid"`
 	ID               string      `json:"id"`
+	Provider         *string     `json:"provider,omitempty"`
 	Name             string      `json:"name"`
 	Type             string      `json:"type"`
 	AvailabilityZone string      `json:"availabilityZone"`
@@ -106,6 +138,7 @@ type Project struct {
 type Volume struct {
 	UUID             string            `json:"uuid"`
 	ID               string            `json:"id"`
+	Provider         *string           `json:"provider,omitempty"`
 	Name             string            `json:"name"`
 	Type             string            `json:"type"`
 	AvailabilityZone string            `json:"availabilityZone"`
@@ -1,6 +1,9 @@
 package graph
 
-import service "github.com/regulatory-transparency-monitor/graph-builder/internal/service"
+import (
+	"github.com/regulatory-transparency-monitor/graph-builder/internal/manager"
+	service "github.com/regulatory-transparency-monitor/graph-builder/internal/service"
+)
 
 //go:generate go run github.com/99designs/gqlgen generate
 // This file will not be regenerated automatically.
@@ -9,4 +12,5 @@ import service "github.com/regulatory-transparency-monitor/graph-builder/interna
 
 type Resolver struct {
 	Service *service.Service
+	Manager *manager.Manager
 }
@@ -2,7 +2,7 @@ package graph
 
 // This file will be automatically regenerated based on the schema, any resolver implementations
 // will be copied through when generating and any unknown code will be moved to the end.
-// Code generated by github.com/99designs/gqlgen version v0.17.36
+// Code generated by github.com/99designs/gqlgen version v0.17.39
 
 import (
 	"context"
@@ -12,9 +12,14 @@ import (
 	"github.com/regulatory-transparency-monitor/graph-builder/graph/model"
 )
 
+// ScanNow is the resolver for the scanNow field.
+func (r *mutationResolver) ScanNow(ctx context.Context, providers []string) ([]string, error) {
+	return r.Manager.ScanNow(providers)
+}
+
 // GetMetadata is the resolver for the getMetadata field.
-func (r *queryResolver) GetMetadata(ctx context.Context, version string) (*model.Metadata, error) {
-	panic(fmt.Errorf("not implemented: GetMetadata - getMetadata"))
+func (r *queryResolver) GetMetadata(ctx context.Context, version *string) (*model.Metadata, error) {
+	return r.Service.GetMetadata(ctx, version)
 }
 
 // GetProject is the resolver for the getProject field.
@@ -68,11 +73,30 @@ func (r *queryResolver) GetDataCategory(ctx context.Context, name string) (*mode
 }
 
 // GetPdsWithCategory is the resolver for the getPdsWithCategory field.
-func (r *queryResolver) GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) {
+func (r *queryResolver) GetPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error) {
 	return r.Service.GetPdsWithCategory(ctx, version, categoryName)
 }
 
+// GetExposedPdsWithCategory is the resolver for the getExposedPdsWithCategory field.
+func (r *queryResolver) GetExposedPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error) {
+	return r.Service.GetExposedPdsWithCategory(ctx, version, categoryName)
+}
+
+// GetPublicInstancesWithPds is the resolver for the getPublicInstancesWithPds field.
+func (r *queryResolver) GetPublicInstancesWithPds(ctx context.Context, version *string) ([]*model.Instance, error) {
+	return r.Service.GetPublicInstancesWithPds(ctx, version)
+}
+
+// GetFindings is the resolver for the getFindings field.
+func (r *queryResolver) GetFindings(ctx context.Context, version *string) ([]*model.Finding, error) {
+	return r.Service.GetFindings(ctx, version)
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
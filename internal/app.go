@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
@@ -42,14 +44,24 @@ func Init() *App {
 		os.Exit(1)
 	}
 
+	storage := viper.GetString("NEO4J_STORAGE")
+	if storage != repository.StorageVersioned && storage != repository.StorageTemporal {
+		logger.Fatal(fmt.Sprintf("Unknown NEO4J_STORAGE %q, expected versioned or temporal", storage))
+	}
 	r := &repository.Neo4jRepository{
 		Connection: neo4Conn,
+		BatchSize:  viper.GetInt("NEO4J_BATCH_SIZE"),
+		Storage:    storage,
 	}
 
 	// 3) Instantiate Service
 	srv := service.NewService(r)
 
 	// 4) Instantiate mngrestrator
+	err = dataparser.LoadMappings(viper.GetString("MAPPINGS_DIR"))
+	if err != nil {
+		logger.Error("Loading mappings failure: ", err)
+	}
 	tf := dataparser.TransformerRegistry
 	mngr := manager.NewManager(tf, srv)
 	err = mngr.Start()
@@ -67,6 +79,17 @@ func (a *App) Run() {
 	host := viper.GetString("SERVER_IP")
 	port := viper.GetString("API_PORT")
 	addrStr := fmt.Sprintf("%s:%s", host, port)
+	// Cancel running scans on shutdown
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		logger.Info("Shutting down, cancelling running scans")
+		// Stop returns once a running scan has finished its version
+		a.Manager.Stop()
+		os.Exit(0)
+	}()
+
 	logger.Info("GraphQL API Listening to http://", addrStr)
 	logger.Fatal("Service failure", http.ListenAndServe(addrStr, a.Router))
 
@@ -78,7 +101,8 @@ func (a *App) InitRoutes() {
 
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: &graph.Resolver{
-			Service: a.Service}}))
+			Service: a.Service,
+			Manager: a.Manager}}))
 	a.Router.Handle("/playground", playground.Handler("GoNeo4jGql GraphQL playground", "/instance"))
 	a.Router.Handle("/instance", srv)
 
@@ -1,14 +1,18 @@
 package manager
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/regulatory-transparency-monitor/commons/models"
 	services "github.com/regulatory-transparency-monitor/graph-builder/internal/service"
 	"github.com/regulatory-transparency-monitor/graph-builder/internal/versioning"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+	"github.com/spf13/viper"
 )
 
 type Manager struct {
@@ -17,26 +21,57 @@ type Manager struct {
 	VersionManager *versioning.VersionManager
 	Scheduler      *Scheduler
 	PluginManager  *plugin.PluginManager
+
+	// ctx is cancelled by Stop to abort in-flight plugin scans
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// latest holds the last successful fetch of every provider, providers
+	// that aren't due are stored again from here so each version covers all
+	// providers. It is only accessed from scans, which the Scheduler never
+	// overlaps.
+	latest map[string]plugin.ProviderData
 }
 
+// Scan triggers
+const (
+	TriggerInitial  = "initial"
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
 func NewManager(tf map[string]dataparser.Transformer, srv *services.Service) *Manager {
-	version, err := srv.GetLatestVersion()
+	// Versions of failed scans are never reused, the next scan gets a new one
+	version, err := srv.GetLastVersion()
+	vm := versioning.NewVersionManager(version)
 	if err != nil {
 		logger.Warning("Couldn't fetch latest version, initializing with version 0.0.1")
-		version = "0.0.1"
+		vm = versioning.NewVersionManager("0.0.1")
+	} else {
+		vm.IncrementVersion()
 	}
-
-	vm := versioning.NewVersionManager(version)
 	pluginMgr := plugin.NewPluginManager()
+	// Tenants that must not be scanned are never recorded either
+	pluginMgr.RecordFilter = func(config map[string]interface{}) func(models.RawData) models.RawData {
+		filter := dataparser.ParseFilter(config["filters"])
+		return func(data models.RawData) models.RawData {
+			filtered, _, _ := filter.Apply(data)
+			return filtered
+		}
+	}
 	pluginMgr.RegisterPluginConstructors()
 	pluginMgr.InitializePlugins()
 
+	ctx, cancel := context.WithCancel(context.Background())
 	o := &Manager{
 		Transformers:   tf,
 		Service:        srv,
 		VersionManager: vm,
 		Scheduler:      NewScheduler(),
 		PluginManager:  pluginMgr,
+		ctx:            ctx,
+		cancel:         cancel,
+		latest:         make(map[string]plugin.ProviderData),
 	}
 
 	return o
@@ -49,8 +84,17 @@ func (o *Manager) Start() error {
 		logger.Error("Failed to create UUID constraints: %v", err)
 		return err
 	}
+	// Versions left pending by an interrupted scan are never completed
+	failed, err := o.Service.FailPendingVersions()
+	if err != nil {
+		logger.Error("Failed to mark pending versions failed: %v", err)
+		return err
+	}
+	if failed > 0 {
+		logger.Warning(fmt.Sprintf("Marked %d pending versions of interrupted scans failed", failed))
+	}
 	// 2) Run Initial infrastructure scan
-	err = o.coordinator()
+	err = o.coordinator(TriggerInitial, o.PluginManager.ActivePluginNames())
 	if err != nil {
 		return err
 	}
@@ -60,22 +104,132 @@ func (o *Manager) Start() error {
 	return nil
 }
 
-// startPeriodicScans scans the infrastructure periodically using provider plugins
+// startPeriodicScans scans the providers sharing a schedule together. Each run
+// fetches the due providers and builds a new version together with the last
+// fetch of the other providers.
 func (o *Manager) startPeriodicScans() {
-	o.Scheduler.AddTask("@every 3m", func() {
-		o.VersionManager.IncrementVersion()
-		o.coordinator()
-	})
+	groups := scheduleGroups(o.PluginManager.ActivePluginNames(), o.PluginManager.Schedules, viper.GetString("SCAN_SCHEDULE"))
+	for _, group := range groups {
+		task := o.scanTask(strings.Join(group.Providers, ","), TriggerSchedule, group.Providers)
+		task.Jitter = group.Jitter
+		err := o.Scheduler.AddScheduledTask(group.Spec, task)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Invalid schedule %q for providers %s", group.Spec, task.Name), err)
+		}
+	}
 	o.Scheduler.Start()
 }
 
+// scheduleGroup is a cron spec and the providers scanned on it, delayed by
+// the largest jitter among them
+type scheduleGroup struct {
+	Spec      string
+	Jitter    time.Duration
+	Providers []string
+}
+
+// scheduleGroups groups the providers by their schedule, providers without
+// one use defaultSpec.
+func scheduleGroups(names []string, schedules map[string]plugin.Schedule, defaultSpec string) []scheduleGroup {
+	var groups []scheduleGroup
+	index := make(map[string]int)
+	for _, name := range names {
+		schedule := schedules[name]
+		spec := schedule.Spec
+		if spec == "" {
+			spec = defaultSpec
+		}
+		i, ok := index[spec]
+		if !ok {
+			i = len(groups)
+			index[spec] = i
+			groups = append(groups, scheduleGroup{Spec: spec})
+		}
+		groups[i].Providers = append(groups[i].Providers, name)
+		if schedule.Jitter > groups[i].Jitter {
+			groups[i].Jitter = schedule.Jitter
+		}
+	}
+	return groups
+}
+
+// ScanNow starts a scan of the given providers, or of all providers if none
+// are given, unless a scan is already running. Providers inside a blackout
+// window are left out, ErrBlackout is returned if that leaves none.
+func (o *Manager) ScanNow(providers []string) ([]string, error) {
+	if len(providers) == 0 {
+		providers = o.PluginManager.ActivePluginNames()
+	}
+	var due []string
+	now := time.Now()
+	for _, name := range providers {
+		if _, err := o.PluginManager.GetPlugin(name); err != nil {
+			return nil, err
+		}
+		if !inBlackout(o.blackouts(name), now) {
+			due = append(due, name)
+		}
+	}
+	if len(due) == 0 {
+		return nil, ErrBlackout
+	}
+
+	return due, o.Scheduler.RunNow(o.scanTask("manual", TriggerManual, due))
+}
+
+// scanTask builds a Scheduler task creating a new version with fresh data of
+// the given providers. Providers inside one of their blackout windows are
+// left to their next run.
+func (o *Manager) scanTask(name string, trigger string, providers []string) Task {
+	return Task{Name: name, Cmd: func() {
+		var due []string
+		now := time.Now()
+		for _, provider := range providers {
+			if !inBlackout(o.blackouts(provider), now) {
+				due = append(due, provider)
+			}
+		}
+		if len(due) == 0 {
+			logger.Info("Skipping run inside blackout window", logger.LogFields{"task": name})
+			return
+		}
+		o.VersionManager.IncrementVersion()
+		o.coordinator(trigger, due)
+	}}
+}
+
+// blackouts parses the blackout windows of a provider, invalid windows are logged and ignored.
+func (o *Manager) blackouts(name string) []BlackoutWindow {
+	var windows []BlackoutWindow
+	for _, b := range o.PluginManager.Schedules[name].Blackouts {
+		w, err := ParseBlackoutWindow(b)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Ignoring blackout window of provider %s", name), err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	return windows
+}
+
+// Stop halts the periodic scans, cancels the fetches of a running scan and
+// waits for it to finish storing and recording its version.
+func (o *Manager) Stop() {
+	o.cancel()
+	o.Scheduler.Stop()
+	o.PluginManager.Close()
+}
+
 func getCurrentTimeString() string {
 	return time.Now().Format("2006-01-02 15:04:05")
 }
 
-func (o *Manager) coordinator() error {
+// coordinator fetches the given providers and stores a new version with their
+// data and the last fetch of all other providers.
+func (o *Manager) coordinator(trigger string, fresh []string) error {
 
 	v := o.VersionManager.GetCurrentVersion()
+	scanResult := dataparser.ScanResult{Version: v, Trigger: trigger, StartedAt: time.Now().UTC()}
 
 	err := o.Service.CreateMetadataNode(v, getCurrentTimeString())
 	if err != nil {
@@ -83,48 +237,121 @@ func (o *Manager) coordinator() error {
 		return err
 	}
 	logger.Info("*** Start fetching resources *** ")
-	// 0) Fetch API services using the appropriate plugin
-	for providerType := range o.PluginManager.ActivePlugins {
-		logger.Info("Fetching API services using ", logger.LogFields{"provider plugin": providerType})
-		// 1) Scan infrastructure using the appropriate plugin
-		rawDataMap := plugin.Scanner(o.PluginManager, providerType)
-
-		// 2) Transform raw data into generic data using the appropriate transformer
-		genericData, err := dataparser.TransformData(rawDataMap) // Call the TransformData function here
-		if err != nil {
-			logger.Error("Error transforming data: %v", err)
-			continue // continue to next provider if there's an error
+	// 1) Scan infrastructure using the due plugins concurrently
+	fetched := make(map[string]plugin.ProviderData)
+	for _, res := range plugin.ScanProviders(o.ctx, o.PluginManager, fresh) {
+		logger.Info("Fetched API services using ", logger.LogFields{"provider plugin": res.Provider, "duration": res.Duration.String()})
+		fetched[res.Provider] = res
+		// A failed fetch is only stored with this version, later versions
+		// reuse the last successful one
+		if res.Err == nil {
+			o.latest[res.Provider] = res
 		}
-		logger.Info("*** Generic data transformed ***")
-
-		// 3) Store generic data in Neo4j
-		for _, component := range genericData {
+	}
 
-			uuid, err := o.Service.CreateInfrastructureComponent(v, component)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Error storing %s in Neo4j: %v", component.Type, err))
+	// Nodes are stored one provider at a time in configuration order
+	var components []dataparser.InfrastructureComponent
+	var providerComponents [][]dataparser.InfrastructureComponent
+	for _, name := range o.PluginManager.ActivePluginNames() {
+		res, isFresh := fetched[name]
+		if !isFresh {
+			var ok bool
+			if res, ok = o.latest[name]; !ok {
+				// Not fetched successfully yet, e.g. the initial scan failed
 				continue
 			}
-
-			if component.Type == "Project" {
-				err = o.Service.LinkProjectToMetadata(v, uuid)
-				if err != nil {
-					logger.Error("Failed to link project to metadata: %v", err)
-				}
-			}
+			res = o.PluginManager.Reused(res)
 		}
-
-		// 4) Relationship Creation Phase: Create relationships between nodes
-		for _, component := range genericData {
-			err := o.Service.CreateRelationships(v, component)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Error creating Relationship %s in Neo4j: %v", component.Type, err))
-				continue
-			}
+		providerResult := dataparser.ProviderScanResult{Provider: res.Provider, Type: res.Type, FetchedAt: res.FetchedAt, FetchDuration: res.Duration, Reused: !isFresh}
+		if res.Err != nil {
+			logger.Error(fmt.Sprintf("Scan of %s failed, storing partial data", res.Provider), res.Err)
+			providerResult.Error = res.Err.Error()
 		}
 
+		stored := o.storeProviderNodes(v, res, &providerResult)
+		components = append(components, stored...)
+		providerComponents = append(providerComponents, stored)
+		scanResult.Providers = append(scanResult.Providers, providerResult)
+	}
+	if err := o.Service.LinkProjectsToMetadata(v); err != nil {
+		logger.Error("Failed to link projects to metadata: %v", err)
+	}
+
+	// 4) Relationships are created once the nodes of all providers are
+	// stored, so edges to other providers find their targets in this version
+	for i := range scanResult.Providers {
+		o.storeProviderRelationships(v, providerComponents[i], &scanResult.Providers[i])
+		scanResult.Providers[i].UpdateStatus()
+	}
+
+	// 4b) Link components of different providers once all of them are stored
+	scanResult.IdentityLinks, scanResult.LinkErrors = o.Service.LinkProviders(v, components)
+	for _, linkErr := range scanResult.LinkErrors {
+		logger.Error("Error linking providers: " + linkErr)
 	}
 	logger.Info("*** Finsihed storing data for all plugins ***")
 
+	// 5) Record the outcome of the scan on the metadata node
+	scanResult.Duration = time.Since(scanResult.StartedAt)
+	err = o.Service.UpdateMetadataScanResult(v, scanResult)
+	if err != nil {
+		logger.Error("Failed to store scan result: %v", err)
+	}
+
+	// 6) Publish the version if everything was stored, otherwise mark it failed
+	complete := err == nil && scanResult.Complete()
+	if finishErr := o.Service.FinishVersion(v, complete); finishErr != nil {
+		logger.Error("Failed to finish version: %v", finishErr)
+		return finishErr
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Scan finished", logger.LogFields{"version": v, "status": scanResult.Status(), "published": complete})
+
 	return nil
 }
+
+// storeProviderNodes transforms the data of a single provider and stores its
+// nodes, recording per-key and storage errors on the provider's scan result.
+// The transformed components are returned for creating their relationships
+// and linking them across providers.
+func (o *Manager) storeProviderNodes(v string, res plugin.ProviderData, providerResult *dataparser.ProviderScanResult) []dataparser.InfrastructureComponent {
+	if res.Data == nil {
+		return nil
+	}
+
+	// 2) Transform raw data into generic data using the appropriate transformer
+	filter := dataparser.ParseFilter(o.PluginManager.Settings[res.Provider]["filters"])
+	genericData, resourceResults, err := dataparser.TransformData(res.Provider, res.Data, filter)
+	providerResult.Resources = resourceResults
+	if err != nil {
+		// Keys that were transformed successfully are still stored
+		logger.Error("Error transforming data: %v", err)
+	}
+	logger.Info("*** Generic data transformed ***")
+
+	// 3) Store generic data in Neo4j, in batches per type
+	written, errs := o.Service.CreateInfrastructureComponents(v, genericData)
+	for _, storeErr := range errs {
+		logger.Error("Error storing in Neo4j: " + storeErr)
+	}
+	providerResult.StoreErrors = append(providerResult.StoreErrors, errs...)
+	logger.Debug("Stored components", logger.LogFields{"provider": res.Provider, "written": written})
+	return genericData
+}
+
+// storeProviderRelationships creates the relationships of the components of
+// a single provider, recording storage errors on the provider's scan result.
+func (o *Manager) storeProviderRelationships(v string, components []dataparser.InfrastructureComponent, providerResult *dataparser.ProviderScanResult) {
+	if len(components) == 0 {
+		return
+	}
+	written, unmatched, errs := o.Service.CreateRelationships(v, components)
+	for _, relErr := range errs {
+		logger.Error("Error creating Relationship in Neo4j: " + relErr)
+	}
+	providerResult.StoreErrors = append(providerResult.StoreErrors, errs...)
+	providerResult.UnmatchedRelationships = append(providerResult.UnmatchedRelationships, unmatched...)
+	logger.Debug("Stored relationships", logger.LogFields{"provider": providerResult.Provider, "written": written, "unmatched": len(unmatched)})
+}
@@ -0,0 +1,213 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/internal/repository"
+	services "github.com/regulatory-transparency-monitor/graph-builder/internal/service"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+	osModels "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeRepository records what the coordinator stores. Methods the coordinator
+// doesn't call aren't implemented and panic.
+type fakeRepository struct {
+	repository.Repository
+	calls    []string
+	nodes    map[string][]dataparser.InfrastructureComponent
+	links    map[string][]dataparser.IdentityLink
+	results  map[string]dataparser.ScanResult
+	finished map[string]bool
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		nodes:    make(map[string][]dataparser.InfrastructureComponent),
+		links:    make(map[string][]dataparser.IdentityLink),
+		results:  make(map[string]dataparser.ScanResult),
+		finished: make(map[string]bool),
+	}
+}
+
+func (r *fakeRepository) GetLastVersion() (string, error) {
+	return "", errors.New("no versions")
+}
+
+func (r *fakeRepository) CreateMetadataNode(version string, timestamp string) error {
+	r.calls = append(r.calls, "metadata "+version)
+	return nil
+}
+
+func (r *fakeRepository) CreateNodes(version string, components []dataparser.InfrastructureComponent) (int, []string) {
+	r.calls = append(r.calls, "nodes "+components[0].Provider)
+	r.nodes[version] = append(r.nodes[version], components...)
+	return len(components), nil
+}
+
+func (r *fakeRepository) LinkProjectsToMetadata(version string) error {
+	r.calls = append(r.calls, "projects")
+	return nil
+}
+
+func (r *fakeRepository) CreateRelationships(version string, components []dataparser.InfrastructureComponent) (int, []string, []string) {
+	r.calls = append(r.calls, "relationships "+components[0].Provider)
+	return 0, nil, nil
+}
+
+func (r *fakeRepository) CreateIdentityRels(version string, links []dataparser.IdentityLink) (int, []string) {
+	r.calls = append(r.calls, "identity links")
+	r.links[version] = links
+	return len(links), nil
+}
+
+func (r *fakeRepository) UpdateMetadataScanResult(version string, scanResult dataparser.ScanResult) error {
+	r.calls = append(r.calls, "scan result")
+	r.results[version] = scanResult
+	return nil
+}
+
+func (r *fakeRepository) FinishVersion(version string, complete bool) error {
+	r.calls = append(r.calls, "finish")
+	r.finished[version] = complete
+	return nil
+}
+
+// scansPlugin returns the given scans one after another
+type scansPlugin struct {
+	scans []models.RawData
+	next  int
+}
+
+func (p *scansPlugin) Initialize(config map[string]interface{}) error { return nil }
+
+func (p *scansPlugin) FetchData() (models.RawData, error) {
+	data := p.scans[p.next]
+	p.next++
+	return data, nil
+}
+
+// record writes the scans to dir the way the record option of a provider does
+func record(t *testing.T, provider string, dir string, scans ...models.RawData) {
+	recorder := plugin.NewRecordingPlugin(&scansPlugin{scans: scans}, provider, dir)
+	for range scans {
+		if _, err := recorder.FetchData(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func openStackScan(servers ...string) models.RawData {
+	project := &osModels.ProjectDetails{}
+	project.Project.ID = "p-1"
+	project.Project.Name = "webshop"
+	data := models.RawData{"os_project": {project}}
+	for _, name := range servers {
+		server := osModels.ServerDetails{}
+		server.Server.ID = "s-" + name
+		server.Server.Name = name
+		server.Server.TenantID = "p-1"
+		server.Server.HostID = "host-1"
+		data["os_instance"] = append(data["os_instance"], server)
+	}
+	return data
+}
+
+func kubernetesScan() models.RawData {
+	return models.RawData{
+		"k8s_node": {corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1", UID: "node-uid"},
+			Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeHostName, Address: "worker-1"}}},
+		}},
+		"k8s_pod": {corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "pod-uid"},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}},
+	}
+}
+
+func componentIDs(components []dataparser.InfrastructureComponent, provider string) []string {
+	var ids []string
+	for _, c := range components {
+		if c.Provider == provider {
+			ids = append(ids, fmt.Sprintf("%s %s", c.Type, c.ID))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TestCoordinatorReplay runs the scans of recorded providers through the
+// coordinator, without OpenStack, Kubernetes or Neo4j
+func TestCoordinatorReplay(t *testing.T) {
+	openStackDir, kubernetesDir := t.TempDir(), t.TempDir()
+	record(t, "openstack", openStackDir, openStackScan("worker-1"), openStackScan("worker-1", "worker-2"))
+	record(t, "cluster", kubernetesDir, kubernetesScan())
+
+	t.Cleanup(viper.Reset)
+	viper.Set("SCAN_TIMEOUT", "10s")
+	viper.Set("providers", []interface{}{
+		map[string]interface{}{"name": "replay", "id": "openstack", "enabled": true, "path": openStackDir},
+		map[string]interface{}{"name": "replay", "id": "cluster", "enabled": true, "path": kubernetesDir},
+	})
+	repo := newFakeRepository()
+	o := NewManager(nil, services.NewService(repo))
+
+	if err := o.coordinator(TriggerInitial, o.PluginManager.ActivePluginNames()); err != nil {
+		t.Fatal(err)
+	}
+	// Relationships are written once the nodes of all providers are stored
+	wantCalls := []string{
+		"metadata 0.0.1", "nodes openstack", "nodes cluster", "projects",
+		"relationships openstack", "relationships cluster", "identity links", "scan result", "finish",
+	}
+	if !reflect.DeepEqual(repo.calls, wantCalls) {
+		t.Errorf("calls = %v, want %v", repo.calls, wantCalls)
+	}
+	if !repo.finished["0.0.1"] {
+		t.Errorf("version 0.0.1 wasn't published: %+v", repo.results["0.0.1"])
+	}
+	if got, want := componentIDs(repo.nodes["0.0.1"], "openstack"), []string{"Instance s-worker-1", "PhysicalHost host-1", "Project p-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("openstack components = %v, want %v", got, want)
+	}
+	if got := componentIDs(repo.nodes["0.0.1"], "cluster"); len(got) == 0 {
+		t.Errorf("no cluster components were stored")
+	}
+	wantLink := dataparser.IdentityLink{
+		Type: "PROVISIONED_BY", SourceType: "ClusterNode", SourceID: "node-uid", SourceProvider: "cluster",
+		TargetID: "s-worker-1", TargetProvider: "openstack", Rule: "hostname", Confidence: 0.6,
+	}
+	if links := repo.links["0.0.1"]; len(links) != 1 || links[0] != wantLink {
+		t.Errorf("identity links = %+v, want %+v", links, wantLink)
+	}
+
+	// A scheduled run of OpenStack replays its next recording, the cluster is
+	// stored again from its last fetch
+	repo.calls = nil
+	o.scanTask("openstack", TriggerSchedule, []string{"openstack"}).Cmd()
+	if !repo.finished["0.0.2"] {
+		t.Fatalf("version 0.0.2 wasn't published: %v", repo.calls)
+	}
+	if got, want := componentIDs(repo.nodes["0.0.2"], "openstack"), []string{"Instance s-worker-1", "Instance s-worker-2", "PhysicalHost host-1", "Project p-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("openstack components = %v, want %v", got, want)
+	}
+	if got, want := componentIDs(repo.nodes["0.0.2"], "cluster"), componentIDs(repo.nodes["0.0.1"], "cluster"); !reflect.DeepEqual(got, want) {
+		t.Errorf("reused cluster components = %v, want %v", got, want)
+	}
+	reused := make(map[string]bool)
+	for _, p := range repo.results["0.0.2"].Providers {
+		reused[p.Provider] = p.Reused
+	}
+	if want := map[string]bool{"openstack": false, "cluster": true}; !reflect.DeepEqual(reused, want) {
+		t.Errorf("reused providers = %v, want %v", reused, want)
+	}
+}
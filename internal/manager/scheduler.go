@@ -1,14 +1,53 @@
 package manager
 
-import "github.com/robfig/cron"
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+	"github.com/robfig/cron"
+)
+
+var (
+	// ErrScanRunning is returned for on-demand runs while another run is in progress
+	ErrScanRunning = errors.New("a scan is already running")
+	// ErrBlackout is returned for on-demand runs inside a blackout window
+	ErrBlackout = errors.New("scan is inside a blackout window")
+	// ErrStopped is returned for on-demand runs once the Scheduler is stopped
+	ErrStopped = errors.New("scheduler is stopped")
+)
+
+// Scheduler runs tasks on cron schedules and on demand. Runs never overlap:
+// scheduled runs wait for the current run to finish, while on-demand runs are
+// rejected with ErrScanRunning.
 type Scheduler struct {
 	cronJob *cron.Cron
+
+	// run is held for the duration of a task run
+	run sync.Mutex
+	// pending tracks tasks that are waiting for or holding run, so a slow run
+	// doesn't queue up ticks of the same task
+	mu      sync.Mutex
+	pending map[string]bool
+	stopped bool
+}
+
+// Task is a named function with an optional random delay and blackout windows.
+type Task struct {
+	Name      string
+	Jitter    time.Duration
+	Blackouts []BlackoutWindow
+	Cmd       func()
 }
 
 func NewScheduler() *Scheduler {
 	return &Scheduler{
 		cronJob: cron.New(),
+		pending: make(map[string]bool),
 	}
 }
 
@@ -16,6 +55,214 @@ func (s *Scheduler) Start() {
 	s.cronJob.Start()
 }
 
-func (s *Scheduler) AddTask(spec string, cmd func()) {
-	s.cronJob.AddFunc(spec, cmd)
+// Stop halts the schedules and waits for the running task to finish. No task
+// runs afterwards, scheduled runs that already fired return without running
+// and RunNow returns ErrStopped.
+func (s *Scheduler) Stop() {
+	s.cronJob.Stop()
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.run.Lock()
+	defer s.run.Unlock()
+}
+
+// AddScheduledTask runs the task on the cron spec, delayed by up to its
+// jitter and skipped inside its blackout windows.
+func (s *Scheduler) AddScheduledTask(spec string, task Task) error {
+	return s.cronJob.AddFunc(spec, func() {
+		time.Sleep(jitter(task.Jitter))
+		s.runScheduled(task)
+	})
+}
+
+// runScheduled runs a task once the running one finished, unless the same
+// task is already pending or the run falls into a blackout window.
+func (s *Scheduler) runScheduled(task Task) {
+	if inBlackout(task.Blackouts, time.Now()) {
+		logger.Info("Skipping scheduled run inside blackout window", logger.LogFields{"task": task.Name})
+		return
+	}
+	if !s.claim(task.Name) {
+		logger.Warning("Skipping scheduled run, previous run is still pending", logger.LogFields{"task": task.Name})
+		return
+	}
+	defer s.release(task.Name)
+
+	s.run.Lock()
+	defer s.run.Unlock()
+	if s.isStopped() {
+		return
+	}
+	task.Cmd()
+}
+
+// jitter returns a random delay below max.
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// RunNow starts the task immediately in the background without jitter. It
+// returns ErrScanRunning if any task is running, ErrBlackout inside one of
+// the task's blackout windows and ErrStopped after Stop.
+func (s *Scheduler) RunNow(task Task) error {
+	if s.isStopped() {
+		return ErrStopped
+	}
+	if inBlackout(task.Blackouts, time.Now()) {
+		return ErrBlackout
+	}
+	if !s.claim(task.Name) {
+		return ErrScanRunning
+	}
+	if !s.run.TryLock() {
+		s.release(task.Name)
+		return ErrScanRunning
+	}
+	// Stop may have waited for the previous run and returned in between
+	if s.isStopped() {
+		s.run.Unlock()
+		s.release(task.Name)
+		return ErrStopped
+	}
+
+	go func() {
+		defer s.release(task.Name)
+		defer s.run.Unlock()
+		task.Cmd()
+	}()
+	return nil
+}
+
+func (s *Scheduler) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+func (s *Scheduler) claim(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[name] {
+		return false
+	}
+	s.pending[name] = true
+	return true
+}
+
+func (s *Scheduler) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, name)
+}
+
+// BlackoutWindow is a daily time range, optionally limited to some weekdays.
+// Ranges ending before they start wrap past midnight.
+type BlackoutWindow struct {
+	Days  map[time.Weekday]bool // nil means every day
+	Start time.Duration         // offset from midnight
+	End   time.Duration
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseBlackoutWindow parses windows like "22:00-06:00", "Sat,Sun 00:00-24:00"
+// or "Mon-Fri 08:00-18:00". Times are local to the graph builder.
+func ParseBlackoutWindow(s string) (BlackoutWindow, error) {
+	var w BlackoutWindow
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return w, fmt.Errorf("invalid blackout window %q", s)
+	}
+	if len(fields) == 2 {
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return w, fmt.Errorf("invalid blackout window %q: %w", s, err)
+		}
+		w.Days = days
+	}
+
+	start, end, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return w, fmt.Errorf("invalid blackout window %q: missing time range", s)
+	}
+	var err error
+	if w.Start, err = parseClock(start); err != nil {
+		return w, fmt.Errorf("invalid blackout window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return w, fmt.Errorf("invalid blackout window %q: %w", s, err)
+	}
+	return w, nil
+}
+
+// Contains reports whether t falls inside the window. For windows wrapping
+// past midnight the weekday refers to the day the window starts on.
+func (w BlackoutWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	day := t.Weekday()
+
+	if w.Start <= w.End {
+		return w.onDay(day) && offset >= w.Start && offset < w.End
+	}
+	if offset >= w.Start {
+		return w.onDay(day)
+	}
+	return offset < w.End && w.onDay((day+6)%7)
+}
+
+func (w BlackoutWindow) onDay(day time.Weekday) bool {
+	return w.Days == nil || w.Days[day]
+}
+
+func inBlackout(windows []BlackoutWindow, t time.Time) bool {
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeekdays parses comma separated days or day ranges like "Mon-Fri,Sun".
+func parseWeekdays(s string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return nil, fmt.Errorf("unknown weekday %q", to)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight, "24:00" marks the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
 }
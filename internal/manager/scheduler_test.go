@@ -0,0 +1,249 @@
+package manager
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/regulatory-transparency-monitor/graph-builder/internal/testflags"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+)
+
+func TestParseBlackoutWindow(t *testing.T) {
+	tests := []struct {
+		window string
+		want   BlackoutWindow
+		err    bool
+	}{
+		{window: "22:00-06:00", want: BlackoutWindow{Start: 22 * time.Hour, End: 6 * time.Hour}},
+		{window: "Sat,Sun 00:00-24:00", want: BlackoutWindow{Days: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}, End: 24 * time.Hour}},
+		{
+			window: "Mon-Fri 08:30-18:00",
+			want: BlackoutWindow{
+				Days:  map[time.Weekday]bool{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true},
+				Start: 8*time.Hour + 30*time.Minute, End: 18 * time.Hour,
+			},
+		},
+		{window: "fri-mon 01:00-02:00", want: BlackoutWindow{Days: map[time.Weekday]bool{time.Friday: true, time.Saturday: true, time.Sunday: true, time.Monday: true}, Start: time.Hour, End: 2 * time.Hour}},
+		{window: "", err: true},
+		{window: "22:00", err: true},
+		{window: "Mon 22:00-06:00 extra", err: true},
+		{window: "Someday 22:00-06:00", err: true},
+		{window: "Mon-Xyz 22:00-06:00", err: true},
+		{window: "25:00-06:00", err: true},
+		{window: "22:00-6pm", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			got, err := ParseBlackoutWindow(tt.window)
+			if tt.err {
+				if err == nil {
+					t.Errorf("ParseBlackoutWindow(%q) = %+v, want an error", tt.window, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBlackoutWindow(%q) = %+v, want %+v", tt.window, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlackoutWindowContains(t *testing.T) {
+	// 2024-03-04 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		window  string
+		inside  []time.Time
+		outside []time.Time
+	}{
+		{
+			window:  "08:00-18:00",
+			inside:  []time.Time{at(4, 8, 0), at(4, 17, 59), at(9, 12, 0)},
+			outside: []time.Time{at(4, 7, 59), at(4, 18, 0), at(4, 23, 0)},
+		},
+		{
+			window:  "22:00-06:00",
+			inside:  []time.Time{at(4, 22, 0), at(4, 23, 59), at(5, 0, 0), at(5, 5, 59)},
+			outside: []time.Time{at(4, 21, 59), at(5, 6, 0), at(5, 12, 0)},
+		},
+		{
+			// The window of Friday night reaches into Saturday, the one of
+			// Sunday night isn't opened
+			window:  "Mon-Fri 22:00-06:00",
+			inside:  []time.Time{at(4, 23, 0), at(5, 3, 0), at(8, 23, 0), at(9, 3, 0)},
+			outside: []time.Time{at(4, 3, 0), at(9, 23, 0)},
+		},
+		{
+			window:  "Sat,Sun 00:00-24:00",
+			inside:  []time.Time{at(9, 0, 0), at(9, 23, 59)},
+			outside: []time.Time{at(8, 23, 59), at(4, 0, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			w, err := ParseBlackoutWindow(tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ts := range tt.inside {
+				if !w.Contains(ts) {
+					t.Errorf("%s isn't inside", ts.Format("Mon 15:04"))
+				}
+			}
+			for _, ts := range tt.outside {
+				if w.Contains(ts) {
+					t.Errorf("%s is inside", ts.Format("Mon 15:04"))
+				}
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	if d := jitter(0); d != 0 {
+		t.Errorf("jitter(0) = %s", d)
+	}
+	if d := jitter(-time.Second); d != 0 {
+		t.Errorf("jitter(-1s) = %s", d)
+	}
+	max := 10 * time.Millisecond
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		d := jitter(max)
+		if d < 0 || d >= max {
+			t.Fatalf("jitter(%s) = %s", max, d)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("jitter(%s) isn't random: %v", max, seen)
+	}
+}
+
+// blockingTask returns a task signalling its start and running until release is closed
+func blockingTask(name string, started chan<- string, release <-chan struct{}) Task {
+	return Task{Name: name, Cmd: func() {
+		started <- name
+		<-release
+	}}
+}
+
+func TestSchedulerOverlap(t *testing.T) {
+	s := NewScheduler()
+	started := make(chan string, 3)
+	release := make(chan struct{})
+
+	if err := s.RunNow(blockingTask("a", started, release)); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	if err := s.RunNow(blockingTask("b", started, release)); !errors.Is(err, ErrScanRunning) {
+		t.Errorf("RunNow during a run = %v, want ErrScanRunning", err)
+	}
+	// A scheduled tick of the running task is dropped
+	s.runScheduled(blockingTask("a", started, release))
+
+	// A scheduled run of another task waits for the running one
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runScheduled(blockingTask("c", started, release))
+	}()
+	select {
+	case name := <-started:
+		t.Fatalf("%s started during a run", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if name := <-started; name != "c" {
+		t.Errorf("%s ran after the first run, want c", name)
+	}
+	wg.Wait()
+	select {
+	case name := <-started:
+		t.Errorf("%s ran, want no further runs", name)
+	default:
+	}
+}
+
+func TestSchedulerBlackout(t *testing.T) {
+	s := NewScheduler()
+	always := BlackoutWindow{End: 24 * time.Hour}
+	ran := false
+	task := Task{Name: "a", Blackouts: []BlackoutWindow{always}, Cmd: func() { ran = true }}
+	if err := s.RunNow(task); !errors.Is(err, ErrBlackout) {
+		t.Errorf("RunNow inside a blackout window = %v, want ErrBlackout", err)
+	}
+	s.runScheduled(task)
+	if ran {
+		t.Errorf("task ran inside a blackout window")
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	s := NewScheduler()
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	if err := s.RunNow(blockingTask("a", started, release)); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// A scheduled run waiting for the running one doesn't run after Stop
+	waiting := make(chan struct{})
+	go func() {
+		defer close(waiting)
+		s.runScheduled(blockingTask("b", started, release))
+	}()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.Stop()
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned during a run")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+	<-waiting
+	select {
+	case name := <-started:
+		t.Errorf("%s ran after Stop", name)
+	default:
+	}
+	if err := s.RunNow(blockingTask("c", started, release)); !errors.Is(err, ErrStopped) {
+		t.Errorf("RunNow after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestScheduleGroups(t *testing.T) {
+	names := []string{"openstack", "cluster-a", "cluster-b", "aws"}
+	schedules := map[string]plugin.Schedule{
+		"openstack": {Spec: "@every 1h", Jitter: time.Minute},
+		"cluster-a": {Jitter: 2 * time.Minute},
+		"cluster-b": {Spec: "@every 1h", Jitter: 5 * time.Minute},
+	}
+
+	groups := scheduleGroups(names, schedules, "@every 3m")
+	want := []scheduleGroup{
+		{Spec: "@every 1h", Jitter: 5 * time.Minute, Providers: []string{"openstack", "cluster-b"}},
+		{Spec: "@every 3m", Jitter: 2 * time.Minute, Providers: []string{"cluster-a", "aws"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %+v, want %+v", groups, want)
+	}
+}
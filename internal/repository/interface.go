@@ -10,22 +10,18 @@ import (
 // Repository definition for repository
 type Repository interface {
 	// Metadata logic
-	GetLabels() ([]string, error)                              // Get all labels from the database
-	SetupUUIDForKnownLabels() error                            // Create UUID constraints for known labels
-	CreateUUIDConstraints(labels string) error                 // Create UUID constraints for a given label
-	GetLatestVersion() (string, error)                         // Get the latest version of metaNode from the database
-	CreateMetadataNode(version string, timestamp string) error // Create a new metadata node using incremented version
+	GetLabels() ([]string, error)                                                    // Get all labels from the database
+	SetupUUIDForKnownLabels() error                                                  // Create UUID constraints and lookup indexes for known labels
+	CreateUUIDConstraints(labels string) error                                       // Create UUID constraints for a given label
+	GetLatestVersion() (string, error)                                               // Get the latest complete version of metaNode from the database
+	GetLastVersion() (string, error)                                                 // Get the version of the last scan, whatever its status
+	CreateMetadataNode(version string, timestamp string) error                       // Create the pending metadata node of a new version
+	FinishVersion(version string, complete bool) error                               // Publish a version as complete or mark it failed
+	FailPendingVersions() (int, error)                                               // Mark the versions of interrupted scans failed
+	UpdateMetadataScanResult(version string, scanResult dataparser.ScanResult) error // Record the outcome of a scan on its metadata node
 
 	// Create Nodes using generic data
-	CreateProjectNode(version string, project dataparser.InfrastructureComponent) (uuid string, err error)     // Create a new project node
-	CreateInstanceNode(version string, instance dataparser.InfrastructureComponent) (uuid string, err error)   // Create a new instance node
-	CreatePhysicalHostNode(version string, host dataparser.InfrastructureComponent) (uuid string, err error)   // Create a new physical host node
-	CreateVolumeNode(version string, volume dataparser.InfrastructureComponent) (uuid string, err error)       // Create a new volume node
-	CreateSnapshotNode(version string, snapshot dataparser.InfrastructureComponent) (uuid string, err error)   // Create a new snapshot node
-	CreateClusterNode(version string, clusterNode dataparser.InfrastructureComponent) (uuid string, err error) // Create a new cluster node
-	CreatePodNode(version string, pod dataparser.InfrastructureComponent) (uuid string, err error)             // Create a new pod node
-	CreatePVNode(version string, pv dataparser.InfrastructureComponent) (uuid string, err error)
-	CreatePVCNode(version string, pvc dataparser.InfrastructureComponent) (uuid string, err error)
+	CreateNodes(version string, components []dataparser.InfrastructureComponent) (written int, errs []string) // Upsert the nodes of components of any type in batches, see NodeTypes
 
 	//Old loghic Create and update nodes using generic data
 	CreateOrUpdateServer(dataparser.InfrastructureComponent) error
@@ -34,18 +30,14 @@ type Repository interface {
 	CreateOrUpdatePod(dataparser.InfrastructureComponent) error
 
 	// Create Relationships
-	LinkProjectToMetadata(version string, projectUUID string) error // Link a projectUUID of current scan to metadata node
+	LinkProjectsToMetadata(version string) error // Link the projects of a version to its metadata node
 	// Link Meta to next Metanode
-	CreateInstanceRelationships(instanceID string, version string, relationships []dataparser.Relationship) error // Create relationships for a given instance
-	CreateClusterNodeRel(nodeID string, version string, relationships []dataparser.Relationship) error            // Create relationships for a given cluster node
-	CreateVolumeRel(volumeID string, version string, relationships []dataparser.Relationship) error               // Create relationships for a given volume
-	CreatePodRel(podID string, version string, relationships []dataparser.Relationship) error                     // Create relationships for a given pod
-	CreatePVCRel(pvcID string, version string, relationships []dataparser.Relationship) error
-	CreatePVRel(pvID string, version string, relationships []dataparser.Relationship) error
-	CreatePDNode(version string, pd dataparser.InfrastructureComponent) (uuid string, err error)
-	CreateSnapshotRel(snapshotID string, version string, relationships []dataparser.Relationship) error //Snapshot to Volume
+	CreateRelationships(version string, components []dataparser.InfrastructureComponent) (written int, unmatched []string, errs []string) // Create the relationships of components in batches, see RelationshipTypes
+	CreateIdentityRels(version string, links []dataparser.IdentityLink) (written int, errs []string)                  // Link components to components of other providers
 	// GraphQL API
-	GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) // Use Casae 1
-	// Use Casae 2
-	// Use Casae 3
+	GetMetadata(ctx context.Context, version string) (*model.Metadata, error)
+	GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error)        // Use Casae 1
+	GetExposedPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) // Use Casae 2
+	GetPublicInstancesWithPds(ctx context.Context, version string) ([]*model.Instance, error)                 // Use Casae 3
+	GetFindings(ctx context.Context, version string) ([]*model.Finding, error)
 }
@@ -2,11 +2,12 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 	"github.com/regulatory-transparency-monitor/graph-builder/graph/model"
+	"github.com/regulatory-transparency-monitor/graph-builder/internal/versioning"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
 	"github.com/spf13/viper"
@@ -15,6 +16,58 @@ import (
 // Neo4jRepository is a Neo4j DB repository
 type Neo4jRepository struct {
 	Connection neo4j.Driver
+	// BatchSize is the number of rows written per query, DefaultBatchSize if not set
+	BatchSize int
+	// Storage is StorageVersioned or StorageTemporal, versioned if not set
+	Storage string
+
+	// indexed holds the labels outside NodeTypes temporal storage has indexed
+	indexed sync.Map
+}
+
+// DefaultBatchSize of the UNWIND writes
+const DefaultBatchSize = 500
+
+func (r *Neo4jRepository) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return r.BatchSize
+}
+
+// batches splits rows into batches of at most size rows
+func batches(rows []map[string]interface{}, size int) [][]map[string]interface{} {
+	var out [][]map[string]interface{}
+	for len(rows) > size {
+		out = append(out, rows[:size])
+		rows = rows[size:]
+	}
+	if len(rows) > 0 {
+		out = append(out, rows)
+	}
+	return out
+}
+
+// run runs a write query and waits for its outcome. The driver only reports
+// the errors of a query with the next query of the session, and drops them
+// when the session is closed.
+func run(session neo4j.Session, query string, parameters map[string]interface{}) error {
+	result, err := session.Run(query, parameters)
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume()
+	return err
+}
+
+// runTx is run within the transaction tx
+func runTx(tx neo4j.Transaction, query string, parameters map[string]interface{}) error {
+	result, err := tx.Run(query, parameters)
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume()
+	return err
 }
 
 // NewNeo4jConnection creates a new neo4j connection returns a neo4j.Driver object and an error
@@ -66,13 +119,38 @@ func (r *Neo4jRepository) GetLabels() ([]string, error) {
 }
 
 func (r *Neo4jRepository) SetupUUIDForKnownLabels() error {
-	labels := []string{"Metadata", "Project", "Instance", "Volume", "ClusterNode", "Pod", "PhysicalHost", "PersistentVolume", "PersistentVolumeClaim", "PDIndicator", "DataCategory", "Snapshot"}
+	labels := append([]string{"Metadata"}, KnownLabels()...)
 
 	for _, label := range labels {
 		if err := r.CreateUUIDConstraints(label); err != nil {
 			return err
 		}
 	}
+	return r.createIndexes()
+}
+
+// createIndexes indexes the known labels on id and provider, nodes and the
+// ends of relationships are looked up by. Temporal storage also indexes the
+// validity of records, see temporalIndexes.
+func (r *Neo4jRepository) createIndexes() error {
+	session, err := r.Connection.Session(neo4j.AccessModeWrite)
+	if err != nil {
+		return fmt.Errorf("error creating Neo4j session: %v", err)
+	}
+	defer session.Close()
+
+	var queries []string
+	for _, label := range KnownLabels() {
+		queries = append(queries, fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.id, n.provider)", label))
+	}
+	if r.temporal() {
+		queries = append(queries, temporalIndexes(KnownLabels(), knownEdgeTypes())...)
+	}
+	for _, query := range queries {
+		if err := run(session, query, nil); err != nil {
+			return fmt.Errorf("error creating index with query: %s, %v", query, err)
+		}
+	}
 	return nil
 }
 
@@ -102,22 +180,42 @@ func (r *Neo4jRepository) CreateUUIDConstraints(label string) error {
 	return nil
 }
 
-// GetLatestVersion retrieves the latest version from the Metadata node
+// completeVersion matches the Metadata node bound to variable of a complete
+// version, versions written before scans had a status count as complete if
+// their scan was. The status is the $complete parameter.
+func completeVersion(variable string) string {
+	return fmt.Sprintf("(%[1]s.status = $complete OR (%[1]s.status IS NULL AND %[1]s.complete = true))", variable)
+}
+
+// GetLatestVersion retrieves the latest complete version
 func (r *Neo4jRepository) GetLatestVersion() (string, error) {
-	session, err := r.Connection.Session(neo4j.AccessModeRead)
-	if err != nil {
-		return "", fmt.Errorf("error creating Neo4j session: %v", err)
-	}
-	defer session.Close()
+	return r.latestVersion(fmt.Sprintf(`
+        MATCH (m:Metadata)
+        WHERE %s
+        RETURN m.version AS version
+        ORDER BY m.scanTimestamp DESC
+        LIMIT 1
+    `, completeVersion("m")))
+}
 
-	query := `
+// GetLastVersion retrieves the version of the last scan, whatever its status
+func (r *Neo4jRepository) GetLastVersion() (string, error) {
+	return r.latestVersion(`
         MATCH (m:Metadata)
         RETURN m.version AS version
         ORDER BY m.scanTimestamp DESC
         LIMIT 1
-    `
+    `)
+}
 
-	result, err := session.Run(query, nil)
+func (r *Neo4jRepository) latestVersion(query string) (string, error) {
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		return "", fmt.Errorf("error creating Neo4j session: %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.Run(query, map[string]interface{}{"complete": versioning.StatusComplete})
 	if err != nil {
 		return "", fmt.Errorf("error getting latest version from metadata node: %s, %v", query, err)
 	}
@@ -133,560 +231,216 @@ func (r *Neo4jRepository) GetLatestVersion() (string, error) {
 	return "", fmt.Errorf("no metadata nodes found in the database")
 }
 
-// CreateMetadataNode creates a new Metadata node with the provided version and timestamp
+// CreateMetadataNode creates the pending Metadata node of a new version
 func (r *Neo4jRepository) CreateMetadataNode(version string, timestamp string) error {
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
 		return fmt.Errorf("error creating Neo4j session: %v", err)
 	}
 	defer session.Close()
-	// Step 1: Get the latest version
-	latestVersion, err := r.GetLatestVersion()
-	if err != nil && err.Error() != "no metadata nodes found in the database" {
-		// Handle error if it is not just "no metadata nodes found"
-		return fmt.Errorf("error getting the latest Metadata version: %v", err)
-	}
 
 	query := `
-		CREATE (m:Metadata {version: $version, scanTimestamp: $timestamp})
+		CREATE (m:Metadata {version: $version, scanTimestamp: $timestamp, status: $status})
 	`
 
 	parameters := map[string]interface{}{
 		"version":   version,
 		"timestamp": timestamp,
+		"status":    versioning.StatusPending,
 	}
 
-	_, err = session.Run(query, parameters)
+	err = run(session, query, parameters)
 	if err != nil {
 		return fmt.Errorf("error creating Metadata node in Neo4j with query: %s, %v", query, err)
 	}
-	// Step 3: If there is a previous version, create a relationship with the new version
-	if latestVersion != "" {
-		queryCreateRelationship := `
-			MATCH (mNew:Metadata {version: $newVersion}), (mOld:Metadata {version: $oldVersion})
-			CREATE (mOld)-[:NEXT_VERSION]->(mNew)
-		`
-		parametersRelationship := map[string]interface{}{
-			"newVersion": version,
-			"oldVersion": latestVersion,
-		}
-		_, err = session.Run(queryCreateRelationship, parametersRelationship)
-		if err != nil {
-			return fmt.Errorf("error creating relationship in Neo4j with query: %s, %v", queryCreateRelationship, err)
-		}
-	}
 	return nil
 }
 
-// CreateProject always creates a new project node and returns its UUID
-func (r *Neo4jRepository) CreateProjectNode(version string, project dataparser.InfrastructureComponent) (uuid string, err error) {
+// FinishVersion publishes a version as complete, linking it to the previous
+// complete version, or marks it failed. In temporal storage publishing ends
+// the validity of everything the scan didn't find, a failed scan may have
+// missed it, and failing discards the records the scan wrote.
+func (r *Neo4jRepository) FinishVersion(version string, complete bool) error {
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("error creating Neo4j session: %v", err)
 	}
 	defer session.Close()
 
-	query := `
-    CREATE (p:Project {
-		uuid: apoc.create.uuid(), 
-		version: $version,
-		id: $id,
-		name: $name, 
-		type: $type, 
-		availabilityZone: $availabilityZone, 
-		enabled: $enabled, 
-		description: $description
+	// Closing the records and publishing the version commit together, a
+	// version is never published with records it removed still current
+	_, err = session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		return nil, r.finishVersion(tx, version, complete)
 	})
-	RETURN p.uuid as uuid
-    `
-
-	parameters := map[string]interface{}{
-		"version":          version,
-		"id":               project.ID,
-		"name":             project.Name,
-		"type":             project.Type,
-		"availabilityZone": project.AvailabilityZone,
-		"description":      GetMetadataValue(project.Metadata, "Description", ""),
-		"enabled":          GetMetadataValue(project.Metadata, "Enabled", false),
-	}
-
-	result, err := session.Run(query, parameters)
-	if err != nil {
-		return "", fmt.Errorf("error creating Project node: %s, %v", query, err)
-	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
-		}
-	}
-
-	return "", nil
+	return err
 }
 
-// CreateInstance always creates a new instance node
-func (r *Neo4jRepository) CreateInstanceNode(version string, instance dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	query := `
-    CREATE (i:Instance {
-		uuid: apoc.create.uuid(), 
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		availabilityZone: $availabilityZone,
-		userID: $userID,
-		hostID: $hostID,
-		tenantID: $tenantID,
-		created: $created,
-		updated: $updated,
-		volumesAttached: $volumesAttached,
-		status: $status
-	})
-	RETURN i.uuid as uuid
-	`
-
-	parameters := map[string]interface{}{
-		"version":          version,
-		"id":               instance.ID,
-		"name":             instance.Name,
-		"type":             instance.Type,
-		"availabilityZone": instance.AvailabilityZone,
-		"userID":           GetMetadataValue(instance.Metadata, "UserID", ""),
-		"hostID":           GetMetadataValue(instance.Metadata, "HostID", ""),
-		"tenantID":         GetMetadataValue(instance.Metadata, "TenantID", ""),
-		"created":          GetMetadataValue(instance.Metadata, "Created", ""),
-		"updated":          GetMetadataValue(instance.Metadata, "Updated", ""),
-		"volumesAttached":  GetMetadataValue(instance.Metadata, "VolumesAttached", ""),
-		"status":           GetMetadataValue(instance.Metadata, "Status", ""),
-	}
-
-	result, err := session.Run(query, parameters)
-	if err != nil {
-		return "", fmt.Errorf("error creating instance node: %s, %v", query, err)
-	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
+func (r *Neo4jRepository) finishVersion(tx neo4j.Transaction, version string, complete bool) error {
+	if !complete {
+		if r.temporal() {
+			if err := r.discardVersion(tx, version); err != nil {
+				return err
 			}
 		}
-	}
-
-	return "", nil
-}
-
-func (r *Neo4jRepository) CreatePhysicalHostNode(version string, host dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	query := `
-    CREATE (h:PhysicalHost {
-		uuid: apoc.create.uuid(), 
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		availabilityZone: $availabilityZone
-	})
-	RETURN h.uuid as uuid
-	`
-	parameters := map[string]interface{}{
-		"version":          version,
-		"id":               host.ID,
-		"name":             host.Name,
-		"type":             host.Type,
-		"availabilityZone": host.AvailabilityZone,
-	}
-
-	_, err = session.Run(query, parameters)
-
-	//logger.Debug("Created physical host in Neo4j", logger.LogFields{"host_id": host.ID}, result)
-	if err != nil {
-		return "", fmt.Errorf("error creating instance node: %s, %v", query, err)
-	}
-
-	return "", nil
-}
-
-// CreateVolumeNode always creates a new volume node and returns its UUID
-func (r *Neo4jRepository) CreateVolumeNode(version string, volume dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	query := `
-    CREATE (v:Volume {
-		uuid: apoc.create.uuid(),
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		availabilityZone: $availabilityZone,
-		status: $status,
-		size: $size,
-		bootable: $bootable,
-		encrypted: $encrypted,
-		multiattach: $multiattach,
-		device: $device,
-		srcSnapshot: $snapshotID
-	})
-	RETURN v.uuid as uuid
-	`
-
-	parameters := map[string]interface{}{
-		"version":          version,
-		"id":               volume.ID,
-		"name":             volume.Name,
-		"type":             volume.Type,
-		"availabilityZone": volume.AvailabilityZone,
-		"status":           volume.Metadata["status"],
-		"size":             volume.Metadata["size"],
-		"bootable":         volume.Metadata["bootable"],
-		"encrypted":        volume.Metadata["encrypted"],
-		"multiattach":      volume.Metadata["multiattach"],
-		"device":           volume.Metadata["device"],
-		"snapshotID":       volume.Metadata["snapshotID"],
-	}
-
-	result, err := session.Run(query, parameters)
-	if err != nil {
-		return "", fmt.Errorf("error creating Volume node: %s, %v", query, err)
-	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
+		query := `
+			MATCH (m:Metadata {version: $version})
+			SET m.status = $status
+		`
+		err := runTx(tx, query, map[string]interface{}{"version": version, "status": versioning.StatusFailed})
+		if err != nil {
+			return fmt.Errorf("error marking version failed with query: %s, %v", query, err)
 		}
+		return nil
 	}
-	return "", fmt.Errorf("failed to retrieve UUID for volume: %s", volume.ID)
-}
-
-// CreateVolumeNode always creates a new volume node and returns its UUID
-func (r *Neo4jRepository) CreateSnapshotNode(version string, snapshot dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	query := `
-    CREATE (s:Snapshot {
-		uuid: apoc.create.uuid(),
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		status: $status,
-		size: $size,
-		createdAt: $createdAt,
-		updatedAt: $updatedAt,
-		description: $description,
-		userID: $userID,
-		groupSnapshotID: $groupSnapshotID
-
-		
-	})
-	RETURN s.uuid as uuid
-	`
 
-	parameters := map[string]interface{}{
-		"version":         version,
-		"id":              snapshot.ID,
-		"name":            snapshot.Name,
-		"type":            snapshot.Type,
-		"status":          snapshot.Metadata["Status"],
-		"size":            snapshot.Metadata["Size"],
-		"createdAt":       snapshot.Metadata["CreatedAt"],
-		"updatedAt":       snapshot.Metadata["UpdatedAt"],
-		"description":     snapshot.Metadata["Description"],
-		"userID":          snapshot.Metadata["UserID"],
-		"groupSnapshotID": snapshot.Metadata["GroupSnapshotID"],
+	if r.temporal() {
+		if err := r.closeVersion(tx, version); err != nil {
+			return err
+		}
 	}
 
-	result, err := session.Run(query, parameters)
+	// Failed versions stay out of the NEXT_VERSION chain
+	query := fmt.Sprintf(`
+		MATCH (mNew:Metadata {version: $version})
+		OPTIONAL MATCH (mOld:Metadata)
+		WHERE mOld <> mNew AND %s
+		WITH mNew, mOld
+		ORDER BY mOld.scanTimestamp DESC
+		LIMIT 1
+		SET mNew.status = $complete
+		FOREACH (old IN CASE WHEN mOld IS NULL THEN [] ELSE [mOld] END |
+			MERGE (old)-[:NEXT_VERSION]->(mNew)
+		)
+	`, completeVersion("mOld"))
+	err := runTx(tx, query, map[string]interface{}{"version": version, "complete": versioning.StatusComplete})
 	if err != nil {
-		return "", fmt.Errorf("error creating snapshot node: %s, %v", query, err)
-	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
-		}
+		return fmt.Errorf("error publishing version with query: %s, %v", query, err)
 	}
-	return "", fmt.Errorf("failed to retrieve UUID for snapshot: %s", snapshot.ID)
+	return nil
 }
 
-// CreateClusterNode always creates a new clusterNode and returns its UUID
-func (r *Neo4jRepository) CreateClusterNode(version string, clusterNode dataparser.InfrastructureComponent) (uuid string, err error) {
+// FailPendingVersions marks the versions of interrupted scans failed
+func (r *Neo4jRepository) FailPendingVersions() (int, error) {
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
-		return "", err
+		return 0, fmt.Errorf("error creating Neo4j session: %v", err)
 	}
 	defer session.Close()
 
-	// Define the CREATE query
 	query := `
-    CREATE (n:ClusterNode {
-		uuid: apoc.create.uuid(), 
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		createdAt: $createdAt
-	})
-	RETURN n.uuid as uuid
-    `
-
-	// Define the parameters
-	parameters := map[string]interface{}{
-		"version":   version,
-		"id":        clusterNode.ID,
-		"name":      clusterNode.Name,
-		"type":      clusterNode.Type,
-		"createdAt": clusterNode.Metadata["CreatedAt"], // assuming createdAt exists in the Metadata
-	}
-
-	result, err := session.Run(query, parameters)
+		MATCH (m:Metadata {status: $pending})
+		SET m.status = $failed
+		RETURN count(m) AS failed
+	`
+	result, err := session.Run(query, map[string]interface{}{"pending": versioning.StatusPending, "failed": versioning.StatusFailed})
 	if err != nil {
-		return "", fmt.Errorf("error creating ClusterNode: %s, %v", query, err)
+		return 0, fmt.Errorf("error failing pending versions with query: %s, %v", query, err)
 	}
-
 	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
+		if failed, ok := result.Record().Get("failed"); ok {
+			if n, ok := failed.(int64); ok {
+				return int(n), nil
 			}
 		}
 	}
-
-	return "", nil
+	return 0, nil
 }
 
-// CreatePod always creates a new pod and returns its UUID
-func (r *Neo4jRepository) CreatePodNode(version string, pod dataparser.InfrastructureComponent) (uuid string, err error) {
+// UpdateMetadataScanResult records the outcome of a scan on the version's Metadata node
+func (r *Neo4jRepository) UpdateMetadataScanResult(version string, scanResult dataparser.ScanResult) error {
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("error creating Neo4j session: %v", err)
 	}
 	defer session.Close()
 
-	// Define the CREATE query
-	query := `
-    CREATE (p:Pod {
-		uuid: apoc.create.uuid(), 
-		version: $version,
-		id: $id,
-		name: $name,
-		type: $type,
-		createdAt: $createdAt,
-		storage: $storage
-	})
-	RETURN p.uuid as uuid
-    `
-
-	// Define the parameters
-	parameters := map[string]interface{}{
-		"version":   version,
-		"id":        pod.ID,
-		"name":      pod.Name,
-		"type":      pod.Type,
-		"createdAt": pod.Metadata["CreatedAt"], // assuming createdAt exists in the Metadata
-		"storage":   pod.Metadata["Volumes"],
-	}
-
-	result, err := session.Run(query, parameters)
+	resultJSON, err := scanResult.JSON()
 	if err != nil {
-		return "", fmt.Errorf("error creating Pod: %s, %v", query, err)
+		return fmt.Errorf("error encoding scan result: %v", err)
 	}
 
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
+	var failedProviders []string
+	for _, p := range scanResult.Providers {
+		if p.Status != dataparser.ScanStatusOK {
+			failedProviders = append(failedProviders, p.Provider)
 		}
 	}
 
-	return "", nil
-}
-
-func (r *Neo4jRepository) CreatePVNode(version string, pv dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	// Define the CREATE query
 	query := `
-    CREATE (pv:PersistentVolume {
-        uuid: apoc.create.uuid(),
-        version: $version,
-        id: $id,
-        name: $name,
-        type: $type,
-        createdAt: $createdAt
-    })
-    RETURN pv.uuid as uuid
-    `
+		MATCH (m:Metadata {version: $version})
+		SET m.scanStatus = $status,
+			m.complete = $complete,
+			m.scanDuration = $duration,
+			m.failedProviders = $failedProviders,
+			m.scanResult = $scanResult
+	`
 
-	// Define the parameters
 	parameters := map[string]interface{}{
-		"version":   version,
-		"id":        pv.ID,
-		"name":      pv.Name,
-		"type":      pv.Type,
-		"createdAt": pv.Metadata["CreatedAt"],
+		"version":         version,
+		"status":          scanResult.Status(),
+		"complete":        scanResult.Complete(),
+		"duration":        scanResult.Duration.Milliseconds(),
+		"failedProviders": failedProviders,
+		"scanResult":      resultJSON,
 	}
 
-	result, err := session.Run(query, parameters)
+	err = run(session, query, parameters)
 	if err != nil {
-		return "", fmt.Errorf("error creating PersistentVolume: %s, %v", query, err)
+		return fmt.Errorf("error updating Metadata node with query: %s, %v", query, err)
 	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
-		}
-	}
-
-	return "", nil
+	return nil
 }
 
-func (r *Neo4jRepository) CreatePVCNode(version string, pvc dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
+// GetMetadata returns the Metadata node of a version together with the projects scanned in it
+func (r *Neo4jRepository) GetMetadata(ctx context.Context, version string) (*model.Metadata, error) {
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer session.Close()
 
-	// Define the CREATE query
 	query := `
-    CREATE (pvc:PersistentVolumeClaim {
-        uuid: apoc.create.uuid(),
-        version: $version,
-        id: $id,
-        name: $name,
-        type: $type
-    })
-    RETURN pvc.uuid as uuid
-    `
-
-	// Define the parameters
+		MATCH (m:Metadata {version: $version})
+		RETURN m.version, m.scanTimestamp, m.status, m.scanStatus, m.scanResult, coalesce(m.complete, false) AS complete
+	`
 	parameters := map[string]interface{}{
 		"version": version,
-		"id":      pvc.ID,
-		"name":    pvc.Name,
-		"type":    pvc.Type,
 	}
 
-	//logger.Debug(logger.LogFields{"PVC TYPE": pvc.Type})
 	result, err := session.Run(query, parameters)
 	if err != nil {
-		return "", fmt.Errorf("error creating PersistentVolumeClaim: %s, %v", query, err)
+		return nil, err
 	}
-
-	if result.Next() {
-		rawUUID, ok := result.Record().Get("uuid")
-		if ok && rawUUID != nil {
-			uuidStr, ok := rawUUID.(string)
-			if ok {
-				return uuidStr, nil
-			}
-		}
+	if !result.Next() {
+		return nil, fmt.Errorf("no metadata node found for version %s", version)
 	}
 
-	return "", nil
-}
-
-func (r *Neo4jRepository) CreatePDNode(version string, pd dataparser.InfrastructureComponent) (uuid string, err error) {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	query := `
-    CALL apoc.create.node(['PDIndicator'], {
-        uuid: apoc.create.uuid(),
-        version: $version,
-        id: $id,
-        name: $name,
-        type: $type
-    })
-    YIELD node AS pd
-    WITH pd, $dataDisclosed.dataCategories AS dataCategories
-    UNWIND dataCategories AS category
-    CREATE (pd)-[:HAS_CATEGORY]->(:DataCategory {
-        name: category.name,
-        purpose: category.purpose,
-        legalBasis: category.legalBasis,
-        storage: category.storage
-    })
-    RETURN pd.uuid as uuid
-    `
-
-	parameters := map[string]interface{}{
-		"version": version,
-		"id":      pd.ID,
-		"name":    pd.Name,
-		"type":    pd.Type,
+	metadata := &model.Metadata{Projects: []*model.Project{}}
+	record := result.Record()
+	if err := ParseCypherQueryResult(record, "m", metadata); err != nil {
+		return nil, err
 	}
-
-	if pdJSON, ok := pd.Metadata["has_pd"].(string); ok {
-		var pdData map[string]interface{}
-		if err := json.Unmarshal([]byte(pdJSON), &pdData); err != nil {
-			return "", fmt.Errorf("error unmarshalling PD data: %v", err)
-		}
-		parameters["dataDisclosed"] = pdData
-	} else {
-		return "", fmt.Errorf("PD data is not a valid JSON string: %v", pd.Metadata["has_pd"])
+	if complete, ok := record.Get("complete"); ok {
+		metadata.Complete, _ = complete.(bool)
 	}
 
-	result, err := session.Run(query, parameters)
+	projectQuery := `
+		MATCH (m:Metadata {version: $version})-[:SCANNED]->(p:Project)
+		RETURN p.uuid, p.id, p.provider, p.name, p.type, p.availabilityZone, p.enabled, p.description
+	`
+	result, err = session.Run(projectQuery, parameters)
 	if err != nil {
-		return "", fmt.Errorf("error running Cypher query: %s, %v", query, err)
+		return nil, err
 	}
-
-	if result.Next() {
-		return result.Record().GetByIndex(0).(string), nil
+	for result.Next() {
+		project := &model.Project{Instances: []*model.Instance{}}
+		if err := ParseCypherQueryResult(result.Record(), "p", project); err != nil {
+			return nil, err
+		}
+		metadata.Projects = append(metadata.Projects, project)
 	}
 
-	return "", fmt.Errorf("no UUID returned by query: %s", query)
+	return metadata, nil
 }
 
 // LinkVolumeToInstance creates a relationship between a volume and attached Instances
@@ -835,11 +589,13 @@ func (r *Neo4jRepository) CreateOrUpdateServer(instance dataparser.Infrastructur
 		"updated":          GetMetadataValue(instance.Metadata, "Updated", ""),
 		"volumesAttached":  GetMetadataValue(instance.Metadata, "VolumesAttached", ""),
 		"status":           GetMetadataValue(instance.Metadata, "Status", ""),
+		"vpcID":            GetMetadataValue(instance.Metadata, "VpcID", nil),
+		"subnetID":         GetMetadataValue(instance.Metadata, "SubnetID", nil),
 	}
 
 	_, err = session.Run(query, parameters)
 	if err != nil {
-		return fmt.Errorf("error creating instance in Neo4j", err)
+		return fmt.Errorf("error creating instance in Neo4j: %v", err)
 	} else { // if no err create relationship
 		//logger.Debug("Created instance in Neo4j", logger.LogFields{"instance_id": instance.ID})
 
@@ -908,10 +664,12 @@ func (r *Neo4jRepository) CreateOrUpdateClusterNode(clusterNode dataparser.Infra
 
 	// Define the parameters
 	parameters := map[string]interface{}{
-		"id":        clusterNode.ID,
-		"name":      clusterNode.Name,
-		"type":      clusterNode.Type,
-		"createdAt": clusterNode.Metadata["CreatedAt"], // assuming createdAt exists in the Metadata
+		"id":         clusterNode.ID,
+		"name":       clusterNode.Name,
+		"type":       clusterNode.Type,
+		"createdAt":  clusterNode.Metadata["CreatedAt"], // assuming createdAt exists in the Metadata
+		"providerID": clusterNode.Metadata["ProviderID"],
+		"systemUUID": clusterNode.Metadata["SystemUUID"],
 	}
 
 	_, err = session.Run(query, parameters)
@@ -975,17 +733,19 @@ func (r *Neo4jRepository) CreateOrUpdatePod(pod dataparser.InfrastructureCompone
 	return err
 }
 
-// FindInstanceByUUID finds a Instance by its uuid
+// GetPdsWithCategory returns the pods processing a data category, declared in
+// a has_pd annotation or in the TILT document describing them
 func (r *Neo4jRepository) GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) {
-	query := `
-		MATCH (p:Pod)-[:HAS_PD]->(pd:PDIndicator)-[:HAS_CATEGORY]->(dc:DataCategory)
-		WHERE p.version = $version AND dc.name = $categoryName
-		RETURN p.id, p.name, p.type, p.createdAt, p.storage
-	`
-	parameters := map[string]interface{}{
-		"version":      version,
-		"categoryName": categoryName,
+	query := fmt.Sprintf(`
+		MATCH (p:Pod)-[h:HAS_PD|DESCRIBED_BY]->(d)-[c:HAS_CATEGORY]->(dc:DataCategory)
+		WHERE %s AND dc.name = $categoryName
+		RETURN DISTINCT p.id, p.provider, p.name, p.type, p.namespace, p.createdAt, p.storage
+	`, r.at("p", "h", "d", "c", "dc"))
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return nil, err
 	}
+	parameters["categoryName"] = categoryName
 
 	session, err := r.Connection.Session(neo4j.AccessModeRead)
 
@@ -1015,6 +775,117 @@ func (r *Neo4jRepository) GetPdsWithCategory(ctx context.Context, version string
 	return pods, nil
 }
 
+// GetExposedPdsWithCategory returns the pods processing a data category that
+// are reachable from outside the cluster, through an Ingress or an exposed Service.
+func (r *Neo4jRepository) GetExposedPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) {
+	query := fmt.Sprintf(`
+		MATCH (p:Pod)-[h:HAS_PD|DESCRIBED_BY]->(d)-[c:HAS_CATEGORY]->(dc:DataCategory)
+		WHERE %s AND dc.name = $categoryName
+			AND (EXISTS { MATCH (p)<-[sel:SELECTS]-(svc:Service)<-[rt:ROUTES_TO]-(ing:Ingress) WHERE %s }
+				OR EXISTS { MATCH (p)<-[sel:SELECTS]-(svc:Service {exposed: true}) WHERE %s })
+		RETURN DISTINCT p.id, p.provider, p.name, p.type, p.namespace, p.createdAt, p.storage
+	`, r.at("p", "h", "d", "c", "dc"), r.at("svc", "sel", "rt", "ing"), r.at("svc", "sel"))
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return nil, err
+	}
+	parameters["categoryName"] = categoryName
+
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	result, err := session.Run(query, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*model.Pod
+	for result.Next() {
+		pod := &model.Pod{}
+		if err := ParseCypherQueryResult(result.Record(), "p", pod); err != nil {
+			return nil, err
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+// GetPublicInstancesWithPds returns the instances running pods with personal
+// data that are reachable through a floating IP.
+func (r *Neo4jRepository) GetPublicInstancesWithPds(ctx context.Context, version string) ([]*model.Instance, error) {
+	query := fmt.Sprintf(`
+		MATCH (p:Pod)-[h:HAS_PD]->(pd:PDIndicator), (p)-[ro:RUNS_ON]->(n:ClusterNode)-[pb:PROVISIONED_BY]->(i:Instance)-[hf:HAS_FLOATING_IP]->(fip:FloatingIP)
+		WHERE %s
+		RETURN DISTINCT i.id, i.provider, i.name, i.type, i.availabilityZone, i.hostID, i.tenantID, i.status
+	`, r.at("p", "h", "pd", "ro", "n", "pb", "i", "hf", "fip"))
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	result, err := session.Run(query, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []*model.Instance
+	for result.Next() {
+		instance := &model.Instance{}
+		if err := ParseCypherQueryResult(result.Record(), "i", instance); err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// GetFindings returns the findings of a version, e.g. has_pd annotations not matching their schema.
+func (r *Neo4jRepository) GetFindings(ctx context.Context, version string) ([]*model.Finding, error) {
+	query := fmt.Sprintf(`
+		MATCH (f:Finding)
+		WHERE %s
+		RETURN f.uuid, f.id, f.provider, f.name, f.type, f.kind, f.schemaVersion, f.violations, f.annotation, f.subject, f.subjectType
+		ORDER BY f.subjectType, f.subject
+	`, r.at("f"))
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	result, err := session.Run(query, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []*model.Finding
+	for result.Next() {
+		finding := &model.Finding{}
+		if err := ParseCypherQueryResult(result.Record(), "f", finding); err != nil {
+			return nil, err
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
 func (r *Neo4jRepository) FindInstanceByProjectID(ctx context.Context, projectID string) ([]*model.Instance, error) {
 
 	query := `
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
+)
+
+// NodeType describes how components of a type are written. Types that aren't
+// registered are written with their type as only label and their metadata
+// under the default property names.
+type NodeType struct {
+	// Labels of the node, the component type if empty
+	Labels []string
+	// Properties maps metadata keys onto property names other than the default
+	Properties map[string]string
+	// Row returns extra fields of the component's row for Then
+	Row func(component dataparser.InfrastructureComponent) (map[string]interface{}, error)
+	// Then is Cypher run after the node is written, with the node bound to n
+	// and the component's row to row
+	Then string
+}
+
+// NodeTypes registers the component types produced by the transformers
+var NodeTypes = map[string]NodeType{
+	"Project":               {},
+	"Instance":              {},
+	"PhysicalHost":          {},
+	"Volume":                {Properties: map[string]string{"snapshotID": "srcSnapshot"}},
+	"Snapshot":              {},
+	"ClusterNode":           {},
+	"Pod":                   {Properties: map[string]string{"Volumes": "storage"}},
+	"PersistentVolume":      {},
+	"PersistentVolumeClaim": {},
+	"PDIndicator":           {Row: pdRow, Then: pdCategories},
+	"Finding":               {},
+	"ResourceEvent":         {},
+	"Namespace":             {},
+	"Service":               {},
+	"Ingress":               {},
+	"Network":               {},
+	"Subnet":                {},
+	"Port":                  {},
+	"Router":                {},
+	"FloatingIP":            {},
+	"SecurityGroup":         {},
+	"Image":                 {},
+	"Flavor":                {Properties: map[string]string{"VCPUs": "vcpus"}},
+	"ObjectContainer":       {},
+	"TransparencyDocument":  {},
+	"DataCategory":          {},
+	"Controller":            {},
+	"Purpose":               {},
+	"LegalBasis":            {},
+	// Workload controllers get their kind as second label
+	"Deployment":  {Labels: []string{"Workload", "Deployment"}},
+	"StatefulSet": {Labels: []string{"Workload", "StatefulSet"}},
+	"DaemonSet":   {Labels: []string{"Workload", "DaemonSet"}},
+	"ReplicaSet":  {Labels: []string{"Workload", "ReplicaSet"}},
+	"Job":         {Labels: []string{"Workload", "Job"}},
+	"CronJob":     {Labels: []string{"Workload", "CronJob"}},
+	// Storage backing PVs other than volumes of a cloud provider
+	"CSIVolume":      {Labels: []string{"Storage", "CSIVolume"}},
+	"NFSShare":       {Labels: []string{"Storage", "NFSShare"}},
+	"HostPathVolume": {Labels: []string{"Storage", "HostPathVolume"}},
+	"LocalVolume":    {Labels: []string{"Storage", "LocalVolume"}},
+}
+
+// RegisterNodeType registers how components of a type are written
+func RegisterNodeType(componentType string, nodeType NodeType) {
+	NodeTypes[componentType] = nodeType
+}
+
+// labels returns the labels of a component type
+func (t NodeType) labels(componentType string) []string {
+	if len(t.Labels) > 0 {
+		return t.Labels
+	}
+	return []string{componentType}
+}
+
+// KnownLabels returns the labels of all registered types
+func KnownLabels() []string {
+	seen := make(map[string]bool)
+	var labels []string
+	for componentType, t := range NodeTypes {
+		for _, label := range t.labels(componentType) {
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// CreateNodes upserts components as nodes identified by their ID, provider
+// and version, see NodeProperties for their properties. In temporal storage a
+// node only gets a new record if its properties changed since the last
+// version. Components are written in batches of BatchSize per type, a failed
+// batch is reported and the others are still written.
+func (r *Neo4jRepository) CreateNodes(version string, components []dataparser.InfrastructureComponent) (written int, errs []string) {
+	// Group by type, keeping the order types are first seen in
+	var types []string
+	rows := make(map[string][]map[string]interface{})
+	for _, component := range components {
+		if component.Type == "" {
+			errs = append(errs, fmt.Sprintf("component %s has no type", component.ID))
+			continue
+		}
+		row, err := nodeRow(component)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", component.Type, component.ID, err))
+			continue
+		}
+		if r.temporal() {
+			row["stateHash"] = stateHash(row)
+		}
+		if _, seen := rows[component.Type]; !seen {
+			types = append(types, component.Type)
+		}
+		rows[component.Type] = append(rows[component.Type], row)
+	}
+	if len(types) == 0 {
+		return 0, errs
+	}
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return 0, append(errs, err.Error())
+	}
+
+	session, err := r.Connection.Session(neo4j.AccessModeWrite)
+	if err != nil {
+		return 0, append(errs, err.Error())
+	}
+	defer session.Close()
+
+	for _, componentType := range types {
+		nodeType := NodeTypes[componentType]
+		labels := nodeType.labels(componentType)
+		// apoc.merge.node takes the labels as parameter, nothing is spliced into the query but Then
+		query := fmt.Sprintf(`
+    UNWIND $rows AS row
+    CALL apoc.merge.node($labels, {id: row.id, provider: row.provider, version: $version}, row.properties, row.properties)
+    YIELD node AS n
+    SET n.uuid = coalesce(n.uuid, apoc.create.uuid())
+    %s
+    `, nodeType.Then)
+		if r.temporal() {
+			// The labels are spliced into the query, types outside the
+			// registry name them after the mapping's type
+			if err := r.prepareTemporalLabels(session, labels); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", componentType, err))
+				continue
+			}
+			query = temporalNodeQuery(labels, nodeType.Then)
+		}
+		parameters["labels"] = labels
+
+		for _, batch := range batches(rows[componentType], r.batchSize()) {
+			parameters["rows"] = batch
+			if err := run(session, query, parameters); err != nil {
+				errs = append(errs, fmt.Sprintf("%s batch of %d: %v", componentType, len(batch), err))
+				continue
+			}
+			written += len(batch)
+		}
+	}
+	return written, errs
+}
+
+// nodeRow is the UNWIND row of a component
+func nodeRow(component dataparser.InfrastructureComponent) (map[string]interface{}, error) {
+	nodeType := NodeTypes[component.Type]
+	row := map[string]interface{}{
+		"id":         component.ID,
+		"provider":   component.Provider,
+		"properties": NodeProperties(component, nodeType.Properties),
+	}
+	if nodeType.Row != nil {
+		extra, err := nodeType.Row(component)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range extra {
+			row[k] = v
+		}
+	}
+	return row, nil
+}
+
+// NodeProperties returns the properties of a component: its name, type and
+// availability zone, and its metadata keys in lower camel case, e.g. CreatedAt
+// becomes createdAt and IPAddresses ipAddresses, unless renamed. Nested maps
+// are flattened into parent_child properties, values Neo4j can't store are
+// stored as JSON. Nil values are left out.
+func NodeProperties(component dataparser.InfrastructureComponent, renames map[string]string) map[string]interface{} {
+	properties := map[string]interface{}{
+		"name": component.Name,
+		"type": component.Type,
+	}
+	if component.AvailabilityZone != "" {
+		properties["availabilityZone"] = component.AvailabilityZone
+	}
+	for key, value := range component.Metadata {
+		name, renamed := renames[key]
+		if !renamed {
+			name = propertyName(key)
+		}
+		flattenProperty(properties, name, value)
+	}
+	return properties
+}
+
+func flattenProperty(properties map[string]interface{}, name string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case string, bool, int, int32, int64, float32, float64, []string, []int, []int64, []float64, []bool:
+		properties[name] = v
+	case time.Time:
+		properties[name] = v.Format(time.RFC3339)
+	case map[string]interface{}:
+		for key, nested := range v {
+			flattenProperty(properties, name+"_"+propertyName(key), nested)
+		}
+	case map[string]string:
+		for key, nested := range v {
+			properties[name+"_"+propertyName(key)] = nested
+		}
+	case []interface{}:
+		if primitiveList(v) {
+			properties[name] = v
+			return
+		}
+		properties[name] = jsonProperty(v)
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return
+			}
+			flattenProperty(properties, name, rv.Elem().Interface())
+			return
+		}
+		properties[name] = jsonProperty(v)
+	}
+}
+
+// primitiveList tells whether all items of a list have the same primitive
+// type, Neo4j only stores homogeneous lists
+func primitiveList(list []interface{}) bool {
+	var kind reflect.Kind
+	for i, item := range list {
+		if item == nil {
+			return false
+		}
+		k := reflect.TypeOf(item).Kind()
+		switch k {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64:
+		default:
+			return false
+		}
+		if i > 0 && k != kind {
+			return false
+		}
+		kind = k
+	}
+	return true
+}
+
+func jsonProperty(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
+
+// propertyName lower-cases the leading upper case letters of a metadata key,
+// keeping the last one of an acronym followed by a word: FSType becomes fsType.
+func propertyName(key string) string {
+	runes := []rune(key)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		upper--
+	}
+	return strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+}
+
+// pdCategories creates the data categories of a has_pd annotation
+const pdCategories = `
+    FOREACH (category IN row.pd.dataCategories |
+        MERGE (n)-[:HAS_CATEGORY]->(c:DataCategory {name: category.name})
+        SET c.purpose = category.purpose, c.legalBasis = category.legalBasis, c.storage = category.storage
+    )`
+
+func pdRow(pd dataparser.InfrastructureComponent) (map[string]interface{}, error) {
+	pdJSON, ok := pd.Metadata["has_pd"].(string)
+	if !ok {
+		return nil, fmt.Errorf("PD data is not a valid JSON string: %v", pd.Metadata["has_pd"])
+	}
+	var pdData map[string]interface{}
+	if err := json.Unmarshal([]byte(pdJSON), &pdData); err != nil {
+		return nil, fmt.Errorf("error unmarshalling PD data: %v", err)
+	}
+	return map[string]interface{}{"pd": pdData}, nil
+}
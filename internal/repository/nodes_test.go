@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
+)
+
+func TestPropertyName(t *testing.T) {
+	tests := map[string]string{
+		"":            "",
+		"name":        "name",
+		"Name":        "name",
+		"CreatedAt":   "createdAt",
+		"FSType":      "fsType",
+		"IPAddresses": "ipAddresses",
+		"ID":          "id",
+		// A plural acronym reads as acronym and word, NodeTypes renames it
+		"VCPUs":    "vcpUs",
+		"RAM":      "ram",
+		"has_pd":   "has_pd",
+		"ÜberSize": "überSize",
+	}
+	for key, want := range tests {
+		if got := propertyName(key); got != want {
+			t.Errorf("propertyName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestNodeProperties(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	replicas := 3
+	var unset *int
+	component := dataparser.InfrastructureComponent{
+		Name:             "web",
+		Type:             "Pod",
+		AvailabilityZone: "nova",
+		Metadata: map[string]interface{}{
+			"Status":      "Running",
+			"CreatedAt":   created,
+			"IPAddresses": []string{"10.0.0.1", "10.0.0.2"},
+			"Replicas":    &replicas,
+			"Unset":       unset,
+			"Deleted":     nil,
+			"Labels":      map[string]string{"App": "web", "tier": "front"},
+			"Limits": map[string]interface{}{
+				"CPU":    "500m",
+				"Memory": map[string]interface{}{"Max": 512, "Min": nil},
+			},
+			"Ports":  []interface{}{80, 443},
+			"Mixed":  []interface{}{"http", 80},
+			"Holes":  []interface{}{"a", nil},
+			"Nested": []interface{}{map[string]interface{}{"a": 1}},
+			"Empty":  []interface{}{},
+			"Volumes": []struct {
+				Name string `json:"name"`
+			}{{Name: "data"}},
+			"Renamed": "x",
+		},
+	}
+	want := map[string]interface{}{
+		"name":              "web",
+		"type":              "Pod",
+		"availabilityZone":  "nova",
+		"status":            "Running",
+		"createdAt":         "2024-05-01T12:00:00Z",
+		"ipAddresses":       []string{"10.0.0.1", "10.0.0.2"},
+		"replicas":          3,
+		"labels_app":        "web",
+		"labels_tier":       "front",
+		"limits_cpu":        "500m",
+		"limits_memory_max": 512,
+		"ports":             []interface{}{80, 443},
+		"mixed":             `["http",80]`,
+		"holes":             `["a",null]`,
+		"nested":            `[{"a":1}]`,
+		"empty":             []interface{}{},
+		"volumes":           `[{"name":"data"}]`,
+		"storage":           "x",
+	}
+	got := NodeProperties(component, map[string]string{"Renamed": "storage"})
+	if !reflect.DeepEqual(got, want) {
+		for key := range want {
+			if !reflect.DeepEqual(got[key], want[key]) {
+				t.Errorf("%s = %#v, want %#v", key, got[key], want[key])
+			}
+		}
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				t.Errorf("unexpected property %s = %#v", key, got[key])
+			}
+		}
+	}
+}
@@ -2,342 +2,375 @@ package repository
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
 )
 
-func (r *Neo4jRepository) LinkProjectToMetadata(version string, projectUUID string) error {
+// LinkProjectsToMetadata links the projects of a version to its metadata node
+func (r *Neo4jRepository) LinkProjectsToMetadata(version string) error {
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
 		return err
 	}
 	defer session.Close()
 
-	query := `
+	query := fmt.Sprintf(`
 	MATCH (m:Metadata {version: $version})
-	MATCH (p:Project {uuid: $projectUUID})
+	MATCH (p:Project)
+	WHERE %s
 	MERGE (m)-[r:SCANNED]->(p)
-	RETURN m, r, p;
-	`
+	`, r.seenIn("p"))
 
 	parameters := map[string]interface{}{
-		"version":     version,
-		"projectUUID": projectUUID,
+		"version": version,
 	}
 
-	_, err = session.Run(query, parameters)
+	err = run(session, query, parameters)
 	if err != nil {
 		return fmt.Errorf("error creating SCANNED relationship: %s, %v", query, err)
 	}
 
-	//logger.Debug("created SCANNED relationship", logger.LogFields{"version": version, "projectUUID": projectUUID})
-
 	return nil
 }
 
-func (r *Neo4jRepository) CreateInstanceRelationships(instanceID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return err
-	}
-	defer session.Close()
-
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"instanceID": instanceID,
-			"targetID":   rel.Target,
-			"version":    version,
-		}
+// RelationshipType declares a relationship transformers may emit from a
+// component. Relationships are matched on the target's id within the provider
+// and version of the source unless declared otherwise.
+type RelationshipType struct {
+	Source  string   // label of the declaring component
+	Type    string   // type emitted by the transformers
+	Targets []string // labels the target may have
+	MatchOn string   // target property matched against the relationship target, id if empty
+	Edge    string   // type of the written edge, Type if empty
+	// Reverse writes the edge from the target to the source, e.g. a pod
+	// OWNED_BY a workload becomes (workload)-[:OWNS]->(pod)
+	Reverse bool
+	// AnyProvider matches targets of every provider, e.g. the volume of a
+	// cloud provider a PV is stored on
+	AnyProvider bool
+	// Properties is a Cypher map of the edge's properties, with the source
+	// bound to s, the target to t and the UNWIND row to row
+	Properties string
+}
 
-		switch rel.Type {
-
-		case "BELONGS_TO":
-
-			query = `
-            MATCH (i:Instance {id: $instanceID, version: $version}), (p:Project {id: $targetID, version: $version})
-			MERGE (i)-[:BELONGS_TO]->(p)
-			RETURN i, p;
-
-            `
-		case "ASSIGNED_HOST":
-			query = `
-		            MATCH (i:Instance {id: $instanceID, version: $version}), (h:PhysicalHost {id: $targetID, version: $version})
-		            MERGE (i)-[:ASSIGNED_HOST]->(h)
-		            `
-		case "ATTACHED_TO":
-			//logger.Debug("ATTACHED_TO SERVER relationship", logger.LogFields{"instanceID": instanceID, "targetID": rel.Target, "version": version})
-			query = `
-					MATCH (i:Instance {id: $instanceID, version: $version}), (v:Volume {id: $targetID, version: $version})
-					MERGE (i)-[:ATTACHES]->(v)
-					`
-		default:
-			// Unsupported relationship type
-			continue
+// inheritedPD marks HAS_PD edges of declarations inherited from namespaces, controllers and claims
+const inheritedPD = "{inherited: true, inheritedFrom: t.declaredOn}"
+
+// RelationshipTypes are the relationships the writer accepts, anything else is rejected
+var RelationshipTypes = []RelationshipType{
+	{Source: "Instance", Type: "BELONGS_TO", Targets: []string{"Project"}},
+	{Source: "Instance", Type: "ASSIGNED_HOST", Targets: []string{"PhysicalHost"}},
+	{Source: "Instance", Type: "ATTACHED_TO", Targets: []string{"Volume"}, Reverse: true},
+	{Source: "Instance", Type: "HAS_PORT", Targets: []string{"Port"}},
+	{Source: "Instance", Type: "CONNECTED_TO", Targets: []string{"Network"}},
+	{Source: "Instance", Type: "HAS_FLOATING_IP", Targets: []string{"FloatingIP"}},
+	{Source: "Instance", Type: "PROTECTED_BY", Targets: []string{"SecurityGroup"}},
+	{Source: "Instance", Type: "BOOTED_FROM", Targets: []string{"Image"}},
+	{Source: "Instance", Type: "HAS_FLAVOR", Targets: []string{"Flavor"}},
+	{Source: "Volume", Type: "ATTACHED_TO", Targets: []string{"Instance"}},
+	{Source: "Snapshot", Type: "SNAPSHOT_OF", Targets: []string{"Volume"}},
+
+	{Source: "Pod", Type: "RUNS_ON", Targets: []string{"ClusterNode"}, MatchOn: "name"},
+	{Source: "Pod", Type: "USES_PVC", Targets: []string{"PersistentVolumeClaim"}},
+	{Source: "Pod", Type: "HAS_PD", Targets: []string{"PDIndicator"}},
+	{Source: "Pod", Type: "HAS_FINDING", Targets: []string{"Finding"}},
+	{Source: "Pod", Type: "INHERITS_PD", Targets: []string{"PDIndicator"}, Edge: "HAS_PD", Properties: inheritedPD},
+	{Source: "Pod", Type: "OWNED_BY", Targets: []string{"Workload"}, Edge: "OWNS", Reverse: true},
+	{Source: "Pod", Type: "MANAGED_BY", Targets: []string{"Workload"}, Edge: "MANAGES", Reverse: true},
+	{Source: "Pod", Type: "IN_NAMESPACE", Targets: []string{"Namespace"}, MatchOn: "name", Edge: "CONTAINS", Reverse: true},
+	{Source: "Pod", Type: "SELECTED_BY", Targets: []string{"Service"}, Edge: "SELECTS", Reverse: true},
+	{Source: "PersistentVolume", Type: "STORED_ON", Targets: []string{"Volume"}, AnyProvider: true},
+	{Source: "PersistentVolume", Type: "BACKED_BY", Targets: []string{"Storage"}, Edge: "STORED_ON"},
+	{Source: "PersistentVolume", Type: "INHERITS_PD", Targets: []string{"PDIndicator"}, Edge: "HAS_PD", Properties: inheritedPD},
+	{Source: "PersistentVolumeClaim", Type: "BINDS_TO", Targets: []string{"PersistentVolume"}, MatchOn: "name"},
+	{Source: "PersistentVolumeClaim", Type: "HAS_PD", Targets: []string{"PDIndicator"}},
+	{Source: "PersistentVolumeClaim", Type: "HAS_FINDING", Targets: []string{"Finding"}},
+	{Source: "ResourceEvent", Type: "CHANGED", Targets: []string{"Pod", "ClusterNode", "PersistentVolume", "Workload", "Namespace", "Service", "Ingress"}},
+	{Source: "Workload", Type: "OWNED_BY", Targets: []string{"Workload"}, Edge: "OWNS", Reverse: true},
+	{Source: "Workload", Type: "HAS_PD", Targets: []string{"PDIndicator"}},
+	{Source: "Workload", Type: "HAS_FINDING", Targets: []string{"Finding"}},
+	{Source: "Namespace", Type: "HAS_PD", Targets: []string{"PDIndicator"}},
+	{Source: "Namespace", Type: "HAS_FINDING", Targets: []string{"Finding"}},
+	{Source: "Service", Type: "IN_NAMESPACE", Targets: []string{"Namespace"}, MatchOn: "name", Edge: "CONTAINS", Reverse: true},
+	{Source: "Ingress", Type: "IN_NAMESPACE", Targets: []string{"Namespace"}, MatchOn: "name", Edge: "CONTAINS", Reverse: true},
+	{Source: "Ingress", Type: "ROUTES_TO", Targets: []string{"Service"}},
+	{Source: "Storage", Type: "LOCATED_ON", Targets: []string{"ClusterNode"}, MatchOn: "name"},
+
+	{Source: "Network", Type: "BELONGS_TO", Targets: []string{"Project"}},
+	{Source: "Subnet", Type: "PART_OF", Targets: []string{"Network"}},
+	{Source: "Port", Type: "ON_NETWORK", Targets: []string{"Network"}},
+	{Source: "Port", Type: "IN_SUBNET", Targets: []string{"Subnet"}},
+	{Source: "Port", Type: "SECURED_BY", Targets: []string{"SecurityGroup"}},
+	{Source: "Router", Type: "GATEWAY_TO", Targets: []string{"Network"}},
+	{Source: "Router", Type: "CONNECTS", Targets: []string{"Network"}},
+	{Source: "FloatingIP", Type: "ON_NETWORK", Targets: []string{"Network"}},
+	{Source: "FloatingIP", Type: "ASSOCIATED_WITH", Targets: []string{"Port"}},
+	{Source: "SecurityGroup", Type: "BELONGS_TO", Targets: []string{"Project"}},
+	{Source: "ObjectContainer", Type: "BELONGS_TO", Targets: []string{"Project"}},
+	{Source: "ObjectContainer", Type: "HAS_PD", Targets: []string{"PDIndicator"}},
+	{Source: "ObjectContainer", Type: "HAS_FINDING", Targets: []string{"Finding"}},
+
+	{Source: "TransparencyDocument", Type: "CONTROLLED_BY", Targets: []string{"Controller"}},
+	{Source: "TransparencyDocument", Type: "HAS_CATEGORY", Targets: []string{"DataCategory"}},
+	{Source: "DataCategory", Type: "FOR_PURPOSE", Targets: []string{"Purpose"}},
+	{Source: "DataCategory", Type: "BASED_ON", Targets: []string{"LegalBasis"}},
+
+	// Links across providers, see dataparser.ResolveIdentities
+	{Source: "ClusterNode", Type: "PROVISIONED_BY", Targets: []string{"Instance"}, AnyProvider: true, Properties: identityRule},
+	{Source: "Pod", Type: "DESCRIBED_BY", Targets: []string{"TransparencyDocument"}, AnyProvider: true, Properties: identityRule},
+	{Source: "Service", Type: "DESCRIBED_BY", Targets: []string{"TransparencyDocument"}, AnyProvider: true, Properties: identityRule},
+}
 
-		}
+// identityRule records the rule that linked two providers and its confidence
+const identityRule = "{rule: row.rule, confidence: row.confidence}"
 
-		_, err := session.Run(query, parameters)
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
+// RegisterRelationshipType allows a relationship, replacing the declaration of the same source and type
+func RegisterRelationshipType(rt RelationshipType) {
+	for i, existing := range RelationshipTypes {
+		if existing.Source == rt.Source && existing.Type == rt.Type {
+			RelationshipTypes[i] = rt
+			return
 		}
-
 	}
-
-	return nil
+	RelationshipTypes = append(RelationshipTypes, rt)
 }
 
-func (r *Neo4jRepository) CreateClusterNodeRel(nodeID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return err
+// edge is the type of the written edge
+func (rt RelationshipType) edge() string {
+	if rt.Edge == "" {
+		return rt.Type
 	}
-	defer session.Close()
-
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"nodeID":   nodeID,
-			"targetID": rel.Target,
-			"version":  version,
-		}
-
-		switch rel.Type {
-
-		case "PROVISIONED_BY":
-
-			query = `
-            MATCH (n:ClusterNode {id: $nodeID, version: $version}), (i:Instance {id: $targetID,version: $version})
-            MERGE (n)-[:PROVISIONED_BY]->(i)
-			RETURN n, i;
-            `
-
-		default:
-			// Unsupported relationship type
-			continue
+	return rt.Edge
+}
 
+// knownEdgeTypes returns the types of the edges written for RelationshipTypes
+func knownEdgeTypes() []string {
+	seen := make(map[string]bool)
+	var edges []string
+	for _, rt := range RelationshipTypes {
+		if edge := rt.edge(); !seen[edge] {
+			seen[edge] = true
+			edges = append(edges, edge)
 		}
+	}
+	sort.Strings(edges)
+	return edges
+}
 
-		_, err := session.Run(query, parameters)
-		//logger.Debug(query, logger.LogFields{"nodeID": nodeID, "targetID": rel.Target, "version": version})
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
+// relationshipType looks up the declaration of a relationship of a component type
+func relationshipType(componentType string, relType string) (RelationshipType, bool) {
+	source := NodeTypes[componentType].labels(componentType)[0]
+	for _, rt := range RelationshipTypes {
+		if rt.Source == source && rt.Type == relType {
+			return rt, true
 		}
 	}
-
-	return nil
+	return RelationshipType{}, false
 }
 
-func (r *Neo4jRepository) CreatePodRel(podID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return err
+// query builds the MERGE of a batch of relationships. Labels and property
+// names come from the registry, values are parameters: $version and the rows
+// with sourceID, provider, target and targetProvider. It returns the
+// provider, sourceID and target of the row of every edge written. In temporal storage the
+// edge links the current records of source and target and is versioned with
+// its properties, see temporalEdge.
+func (rt RelationshipType) query(temporal bool) string {
+	matchOn := rt.MatchOn
+	if matchOn == "" {
+		matchOn = "id"
 	}
-	defer session.Close()
-
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"podID":   podID,
-			"target":  rel.Target,
-			"version": version,
-		}
+	edge := rt.edge()
 
-		switch rel.Type {
-
-		case "RUNS_ON":
-			query = `
-            MATCH (p:Pod {id: $podID,version: $version}), (c:ClusterNode {name: $target, version: $version})
-            MERGE (p)-[:RUNS_ON]->(c)
-			RETURN p, c;
-            `
-		case "USES_PVC":
-			query = `
-            MATCH (p:Pod {id: $podID,version: $version}), (pvc:PersistentVolumeClaim {name: $target, version: $version})
-            MERGE (p)-[:USES_PVC]->(pvc)
-			RETURN p, pvc;
-            `
-		case "HAS_PD":
-			query = `
-			MATCH (p:Pod {id: $podID,version: $version}), (pd:PDIndicator {id: $target, version: $version})
-            MERGE (p)-[:HAS_PD]->(pd)
-			RETURN p, pd;
-            `
-
-		default:
-			// Unsupported relationship type
-			continue
-
-		}
+	version := ", version: $version"
+	var where []string
+	if temporal {
+		version = ""
+		where = append(where, current("s"), current("t"))
+	}
 
-		_, err := session.Run(query, parameters)
-		//logger.Debug(query, logger.LogFields{"podID": podID, "targetName": rel.Target, "version": version})
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
+	var target string
+	if len(rt.Targets) == 1 {
+		target = fmt.Sprintf("(t:%s {%s: row.target%s})", rt.Targets[0], matchOn, version)
+	} else {
+		target = fmt.Sprintf("(t {%s: row.target%s})", matchOn, version)
+		var labels []string
+		for _, label := range rt.Targets {
+			labels = append(labels, "t:"+label)
 		}
-
+		where = append(where, "("+strings.Join(labels, " OR ")+")")
 	}
-
-	return nil
-}
-
-// HandleAttachedToRelationship creates an AttachedTo relationship between volume and  server
-func (r *Neo4jRepository) CreateVolumeRel(volumeID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return err
+	if !rt.AnyProvider {
+		where = append(where, "t.provider = row.provider")
+	} else {
+		where = append(where, "(row.targetProvider IS NULL OR t.provider = row.targetProvider)")
 	}
-	defer session.Close()
 
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"volumeID": volumeID,
-			"target":   rel.Target,
-			"version":  version,
-		}
+	from, to := "s", "t"
+	if rt.Reverse {
+		from, to = "t", "s"
+	}
+	properties := rt.Properties
+	if properties == "" {
+		properties = "{}"
+	}
+	merge := fmt.Sprintf("MERGE (%s)-[r:%s]->(%s)\n    SET r += props", from, edge, to)
+	if temporal {
+		merge = temporalEdge(from, edge, to)
+	}
 
-		switch rel.Type {
+	return fmt.Sprintf(`
+    UNWIND $rows AS row
+    MATCH (s:%s {id: row.sourceID, provider: row.provider%s}), %s
+    WHERE %s
+    WITH s, t, row, %s AS props
+    %s
+    RETURN row.provider AS provider, row.sourceID AS sourceID, row.target AS target
+    `, rt.Source, version, target, strings.Join(where, " AND "), properties, merge)
+}
 
-		case "ATTACHED_TO":
-			query = `
-			MATCH (v:Volume {id: $volumeID, version: $version}), (i:Instance {id: $target, version: $version})
-			MERGE (v)-[:ATTACHED_TO]->(i)
-            `
+// relationshipRows collects the rows of each relationship type, in the order
+// the types are first seen in
+type relationshipRows struct {
+	types []RelationshipType
+	rows  map[string][]map[string]interface{} // by source label and type
+}
 
-		default:
-			// Unsupported relationship type
-			continue
+func (rr *relationshipRows) add(rt RelationshipType, row map[string]interface{}) {
+	if rr.rows == nil {
+		rr.rows = make(map[string][]map[string]interface{})
+	}
+	key := rt.Source + "/" + rt.Type
+	if _, seen := rr.rows[key]; !seen {
+		rr.types = append(rr.types, rt)
+	}
+	rr.rows[key] = append(rr.rows[key], row)
+}
 
+// CreateRelationships writes the relationships of components, in batches of
+// BatchSize per relationship type. Relationships not declared in
+// RelationshipTypes are rejected and reported, as are failed batches.
+// Relationships whose target isn't found in the version are returned as
+// unmatched, they don't fail the scan.
+func (r *Neo4jRepository) CreateRelationships(version string, components []dataparser.InfrastructureComponent) (written int, unmatched []string, errs []string) {
+	var rows relationshipRows
+	for _, component := range components {
+		var rejected []string
+		for _, rel := range component.Relationships {
+			rt, ok := relationshipType(component.Type, rel.Type)
+			if !ok {
+				rejected = append(rejected, rel.Type)
+				continue
+			}
+			rows.add(rt, map[string]interface{}{
+				"sourceID":       component.ID,
+				"provider":       component.Provider,
+				"target":         rel.Target,
+				"targetProvider": nil,
+			})
 		}
-		_, err := session.Run(query, parameters)
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
+		if len(rejected) > 0 {
+			errs = append(errs, fmt.Sprintf("%s %s relationships: unsupported %s", component.Type, component.ID, strings.Join(rejected, ", ")))
 		}
-		//logger.Debug(query, logger.LogFields{"volumeID": volumeID, "targetID": rel.Target, "version": version})
-
 	}
 
-	return nil
+	n, unmatched, batchErrs := r.writeRelationships(version, rows)
+	return n, unmatched, append(errs, batchErrs...)
 }
 
-// HandleAttachedToRelationship creates an AttachedTo relationship between volume and  server
-func (r *Neo4jRepository) CreateSnapshotRel(snapshotID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
-	if err != nil {
-		return err
-	}
-	defer session.Close()
-
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"snapshotID": snapshotID,
-			"target":     rel.Target,
-			"version":    version,
-		}
-
-		switch rel.Type {
-
-		case "SNAPSHOT_OF":
-			query = `
-			MATCH (s:Snapshot {id: $snapshotID, version: $version}), (v:Volume {id: $target, version: $version})
-			MERGE (s)-[:SNAPSHOT_OF]->(v)
-            `
-
-		default:
-			// Unsupported relationship type
+// CreateIdentityRels links components of two providers, e.g. cluster nodes to
+// the instances they run on, recording the rule that matched and its confidence on the edge.
+// Links are resolved from the components of the version, a link whose target
+// isn't found is reported as error.
+func (r *Neo4jRepository) CreateIdentityRels(version string, links []dataparser.IdentityLink) (written int, errs []string) {
+	var rows relationshipRows
+	for _, link := range links {
+		rt, ok := relationshipType(link.SourceType, link.Type)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s %s: unsupported cross-provider relationship of %s", link.Type, link.SourceID, link.SourceType))
 			continue
-
 		}
-		_, err := session.Run(query, parameters)
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
-		}
-		//logger.Debug(query, logger.LogFields{"volumeID": volumeID, "targetID": rel.Target, "version": version})
-
+		rows.add(rt, map[string]interface{}{
+			"sourceID":       link.SourceID,
+			"provider":       link.SourceProvider,
+			"target":         link.TargetID,
+			"targetProvider": link.TargetProvider,
+			"rule":           link.Rule,
+			"confidence":     link.Confidence,
+		})
 	}
 
-	return nil
+	n, unmatched, batchErrs := r.writeRelationships(version, rows)
+	return n, append(append(errs, unmatched...), batchErrs...)
 }
 
-func (r *Neo4jRepository) CreatePVRel(pvID string, version string, relationships []dataparser.Relationship) error {
+// writeRelationships writes the rows of each relationship type and returns
+// the number of edges written. Rows that matched no source or target are
+// returned as unmatched, rows of failed batches as errors.
+func (r *Neo4jRepository) writeRelationships(version string, rows relationshipRows) (written int, unmatched []string, errs []string) {
+	if len(rows.types) == 0 {
+		return 0, nil, nil
+	}
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return 0, nil, []string{err.Error()}
+	}
 	session, err := r.Connection.Session(neo4j.AccessModeWrite)
 	if err != nil {
-		return err
+		return 0, nil, []string{err.Error()}
 	}
 	defer session.Close()
 
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"pvID":     pvID,
-			"targetID": rel.Target,
-			"version":  version,
+	for _, rt := range rows.types {
+		query := rt.query(r.temporal())
+		for _, batch := range batches(rows.rows[rt.Source+"/"+rt.Type], r.batchSize()) {
+			parameters["rows"] = batch
+			matched, edges, err := runMatched(session, query, parameters)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s %s batch of %d: %v", rt.Source, rt.Type, len(batch), err))
+				continue
+			}
+			written += edges
+			unmatched = append(unmatched, unmatchedRows(rt, batch, matched)...)
 		}
-
-		switch rel.Type {
-
-		case "STORED_ON":
-			query = `
-            MATCH (pv:PersistentVolume {id: $pvID,version: $version}), (v:Volume {id: $targetID, version: $version})
-            MERGE (pv)-[:STORED_ON]->(v)
-			RETURN pv, v;
-            `
-		default:
-			// Unsupported relationship type
-			continue
-
-		}
-
-		_, err := session.Run(query, parameters)
-		//logger.Debug(query, logger.LogFields{"pvID": pvID, "target Volume": rel.Target, "version": version})
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
-		}
-
 	}
+	return written, unmatched, errs
+}
 
-	return nil
+// rowKey identifies the source and target of a relationship row
+func rowKey(provider interface{}, sourceID interface{}, target interface{}) string {
+	return fmt.Sprintf("%v\x00%v\x00%v", provider, sourceID, target)
 }
 
-func (r *Neo4jRepository) CreatePVCRel(pvcID string, version string, relationships []dataparser.Relationship) error {
-	session, err := r.Connection.Session(neo4j.AccessModeWrite)
+// runMatched runs a relationship query and returns the keys of the rows that
+// were written and the number of edges written for them
+func runMatched(session neo4j.Session, query string, parameters map[string]interface{}) (map[string]bool, int, error) {
+	result, err := session.Run(query, parameters)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	defer session.Close()
-
-	for _, rel := range relationships {
-		var query string
-		parameters := map[string]interface{}{
-			"pvcID":   pvcID,
-			"target":  rel.Target,
-			"version": version,
-		}
-
-		switch rel.Type {
-
-		case "BINDS_TO":
-			query = `
-            MATCH (pvc:PersistentVolumeClaim {id: $pvcID,version: $version}), (pv:PersistentVolume {name: $target, version: $version})
-            MERGE (pvc)-[:BINDS_TO]->(pv)
-			RETURN pvc, pv;
-            `
-		default:
-			// Unsupported relationship type
-			continue
-
-		}
+	matched := make(map[string]bool)
+	edges := 0
+	for result.Next() {
+		record := result.Record()
+		provider, _ := record.Get("provider")
+		sourceID, _ := record.Get("sourceID")
+		target, _ := record.Get("target")
+		matched[rowKey(provider, sourceID, target)] = true
+		edges++
+	}
+	return matched, edges, result.Err()
+}
 
-		_, err := session.Run(query, parameters)
-		//logger.Debug(query, logger.LogFields{"pvcID": pvcID, "targetName": rel.Target, "version": version})
-		if err != nil {
-			return fmt.Errorf("error creating %s relationship: %v", rel.Type, err)
+// unmatchedRows describes the rows of a batch no edge was written for
+func unmatchedRows(rt RelationshipType, batch []map[string]interface{}, matched map[string]bool) []string {
+	var unmatched []string
+	for _, row := range batch {
+		if !matched[rowKey(row["provider"], row["sourceID"], row["target"])] {
+			unmatched = append(unmatched, fmt.Sprintf("%s %v %s %v: target not found", rt.Source, row["sourceID"], rt.Type, row["target"]))
 		}
-
 	}
-
-	return nil
+	return unmatched
 }
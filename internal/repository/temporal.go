@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/regulatory-transparency-monitor/graph-builder/internal/versioning"
+)
+
+// Storage models of the graph
+const (
+	// StorageVersioned writes a copy of every node and edge per version,
+	// identified by its version property
+	StorageVersioned = "versioned"
+	// StorageTemporal writes a node or edge once and keeps it valid from the
+	// version it was first seen in (validFrom) to the version it changed or
+	// disappeared in (validTo, unset while it is current). Only changes create
+	// new records.
+	StorageTemporal = "temporal"
+)
+
+func (r *Neo4jRepository) temporal() bool {
+	return r.Storage == StorageTemporal
+}
+
+// versionParameters returns the parameters selecting a version: $version, and
+// in temporal storage its $seq the validity of records is compared with
+func (r *Neo4jRepository) versionParameters(version string) (map[string]interface{}, error) {
+	parameters := map[string]interface{}{"version": version}
+	if r.temporal() {
+		seq, err := versioning.Sequence(version)
+		if err != nil {
+			return nil, err
+		}
+		parameters["seq"] = seq
+	}
+	return parameters, nil
+}
+
+// at returns the predicate matching the nodes and edges bound to variables in
+// the version of the query, see versionParameters. The first variable must be
+// a node: versions of the versioned storage are disjoint subgraphs, so
+// matching it on its version is enough. Temporal storage matches every
+// variable valid in the version, nodes and edges written without validity
+// like the categories of has_pd annotations are valid as long as what they
+// hang off is, and copies written before switching to temporal storage are
+// still matched on their version.
+func (r *Neo4jRepository) at(variables ...string) string {
+	if !r.temporal() {
+		return variables[0] + ".version = $version"
+	}
+	var valid []string
+	for _, v := range variables {
+		valid = append(valid, fmt.Sprintf("(CASE WHEN %[1]s.version IS NULL THEN coalesce(%[1]s.validFromSeq, $seq) <= $seq AND coalesce(%[1]s.validToSeq, $seq + 1) > $seq ELSE %[1]s.version = $version END)", v))
+	}
+	return strings.Join(valid, " AND ")
+}
+
+// seenIn returns the predicate matching the nodes bound to variable that were
+// written in the version of the query
+func (r *Neo4jRepository) seenIn(variable string) string {
+	if !r.temporal() {
+		return variable + ".version = $version"
+	}
+	return variable + ".lastSeen = $version"
+}
+
+// current matches the record of a node or edge that is valid now
+func current(variable string) string {
+	return fmt.Sprintf("%[1]s.validFrom IS NOT NULL AND %[1]s.validTo IS NULL", variable)
+}
+
+// temporalNodeQuery keeps the current record of a node if its state didn't
+// change, or closes it and writes a new record valid from this version.
+// Records written earlier in the same version are updated in place.
+func temporalNodeQuery(labels []string, then string) string {
+	return fmt.Sprintf(`
+    UNWIND $rows AS row
+    OPTIONAL MATCH (old:%[1]s {id: row.id, provider: row.provider})
+    WHERE %[2]s
+    WITH row, old, old IS NOT NULL AND (old.stateHash = row.stateHash OR old.validFrom = $version) AS unchanged
+    FOREACH (o IN CASE WHEN old IS NOT NULL AND NOT unchanged THEN [old] ELSE [] END |
+        SET o.validTo = $version, o.validToSeq = $seq
+    )
+    MERGE (n:%[1]s {id: row.id, provider: row.provider, validFrom: CASE WHEN unchanged THEN old.validFrom ELSE $version END})
+    ON CREATE SET n.validFromSeq = $seq
+    SET n += row.properties, n.stateHash = row.stateHash, n.lastSeen = $version, n.uuid = coalesce(n.uuid, apoc.create.uuid())
+    %[3]s
+    `, strings.Join(labels, ":"), current("old"), then)
+}
+
+// stateHash identifies the state of a node, its properties and the extra
+// fields of its row
+func stateHash(row map[string]interface{}) string {
+	state := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		if k != "id" && k != "provider" {
+			state[k] = v
+		}
+	}
+	// Map keys are marshalled in order, equal states give equal hashes
+	raw, err := json.Marshal(state)
+	if err != nil {
+		raw = []byte(fmt.Sprintf("%v", state))
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// temporalEdge keeps the current edge between the current records of source
+// and target if its properties, bound to props, didn't change, or closes it
+// and writes one valid from this version. Edges written earlier in the same
+// version are updated in place.
+func temporalEdge(from string, edge string, to string) string {
+	return fmt.Sprintf(`OPTIONAL MATCH (%[1]s)-[old:%[2]s]->(%[3]s)
+    WHERE %[4]s
+    WITH %[1]s, %[3]s, row, props, old, old IS NOT NULL AND (old.validFrom = $version OR
+        all(k IN keys(props) WHERE coalesce(old[k] = props[k], false) OR (old[k] IS NULL AND props[k] IS NULL))) AS unchanged
+    FOREACH (o IN CASE WHEN old IS NOT NULL AND NOT unchanged THEN [old] ELSE [] END |
+        SET o.validTo = $version, o.validToSeq = $seq
+    )
+    MERGE (%[1]s)-[r:%[2]s {validFrom: CASE WHEN unchanged THEN old.validFrom ELSE $version END}]->(%[3]s)
+    ON CREATE SET r.validFromSeq = $seq
+    SET r += props, r.lastSeen = $version`, from, edge, to, current("old"))
+}
+
+// temporalIndexes returns the indexes on the validity of the records of
+// labels and edge types, which finishing a version closes or discards
+// records by
+func temporalIndexes(labels []string, edges []string) []string {
+	var indexes []string
+	for _, label := range labels {
+		indexes = append(indexes,
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (x:%s) ON (x.validFrom)", label),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (x:%s) ON (x.validTo)", label),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (x:%s) ON (x.lastSeen)", label),
+		)
+	}
+	for _, edge := range edges {
+		indexes = append(indexes,
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR ()-[x:%s]-() ON (x.validFrom)", edge),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR ()-[x:%s]-() ON (x.validTo)", edge),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR ()-[x:%s]-() ON (x.lastSeen)", edge),
+		)
+	}
+	return indexes
+}
+
+// identifier matches the labels and edge types temporal storage splices into
+// its queries
+var identifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// prepareTemporalLabels rejects labels that aren't plain identifiers and
+// indexes the labels of types outside the registry the first time they are
+// written, the registered ones are indexed on startup
+func (r *Neo4jRepository) prepareTemporalLabels(session neo4j.Session, labels []string) error {
+	for _, label := range labels {
+		if !identifier.MatchString(label) {
+			return fmt.Errorf("invalid label %q", label)
+		}
+	}
+	known := make(map[string]bool)
+	for _, label := range KnownLabels() {
+		known[label] = true
+	}
+	for _, label := range labels {
+		if known[label] {
+			continue
+		}
+		if _, indexed := r.indexed.Load(label); indexed {
+			continue
+		}
+		queries := append([]string{fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.id, n.provider)", label)}, temporalIndexes([]string{label}, nil)...)
+		for _, query := range queries {
+			if err := run(session, query, nil); err != nil {
+				return fmt.Errorf("error creating index with query: %s, %v", query, err)
+			}
+		}
+		r.indexed.Store(label, true)
+	}
+	return nil
+}
+
+// writtenTypes returns the labels and edge types of the records closeVersion
+// considers: the registered ones and those found in the database, which
+// include the types of mappings outside the registry. Types that aren't plain
+// identifiers were never written by temporal storage.
+func writtenTypes(tx neo4j.Transaction) (labels []string, edges []string, err error) {
+	found := func(query string, key string, known []string) ([]string, error) {
+		seen := make(map[string]bool)
+		for _, name := range known {
+			seen[name] = true
+		}
+		result, err := tx.Run(query, nil)
+		if err != nil {
+			return nil, err
+		}
+		for result.Next() {
+			if name, ok := result.Record().Get(key); ok {
+				if s, ok := name.(string); ok && !seen[s] && identifier.MatchString(s) {
+					seen[s] = true
+					known = append(known, s)
+				}
+			}
+		}
+		return known, result.Err()
+	}
+	if labels, err = found("CALL db.labels()", "label", KnownLabels()); err != nil {
+		return nil, nil, fmt.Errorf("error getting labels: %v", err)
+	}
+	if edges, err = found("CALL db.relationshipTypes()", "relationshipType", knownEdgeTypes()); err != nil {
+		return nil, nil, fmt.Errorf("error getting relationship types: %v", err)
+	}
+	return labels, edges, nil
+}
+
+// closeVersion ends the validity of the current nodes and edges that weren't
+// written in a version, they were removed from the infrastructure.
+func (r *Neo4jRepository) closeVersion(tx neo4j.Transaction, version string) error {
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		return err
+	}
+	labels, edges, err := writtenTypes(tx)
+	if err != nil {
+		return err
+	}
+	var queries []string
+	for _, edge := range edges {
+		queries = append(queries, fmt.Sprintf(`
+		MATCH ()-[x:%s]->()
+		WHERE %s AND x.lastSeen <> $version
+		SET x.validTo = $version, x.validToSeq = $seq
+		`, edge, current("x")))
+	}
+	for _, label := range labels {
+		queries = append(queries, fmt.Sprintf(`
+		MATCH (x:%s)
+		WHERE %s AND x.lastSeen <> $version
+		SET x.validTo = $version, x.validToSeq = $seq
+		`, label, current("x")))
+	}
+	for _, query := range queries {
+		if err := runTx(tx, query, parameters); err != nil {
+			return fmt.Errorf("error closing records missing from version %s with query: %s, %v", version, query, err)
+		}
+	}
+	return nil
+}
+
+// discardVersion removes what a failed version wrote: its records are deleted
+// and the records it closed are current again, as if it never ran. Records it
+// kept unchanged only keep it as lastSeen.
+func (r *Neo4jRepository) discardVersion(tx neo4j.Transaction, version string) error {
+	labels, edges, err := writtenTypes(tx)
+	if err != nil {
+		return err
+	}
+	var queries []string
+	for _, edge := range edges {
+		queries = append(queries,
+			fmt.Sprintf("MATCH ()-[x:%s]->() WHERE x.validFrom = $version DELETE x", edge),
+			fmt.Sprintf("MATCH ()-[x:%s]->() WHERE x.validTo = $version REMOVE x.validTo, x.validToSeq", edge),
+		)
+	}
+	for _, label := range labels {
+		queries = append(queries,
+			fmt.Sprintf("MATCH (x:%s) WHERE x.validFrom = $version DETACH DELETE x", label),
+			fmt.Sprintf("MATCH (x:%s) WHERE x.validTo = $version REMOVE x.validTo, x.validToSeq", label),
+		)
+	}
+	for _, query := range queries {
+		if err := runTx(tx, query, map[string]interface{}{"version": version}); err != nil {
+			return fmt.Errorf("error discarding records of failed version %s with query: %s, %v", version, query, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+//go:build integration
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	_ "github.com/regulatory-transparency-monitor/graph-builder/internal/testflags"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
+)
+
+// The integration tests run the queries of both storage models against a
+// Neo4j with APOC. They empty the database, use a scratch instance:
+//
+//	NEO4J_TEST_URI=bolt://localhost:7687 NEO4J_PASS=psw go test -tags integration ./internal/repository -run Integration
+
+func integrationRepository(t *testing.T, storage string) *Neo4jRepository {
+	uri := os.Getenv("NEO4J_TEST_URI")
+	if uri == "" {
+		t.Skip("NEO4J_TEST_URI isn't set")
+	}
+	user := os.Getenv("NEO4J_USER")
+	if user == "" {
+		user = "neo4j"
+	}
+	driver, err := neo4j.NewDriver(uri, neo4j.BasicAuth(user, os.Getenv("NEO4J_PASS"), ""), func(c *neo4j.Config) {
+		c.Encrypted = false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close() })
+
+	session, err := driver.Session(neo4j.AccessModeWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if err := run(session, "MATCH (n) DETACH DELETE n", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Neo4jRepository{Connection: driver, Storage: storage}
+	if err := r.SetupUUIDForKnownLabels(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// integrationScan is the inventory of a version: a pod on a cluster node,
+// which runs on an instance of another provider
+type integrationScan struct {
+	version   string
+	podStatus string // no pod if empty
+	rule      string
+	failed    bool
+}
+
+func (s integrationScan) write(t *testing.T, r *Neo4jRepository) {
+	node := dataparser.InfrastructureComponent{ID: "node-1", Name: "worker-1", Type: "ClusterNode", Provider: "k8s"}
+	instance := dataparser.InfrastructureComponent{ID: "i-1", Name: "worker-1", Type: "Instance", Provider: "openstack"}
+	components := []dataparser.InfrastructureComponent{node, instance}
+	if s.podStatus != "" {
+		components = append(components, dataparser.InfrastructureComponent{
+			ID: "pod-1", Name: "web", Type: "Pod", Provider: "k8s",
+			Metadata:      map[string]interface{}{"Status": s.podStatus},
+			Relationships: []dataparser.Relationship{{Type: "RUNS_ON", Target: "worker-1"}},
+		})
+	}
+	links := []dataparser.IdentityLink{{
+		Type: "PROVISIONED_BY", SourceID: "node-1", SourceType: "ClusterNode", SourceProvider: "k8s",
+		TargetID: "i-1", TargetProvider: "openstack", Rule: s.rule, Confidence: 0.5,
+	}}
+
+	if err := r.CreateMetadataNode(s.version, s.version); err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := r.CreateNodes(s.version, components); len(errs) > 0 {
+		t.Fatalf("%s nodes: %v", s.version, errs)
+	}
+	if _, _, errs := r.CreateRelationships(s.version, components); len(errs) > 0 {
+		t.Fatalf("%s relationships: %v", s.version, errs)
+	}
+	if _, errs := r.CreateIdentityRels(s.version, links); len(errs) > 0 {
+		t.Fatalf("%s identity links: %v", s.version, errs)
+	}
+	if err := r.FinishVersion(s.version, !s.failed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readVersion answers the questions of the tests for a version, using the
+// predicates the read queries use
+func readVersion(t *testing.T, r *Neo4jRepository, version string) []string {
+	parameters, err := r.versionParameters(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	queries := []string{
+		fmt.Sprintf("MATCH (p:Pod) WHERE %s RETURN 'pod ' + p.name + ' ' + p.status AS answer", r.at("p")),
+		fmt.Sprintf("MATCH (p:Pod)-[x:RUNS_ON]->(n:ClusterNode) WHERE %s RETURN 'runs on ' + n.name AS answer", r.at("p", "x", "n")),
+		fmt.Sprintf("MATCH (n:ClusterNode)-[x:PROVISIONED_BY]->(i:Instance) WHERE %s RETURN 'provisioned by ' + i.id + ' ' + x.rule AS answer", r.at("n", "x", "i")),
+	}
+	var answers []string
+	for _, query := range queries {
+		result, err := session.Run(query, parameters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for result.Next() {
+			answer, _ := result.Record().Get("answer")
+			answers = append(answers, answer.(string))
+		}
+		if err := result.Err(); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+	}
+	sort.Strings(answers)
+	return answers
+}
+
+func countRecords(t *testing.T, r *Neo4jRepository, query string) int64 {
+	session, err := r.Connection.Session(neo4j.AccessModeRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	result, err := session.Run(query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Next() {
+		t.Fatalf("%s returned nothing: %v", query, result.Err())
+	}
+	count, _ := result.Record().Get("count")
+	return count.(int64)
+}
+
+var integrationScans = []integrationScan{
+	{version: "0.0.1", podStatus: "Pending", rule: "hostname"},
+	{version: "0.0.2", podStatus: "Running", rule: "hostname"},
+	{version: "0.0.3", podStatus: "Running", rule: "providerID"},
+	{version: "0.0.4", rule: "providerID"},
+}
+
+var integrationAnswers = map[string][]string{
+	"0.0.1": {"pod web Pending", "provisioned by i-1 hostname", "runs on worker-1"},
+	"0.0.2": {"pod web Running", "provisioned by i-1 hostname", "runs on worker-1"},
+	"0.0.3": {"pod web Running", "provisioned by i-1 providerID", "runs on worker-1"},
+	"0.0.4": {"provisioned by i-1 providerID"},
+}
+
+// Every version answers the same in both storage models, also after later
+// versions changed or removed what it holds
+func TestIntegrationPerVersionSemantics(t *testing.T) {
+	for _, storage := range []string{StorageVersioned, StorageTemporal} {
+		t.Run(storage, func(t *testing.T) {
+			r := integrationRepository(t, storage)
+			for i, scan := range integrationScans {
+				scan.write(t, r)
+				for _, earlier := range integrationScans[:i+1] {
+					if got, want := readVersion(t, r, earlier.version), integrationAnswers[earlier.version]; !reflect.DeepEqual(got, want) {
+						t.Errorf("after %s, version %s answers %q, want %q", scan.version, earlier.version, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// A failed version leaves the versions before and after it answering as if it
+// never ran, and no record refers to it
+func TestIntegrationFailedVersion(t *testing.T) {
+	for _, storage := range []string{StorageVersioned, StorageTemporal} {
+		t.Run(storage, func(t *testing.T) {
+			r := integrationRepository(t, storage)
+			scans := []integrationScan{
+				integrationScans[0],
+				{version: "0.0.2", podStatus: "Failed", rule: "providerID", failed: true},
+				{version: "0.0.3", podStatus: "Pending", rule: "hostname"},
+			}
+			want := map[string][]string{
+				"0.0.1": integrationAnswers["0.0.1"],
+				"0.0.3": integrationAnswers["0.0.1"],
+			}
+			for _, scan := range scans {
+				scan.write(t, r)
+				for version, answers := range want {
+					if version > scan.version {
+						continue
+					}
+					if got := readVersion(t, r, version); !reflect.DeepEqual(got, answers) {
+						t.Errorf("after %s, version %s answers %q, want %q", scan.version, version, got, answers)
+					}
+				}
+			}
+			if storage != StorageTemporal {
+				return
+			}
+			for _, query := range []string{
+				"MATCH (x) WHERE x.validFrom = '0.0.2' OR x.validTo = '0.0.2' RETURN count(x) AS count",
+				"MATCH ()-[x]->() WHERE x.validFrom = '0.0.2' OR x.validTo = '0.0.2' RETURN count(x) AS count",
+			} {
+				if got := countRecords(t, r, query); got != 0 {
+					t.Errorf("%s = %d, want 0", query, got)
+				}
+			}
+			// Nothing changed between the complete versions
+			if got := countRecords(t, r, "MATCH (x:Pod) RETURN count(x) AS count"); got != 1 {
+				t.Errorf("pod records = %d, want 1", got)
+			}
+		})
+	}
+}
+
+// Temporal storage writes new records only for changes: the pod changed
+// once, the identity edge once with its rule, the node and instance never
+func TestIntegrationTemporalRecords(t *testing.T) {
+	r := integrationRepository(t, StorageTemporal)
+	for _, scan := range integrationScans {
+		scan.write(t, r)
+	}
+	counts := map[string]int64{
+		"MATCH (x:Pod) RETURN count(x) AS count":                                           2,
+		"MATCH (x:ClusterNode) RETURN count(x) AS count":                                   1,
+		"MATCH (x:Instance) RETURN count(x) AS count":                                      1,
+		"MATCH ()-[x:PROVISIONED_BY]->() RETURN count(x) AS count":                         2,
+		"MATCH ()-[x:PROVISIONED_BY]->() WHERE x.validTo IS NULL RETURN count(x) AS count": 1,
+		"MATCH (x:Pod) WHERE x.validTo IS NULL RETURN count(x) AS count":                   0,
+	}
+	for query, want := range counts {
+		if got := countRecords(t, r, query); got != want {
+			t.Errorf("%s = %d, want %d", query, got, want)
+		}
+	}
+}
+
+// Components of types outside the registry, like those of mappings, are
+// closed once they disappear
+func TestIntegrationTemporalUnregisteredType(t *testing.T) {
+	r := integrationRepository(t, StorageTemporal)
+	backup := dataparser.InfrastructureComponent{ID: "b-1", Name: "nightly", Type: "BackupJob", Provider: "mapped"}
+	for _, scan := range []struct {
+		version    string
+		components []dataparser.InfrastructureComponent
+	}{
+		{version: "0.0.1", components: []dataparser.InfrastructureComponent{backup}},
+		{version: "0.0.2"},
+	} {
+		if err := r.CreateMetadataNode(scan.version, scan.version); err != nil {
+			t.Fatal(err)
+		}
+		if _, errs := r.CreateNodes(scan.version, scan.components); len(errs) > 0 {
+			t.Fatalf("%s nodes: %v", scan.version, errs)
+		}
+		if err := r.FinishVersion(scan.version, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	query := "MATCH (x:BackupJob) WHERE x.validTo = '0.0.2' RETURN count(x) AS count"
+	if got := countRecords(t, r, query); got != 1 {
+		t.Errorf("%s = %d, want 1", query, got)
+	}
+
+	injected := dataparser.InfrastructureComponent{ID: "x", Type: "Job`) DETACH DELETE n //", Provider: "mapped"}
+	if _, errs := r.CreateNodes("0.0.3", []dataparser.InfrastructureComponent{injected}); len(errs) != 1 {
+		t.Errorf("invalid label errors = %v, want one", errs)
+	}
+}
+
+// Relationships count the edges written and return those whose target is
+// missing from the version
+func TestIntegrationUnmatchedRelationships(t *testing.T) {
+	for _, storage := range []string{StorageVersioned, StorageTemporal} {
+		t.Run(storage, func(t *testing.T) {
+			r := integrationRepository(t, storage)
+			components := []dataparser.InfrastructureComponent{
+				{ID: "node-1", Name: "worker-1", Type: "ClusterNode", Provider: "k8s"},
+				{ID: "pod-1", Name: "web", Type: "Pod", Provider: "k8s", Relationships: []dataparser.Relationship{{Type: "RUNS_ON", Target: "worker-1"}}},
+				{ID: "pod-2", Name: "api", Type: "Pod", Provider: "k8s", Relationships: []dataparser.Relationship{{Type: "RUNS_ON", Target: "worker-2"}}},
+			}
+			if err := r.CreateMetadataNode("0.0.1", "0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+			if _, errs := r.CreateNodes("0.0.1", components); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			written, unmatched, errs := r.CreateRelationships("0.0.1", components)
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if written != 1 {
+				t.Errorf("written = %d, want 1", written)
+			}
+			if want := []string{"Pod pod-2 RUNS_ON worker-2: target not found"}; !reflect.DeepEqual(unmatched, want) {
+				t.Errorf("unmatched = %q, want %q", unmatched, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/internal/versioning"
+)
+
+// The predicates are run against Neo4j by the integration tests, see
+// temporal_integration_test.go
+func TestAt(t *testing.T) {
+	versioned := &Neo4jRepository{}
+	if got, want := versioned.at("p", "r", "n"), "p.version = $version"; got != want {
+		t.Errorf("versioned at = %q, want %q", got, want)
+	}
+
+	temporal := &Neo4jRepository{Storage: StorageTemporal}
+	want := "(CASE WHEN p.version IS NULL THEN coalesce(p.validFromSeq, $seq) <= $seq AND coalesce(p.validToSeq, $seq + 1) > $seq ELSE p.version = $version END)" +
+		" AND (CASE WHEN r.version IS NULL THEN coalesce(r.validFromSeq, $seq) <= $seq AND coalesce(r.validToSeq, $seq + 1) > $seq ELSE r.version = $version END)"
+	if got := temporal.at("p", "r"); got != want {
+		t.Errorf("temporal at = %q, want %q", got, want)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	ordered := []string{"0.0.0", "0.0.9", "0.0.10", "0.1.0", "0.10.0", "1.0.0", "1.0.999999"}
+	var last int64 = -1
+	for _, version := range ordered {
+		seq, err := versioning.Sequence(version)
+		if err != nil {
+			t.Fatalf("Sequence(%s): %v", version, err)
+		}
+		if seq <= last {
+			t.Errorf("Sequence(%s) = %d, not after the previous version %d", version, seq, last)
+		}
+		last = seq
+	}
+	for _, version := range []string{"", "1", "1.2", "1.2.3.4", "1.a.3", "1.-2.3", "1.1000000.0"} {
+		if _, err := versioning.Sequence(version); err == nil {
+			t.Errorf("Sequence(%q) accepted an invalid version", version)
+		}
+	}
+}
+
+func TestStateHash(t *testing.T) {
+	row := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":       "pod-1",
+			"provider": "k8s",
+			"properties": map[string]interface{}{
+				"name":      name,
+				"type":      "Pod",
+				"namespace": "shop",
+				"storage":   []string{"ns/data"},
+			},
+			"pd": map[string]interface{}{"dataCategories": []interface{}{map[string]interface{}{"name": "health"}}},
+		}
+	}
+
+	hash := stateHash(row("web"))
+	for i := 0; i < 20; i++ {
+		// Map iteration order differs between calls
+		if got := stateHash(row("web")); got != hash {
+			t.Fatalf("hash of the same state changed: %s, %s", hash, got)
+		}
+	}
+
+	other := row("web")
+	other["id"] = "pod-2"
+	other["provider"] = "k8s-2"
+	if got := stateHash(other); got != hash {
+		t.Errorf("hash depends on id and provider")
+	}
+
+	if got := stateHash(row("api")); got == hash {
+		t.Errorf("hash doesn't change with a property")
+	}
+	changed := row("web")
+	changed["pd"] = map[string]interface{}{"dataCategories": []interface{}{map[string]interface{}{"name": "financial"}}}
+	if got := stateHash(changed); got == hash {
+		t.Errorf("hash doesn't change with an extra field of the row")
+	}
+}
+
+func TestTemporalNodeQuery(t *testing.T) {
+	query := temporalNodeQuery([]string{"Deployment", "Workload"}, pdCategories)
+	for _, part := range []string{
+		"OPTIONAL MATCH (old:Deployment:Workload {id: row.id, provider: row.provider})",
+		"WHERE " + current("old"),
+		"MERGE (n:Deployment:Workload {id: row.id, provider: row.provider, validFrom: CASE WHEN unchanged THEN old.validFrom ELSE $version END})",
+		pdCategories,
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query doesn't contain %q:\n%s", part, query)
+		}
+	}
+}
+
+// closeVersion and temporalIndexes cover the edge types written, not the
+// relationship types of the transformers
+func TestKnownEdgeTypes(t *testing.T) {
+	edges := make(map[string]bool)
+	for _, edge := range knownEdgeTypes() {
+		edges[edge] = true
+	}
+	for _, want := range []string{"HAS_PD", "OWNS", "PROVISIONED_BY"} {
+		if !edges[want] {
+			t.Errorf("edge type %s isn't known, got %v", want, edges)
+		}
+	}
+	if edges["OWNED_BY"] {
+		t.Errorf("reversed relationship type OWNED_BY is known as edge type")
+	}
+}
+
+// Labels of types outside the registry are spliced into the temporal queries
+// only if they are plain identifiers, checked before anything is written
+func TestPrepareTemporalLabels(t *testing.T) {
+	r := &Neo4jRepository{Storage: StorageTemporal}
+	for _, label := range []string{"", "1Job", "Job Run", "Job`) DETACH DELETE n //", "Job:Admin", "Jöb"} {
+		if err := r.prepareTemporalLabels(nil, []string{"Workload", label}); err == nil {
+			t.Errorf("label %q was accepted", label)
+		}
+	}
+	// Registered labels are indexed on startup, nothing is run for them
+	if err := r.prepareTemporalLabels(nil, []string{"Workload", "Deployment"}); err != nil {
+		t.Errorf("registered labels: %v", err)
+	}
+}
+
+func TestRelationshipQueryProperties(t *testing.T) {
+	rt, ok := relationshipType("ClusterNode", "PROVISIONED_BY")
+	if !ok {
+		t.Fatal("PROVISIONED_BY isn't declared")
+	}
+	versioned := rt.query(false)
+	temporal := rt.query(true)
+	for _, query := range []string{versioned, temporal} {
+		if !strings.Contains(query, "WITH s, t, row, "+identityRule+" AS props") {
+			t.Errorf("edge properties aren't bound to props:\n%s", query)
+		}
+		// The rows written are returned to find those without a target
+		if !strings.Contains(query, "RETURN row.provider AS provider, row.sourceID AS sourceID, row.target AS target") {
+			t.Errorf("written rows aren't returned:\n%s", query)
+		}
+	}
+	if !strings.Contains(versioned, "SET r += props") {
+		t.Errorf("versioned query doesn't set the edge properties:\n%s", versioned)
+	}
+	// A changed rule closes the edge instead of overwriting it
+	for _, part := range []string{"old[k] = props[k]", "SET o.validTo = $version", "SET r += props, r.lastSeen = $version"} {
+		if !strings.Contains(temporal, part) {
+			t.Errorf("temporal query doesn't contain %q:\n%s", part, temporal)
+		}
+	}
+}
+
+func TestUnmatchedRows(t *testing.T) {
+	rt, _ := relationshipType("Pod", "RUNS_ON")
+	batch := []map[string]interface{}{
+		{"provider": "k8s", "sourceID": "pod-1", "target": "worker-1"},
+		{"provider": "k8s", "sourceID": "pod-2", "target": "worker-2"},
+		{"provider": "k8s-2", "sourceID": "pod-1", "target": "worker-1"},
+	}
+	matched := map[string]bool{rowKey("k8s", "pod-1", "worker-1"): true}
+	want := []string{
+		"Pod pod-2 RUNS_ON worker-2: target not found",
+		"Pod pod-1 RUNS_ON worker-1: target not found",
+	}
+	if got := unmatchedRows(rt, batch, matched); !reflect.DeepEqual(got, want) {
+		t.Errorf("unmatched = %q, want %q", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
@@ -13,7 +14,7 @@ func ParseCypherQueryResult(record neo4j.Record, alias string, target interface{
 	for i := 0; i < elem.Type().NumField(); i++ {
 		structField := elem.Type().Field(i)
 
-		tag := structField.Tag.Get("json") // Reading from the "json" tag
+		tag := strings.Split(structField.Tag.Get("json"), ",")[0] // Reading from the "json" tag, without options like omitempty
 		fieldType := structField.Type
 		fieldName := structField.Name
 
@@ -34,22 +35,32 @@ func ParseCypherQueryResult(record neo4j.Record, alias string, target interface{
 						field.SetBool(boolVal)
 					}
 				case reflect.Ptr:
+					// Neo4j returns plain values, optional fields point to a copy
 					switch fieldType.Elem().Kind() {
 					case reflect.String:
-						if strVal, ok := val.(*string); ok {
-							field.Set(reflect.ValueOf(strVal))
+						if strVal, ok := val.(string); ok {
+							field.Set(reflect.ValueOf(StringPtr(strVal)))
 						}
-					case reflect.Int, reflect.Int64:
-						if intVal, ok := val.(*int64); ok {
-							field.Set(reflect.ValueOf(intVal))
+					case reflect.Int:
+						if intVal, ok := val.(int64); ok {
+							field.Set(reflect.ValueOf(IntPtr(int(intVal))))
 						}
 					case reflect.Bool:
-						if boolVal, ok := val.(*bool); ok {
-							field.Set(reflect.ValueOf(boolVal))
+						if boolVal, ok := val.(bool); ok {
+							field.Set(reflect.ValueOf(BoolPtr(boolVal)))
 						}
 					}
 				case reflect.Slice:
-					// Handle slice types
+					// Neo4j returns lists as []interface{}, only string lists are mapped
+					if list, ok := val.([]interface{}); ok && fieldType.Elem().Kind() == reflect.String {
+						strs := make([]string, 0, len(list))
+						for _, item := range list {
+							if strVal, ok := item.(string); ok {
+								strs = append(strs, strVal)
+							}
+						}
+						field.Set(reflect.ValueOf(strs))
+					}
 				default:
 					return fmt.Errorf("unsupported type: %s", fieldType.Kind().String())
 				}
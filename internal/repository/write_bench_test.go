@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	_ "github.com/regulatory-transparency-monitor/graph-builder/internal/testflags"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/dataparser"
+)
+
+// benchPods is the number of pods of the benchmarked inventory, each with a
+// claim and three relationships
+const benchPods = 1000
+
+// BenchmarkWrite compares writing a cluster inventory like before batching,
+// one session per component and one round trip per node and relationship,
+// with the batched UNWIND writes. It needs a running Neo4j with APOC:
+//
+//	NEO4J_BENCH_URI=bolt://localhost:7687 NEO4J_PASS=psw go test ./internal/repository -run - -bench Write
+func BenchmarkWrite(b *testing.B) {
+	uri := os.Getenv("NEO4J_BENCH_URI")
+	if uri == "" {
+		b.Skip("NEO4J_BENCH_URI isn't set")
+	}
+	user := os.Getenv("NEO4J_USER")
+	if user == "" {
+		user = "neo4j"
+	}
+	driver, err := neo4j.NewDriver(uri, neo4j.BasicAuth(user, os.Getenv("NEO4J_PASS"), ""), func(c *neo4j.Config) {
+		c.Encrypted = false
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer driver.Close()
+
+	components := benchInventory(benchPods)
+	writes := len(components)
+	for _, c := range components {
+		writes += len(c.Relationships)
+	}
+
+	write := func(b *testing.B, name string, store func(r *Neo4jRepository, version string) error) {
+		b.Run(name, func(b *testing.B) {
+			r := &Neo4jRepository{Connection: driver}
+			var elapsed time.Duration
+			for i := 0; i < b.N; i++ {
+				version := fmt.Sprintf("bench-%s-%d", name, time.Now().UnixNano())
+				start := time.Now()
+				err := store(r, version)
+				elapsed += time.Since(start)
+
+				b.StopTimer()
+				if cleanupErr := benchCleanup(driver, version); cleanupErr != nil {
+					b.Error(cleanupErr)
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(writes*b.N)/elapsed.Seconds(), "writes/s")
+		})
+	}
+
+	write(b, "unbatched", func(r *Neo4jRepository, version string) error {
+		return unbatchedWrite(r, version, components)
+	})
+	for _, size := range []int{100, 500, 1000} {
+		size := size
+		write(b, fmt.Sprintf("batch-%d", size), func(r *Neo4jRepository, version string) error {
+			r.BatchSize = size
+			if _, errs := r.CreateNodes(version, components); len(errs) > 0 {
+				return fmt.Errorf("%d node errors, first: %s", len(errs), errs[0])
+			}
+			if _, _, errs := r.CreateRelationships(version, components); len(errs) > 0 {
+				return fmt.Errorf("%d relationship errors, first: %s", len(errs), errs[0])
+			}
+			return nil
+		})
+	}
+}
+
+// unbatchedWrite writes components the way the coordinator did before
+// batching: a session and a query per component, and a session per component
+// with a query per relationship
+func unbatchedWrite(r *Neo4jRepository, version string, components []dataparser.InfrastructureComponent) error {
+	for _, component := range components {
+		row, err := nodeRow(component)
+		if err != nil {
+			return err
+		}
+		nodeType := NodeTypes[component.Type]
+		query := fmt.Sprintf(`
+    WITH $row AS row
+    CALL apoc.merge.node($labels, {id: row.id, provider: row.provider, version: $version}, row.properties, row.properties)
+    YIELD node AS n
+    SET n.uuid = coalesce(n.uuid, apoc.create.uuid())
+    %s
+    RETURN n.uuid AS uuid
+    `, nodeType.Then)
+		parameters := map[string]interface{}{"version": version, "labels": nodeType.labels(component.Type), "row": row}
+		if err := unbatchedRun(r, query, parameters); err != nil {
+			return fmt.Errorf("%s %s: %v", component.Type, component.ID, err)
+		}
+	}
+
+	for _, component := range components {
+		session, err := r.Connection.Session(neo4j.AccessModeWrite)
+		if err != nil {
+			return err
+		}
+		for _, rel := range component.Relationships {
+			rt, ok := relationshipType(component.Type, rel.Type)
+			if !ok {
+				session.Close()
+				return fmt.Errorf("unsupported relationship %s of %s", rel.Type, component.Type)
+			}
+			row := map[string]interface{}{"sourceID": component.ID, "provider": component.Provider, "target": rel.Target, "targetProvider": nil}
+			if err := run(session, rt.query(false), map[string]interface{}{"version": version, "rows": []interface{}{row}}); err != nil {
+				session.Close()
+				return fmt.Errorf("%s %s %s: %v", component.Type, component.ID, rel.Type, err)
+			}
+		}
+		session.Close()
+	}
+	return nil
+}
+
+func unbatchedRun(r *Neo4jRepository, query string, parameters map[string]interface{}) error {
+	session, err := r.Connection.Session(neo4j.AccessModeWrite)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return run(session, query, parameters)
+}
+
+// benchInventory returns a cluster of pods spread over namespaces and nodes,
+// with one claim per pod
+func benchInventory(pods int) []dataparser.InfrastructureComponent {
+	const provider = "bench"
+	var components []dataparser.InfrastructureComponent
+	for i := 0; i < 10; i++ {
+		components = append(components,
+			dataparser.InfrastructureComponent{ID: fmt.Sprintf("ns-%d", i), Name: fmt.Sprintf("ns-%d", i), Type: "Namespace", Provider: provider},
+			dataparser.InfrastructureComponent{ID: fmt.Sprintf("node-%d", i), Name: fmt.Sprintf("node-%d", i), Type: "ClusterNode", Provider: provider},
+		)
+	}
+	for i := 0; i < pods; i++ {
+		ns := fmt.Sprintf("ns-%d", i%10)
+		pvc := fmt.Sprintf("%s/data-%d", ns, i)
+		components = append(components,
+			dataparser.InfrastructureComponent{
+				ID: pvc, Name: fmt.Sprintf("data-%d", i), Type: "PersistentVolumeClaim", Provider: provider,
+				Metadata: map[string]interface{}{"Namespace": ns},
+			},
+			dataparser.InfrastructureComponent{
+				ID: fmt.Sprintf("pod-%d", i), Name: fmt.Sprintf("pod-%d", i), Type: "Pod", Provider: provider,
+				Metadata: map[string]interface{}{"Namespace": ns, "CreatedAt": time.Now().Format(time.RFC3339), "Volumes": []string{pvc}},
+				Relationships: []dataparser.Relationship{
+					{Type: "RUNS_ON", Target: fmt.Sprintf("node-%d", i%10)},
+					{Type: "IN_NAMESPACE", Target: ns},
+					{Type: "USES_PVC", Target: pvc},
+				},
+			},
+		)
+	}
+	return components
+}
+
+func benchCleanup(driver neo4j.Driver, version string) error {
+	session, err := driver.Session(neo4j.AccessModeWrite)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return run(session, "MATCH (n {version: $version}) DETACH DELETE n", map[string]interface{}{"version": version})
+}
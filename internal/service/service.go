@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/regulatory-transparency-monitor/graph-builder/graph/model"
 	"github.com/regulatory-transparency-monitor/graph-builder/internal/repository"
@@ -21,85 +20,47 @@ func NewService(r repository.Repository) *Service {
 	}
 }
 
-func (s *Service) CreateInfrastructureComponent(version string, component dataparser.InfrastructureComponent) (uuid string, err error) {
-	switch component.Type {
-	case "Project":
-		return s.repository.CreateProjectNode(version, component)
-	case "Instance":
-		return s.repository.CreateInstanceNode(version, component)
-	case "Volume":
-		return s.repository.CreateVolumeNode(version, component)
-	case "ClusterNode":
-		return s.repository.CreateClusterNode(version, component)
-	case "Pod":
-		return s.repository.CreatePodNode(version, component)
-	case "PhysicalHost":
-		return s.repository.CreatePhysicalHostNode(version, component)
-	case "PersistentVolume":
-		return s.repository.CreatePVNode(version, component)
-	case "PersistentVolumeClaim":
-		return s.repository.CreatePVCNode(version, component)
-	case "PDIndicator":
-		return s.repository.CreatePDNode(version, component)
-	case "Snapshot":
-		return s.repository.CreateSnapshotNode(version, component)
-	default:
-		return "", fmt.Errorf("unknown component type: %s", component.Type)
-	}
+// CreateInfrastructureComponents stores components of any type, see repository.NodeTypes
+func (s *Service) CreateInfrastructureComponents(version string, components []dataparser.InfrastructureComponent) (written int, errs []string) {
+	return s.repository.CreateNodes(version, components)
 }
 
-func (s *Service) CreateRelationships(v string, component dataparser.InfrastructureComponent) error {
-	switch component.Type {
-	case "Project":
-		return nil
-	case "Instance":
-		return s.repository.CreateInstanceRelationships(component.ID, v, component.Relationships)
-	case "ClusterNode":
-		return s.repository.CreateClusterNodeRel(component.ID, v, component.Relationships)
-	case "Pod":
-		return s.repository.CreatePodRel(component.ID, v, component.Relationships)
-	case "Volume":
-		return s.repository.CreateVolumeRel(component.ID, v, component.Relationships)
-	case "PersistentVolumeClaim":
-		return s.repository.CreatePVCRel(component.ID, v, component.Relationships)
-	case "PhysicalHost":
-		return nil
-	case "PDIndicator":
-		return nil
-	case "PersistentVolume":
-		return s.repository.CreatePVRel(component.ID, v, component.Relationships)
-	case "Snapshot":
-		return s.repository.CreateSnapshotRel(component.ID, v, component.Relationships)
-	default:
-		return fmt.Errorf("unknown component type: %s", component.Type)
-	}
+// CreateRelationships stores the relationships of components, see repository.RelationshipTypes
+func (s *Service) CreateRelationships(v string, components []dataparser.InfrastructureComponent) (written int, unmatched []string, errs []string) {
+	return s.repository.CreateRelationships(v, components)
 }
 
-func (s *Service) CreatePVRel(pvID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreatePVRel(pvID, version, relationships)
+// LinkProviders resolves which instances the cluster nodes of a version run on
+// and which TILT documents describe its pods and services, and stores the
+// links. Errors are collected so one bad link doesn't stop the others.
+func (s *Service) LinkProviders(version string, components []dataparser.InfrastructureComponent) (links int, errs []string) {
+	return s.repository.CreateIdentityRels(version, dataparser.ResolveIdentities(components))
 }
 
-func (s *Service) CreatePVCRel(pvcID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreatePVCRel(pvcID, version, relationships)
+func (s *Service) SetupUUIDForKnownLabels() error {
+	return s.repository.SetupUUIDForKnownLabels()
 }
-
-func (s *Service) CreateVolumeRel(volumeID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreateVolumeRel(volumeID, version, relationships)
+func (s *Service) GetLatestVersion() (string, error) {
+	return s.repository.GetLatestVersion()
 }
 
-func (s *Service) CreatePodRel(podID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreatePodRel(podID, version, relationships)
-}
-func (s *Service) CreateClusterNodeRel(nodeID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreateClusterNodeRel(nodeID, version, relationships)
+func (s *Service) GetLastVersion() (string, error) {
+	return s.repository.GetLastVersion()
 }
-func (s *Service) CreateInstanceRelationships(instanceID string, version string, relationships []dataparser.Relationship) error {
-	return s.repository.CreateInstanceRelationships(instanceID, version, relationships)
+
+func (s *Service) FinishVersion(version string, complete bool) error {
+	return s.repository.FinishVersion(version, complete)
 }
-func (s *Service) SetupUUIDForKnownLabels() error {
-	return s.repository.SetupUUIDForKnownLabels()
+
+func (s *Service) FailPendingVersions() (int, error) {
+	return s.repository.FailPendingVersions()
 }
-func (s *Service) GetLatestVersion() (string, error) {
+
+// resolveVersion returns the requested version, or the latest complete version if none is given
+func (s *Service) resolveVersion(version *string) (string, error) {
+	if version != nil && *version != "" {
+		return *version, nil
+	}
 	return s.repository.GetLatestVersion()
 }
 
@@ -111,11 +72,55 @@ func (s *Service) CreateMetadataNode(version string, timeString string) error {
 	return s.repository.CreateMetadataNode(version, timeString)
 }
 
-func (s *Service) LinkProjectToMetadata(version string, projectUUID string) error {
-	return s.repository.LinkProjectToMetadata(version, projectUUID)
+func (s *Service) UpdateMetadataScanResult(version string, scanResult dataparser.ScanResult) error {
+	return s.repository.UpdateMetadataScanResult(version, scanResult)
+}
+
+func (s *Service) LinkProjectsToMetadata(version string) error {
+	return s.repository.LinkProjectsToMetadata(version)
+}
+
+// GetMetadata returns the metadata of a version, including whether its scan was complete
+func (s *Service) GetMetadata(ctx context.Context, version *string) (*model.Metadata, error) {
+	v, err := s.resolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetMetadata(ctx, v)
 }
 
 // FindInstanceByUUID finds a Instance by its uuid
-func (s *Service) GetPdsWithCategory(ctx context.Context, version string, categoryName string) ([]*model.Pod, error) {
-	return s.repository.GetPdsWithCategory(ctx, version, categoryName)
+func (s *Service) GetPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error) {
+	v, err := s.resolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetPdsWithCategory(ctx, v, categoryName)
+}
+
+// GetExposedPdsWithCategory returns the pods processing a data category that are reachable from outside their cluster
+func (s *Service) GetExposedPdsWithCategory(ctx context.Context, version *string, categoryName string) ([]*model.Pod, error) {
+	v, err := s.resolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetExposedPdsWithCategory(ctx, v, categoryName)
+}
+
+// GetPublicInstancesWithPds returns the instances running pods with personal data that have a floating IP
+func (s *Service) GetPublicInstancesWithPds(ctx context.Context, version *string) ([]*model.Instance, error) {
+	v, err := s.resolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetPublicInstancesWithPds(ctx, v)
+}
+
+// GetFindings returns the findings of a version, e.g. broken has_pd annotations
+func (s *Service) GetFindings(ctx context.Context, version *string) ([]*model.Finding, error) {
+	v, err := s.resolveVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetFindings(ctx, v)
 }
@@ -0,0 +1,11 @@
+// Package testflags registers the testing flags before any other package of a
+// test binary is initialized. Import it for its side effect in tests of
+// packages that depend on the kubernetes provider plugin, which parses the
+// command line in an init function and exits on the -test.* flags otherwise.
+package testflags
+
+import "testing"
+
+func init() {
+	testing.Init()
+}
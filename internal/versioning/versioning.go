@@ -8,6 +8,19 @@ import (
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
 )
 
+// Status of a version on its Metadata node. A scan writes its version as
+// pending and publishes it as complete once everything is stored, versions
+// with errors or of interrupted scans are failed.
+const (
+	StatusPending  = "pending"
+	StatusComplete = "complete"
+	StatusFailed   = "failed"
+)
+
+// componentLimit bounds the components of a version, so versions fit into a
+// Sequence. Patch rolls over into minor and minor into major when reaching it.
+const componentLimit = 1000000
+
 type VersionManager struct {
 	CurrentVersion string
 }
@@ -40,6 +53,32 @@ func incrementVersion(version string) string {
 		return "0.0.0"
 	}
 	patch++
+	if patch >= componentLimit {
+		patch = 0
+		minor++
+	}
+	if minor >= componentLimit {
+		minor = 0
+		major++
+	}
 
 	return fmt.Sprintf("%d.%d.%d", major, minor, patch)
 }
+
+// Sequence orders versions numerically, 0.0.10 comes after 0.0.9. Temporal
+// storage compares versions by their sequence.
+func Sequence(version string) (int64, error) {
+	splitVersion := strings.Split(version, ".")
+	if len(splitVersion) != 3 {
+		return 0, fmt.Errorf("invalid version format: %s", version)
+	}
+	var sequence int64
+	for _, part := range splitVersion {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 || n >= componentLimit {
+			return 0, fmt.Errorf("invalid version component %q of %s", part, version)
+		}
+		sequence = sequence*componentLimit + n
+	}
+	return sequence, nil
+}
@@ -0,0 +1,38 @@
+package versioning
+
+import "testing"
+
+func TestIncrementVersion(t *testing.T) {
+	tests := map[string]string{
+		"0.0.1":           "0.0.2",
+		"0.0.9":           "0.0.10",
+		"1.2.999998":      "1.2.999999",
+		"1.2.999999":      "1.3.0",
+		"1.999999.999999": "2.0.0",
+		"invalid":         "0.0.0",
+		"1.x.3":           "0.0.0",
+	}
+	for version, want := range tests {
+		if got := incrementVersion(version); got != want {
+			t.Errorf("incrementVersion(%s) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+// Every incremented version must have a sequence after the previous one
+func TestIncrementVersionSequence(t *testing.T) {
+	for _, version := range []string{"0.0.1", "0.0.999999", "0.999999.999999", "3.4.999999"} {
+		next := incrementVersion(version)
+		seq, err := Sequence(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nextSeq, err := Sequence(next)
+		if err != nil {
+			t.Fatalf("incremented version %s has no sequence: %v", next, err)
+		}
+		if nextSeq <= seq {
+			t.Errorf("sequence of %s (%d) isn't after the one of %s (%d)", next, nextSeq, version, seq)
+		}
+	}
+}
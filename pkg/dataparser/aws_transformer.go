@@ -0,0 +1,190 @@
+package dataparser
+
+import (
+	"fmt"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+)
+
+// Transformer for AWS, resources are mapped onto the OpenStack node types:
+// the account becomes a Project, EC2 instances Instances placed on a
+// PhysicalHost per dedicated host or availability zone, and EBS volumes and
+// snapshots Volumes and Snapshots.
+func (a *AWSTransformer) Transform(key string, data []interface{}) ([]InfrastructureComponent, error) {
+	switch key {
+	case "aws_account":
+		return handleAWSAccount(data), nil
+	case "aws_instance":
+		return handleEC2Instance(data), nil
+	case "aws_volume":
+		return handleEBSVolume(data), nil
+	case "aws_snapshot":
+		return handleEBSSnapshot(data), nil
+	default:
+		return nil, fmt.Errorf("unknown key for AWS: %s", key)
+	}
+}
+
+func handleAWSAccount(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		account, ok := item.(plugin.AWSAccount)
+		if !ok {
+			fmt.Printf("expected type plugin.AWSAccount, but got: %T\n", item)
+			continue
+		}
+		components = append(components, InfrastructureComponent{
+			ID:               account.ID,
+			Name:             account.ID,
+			Type:             "Project",
+			AvailabilityZone: account.Region,
+			Metadata: map[string]interface{}{
+				"Description": account.ARN,
+				"Enabled":     true,
+			},
+		})
+	}
+	return components
+}
+
+func handleEC2Instance(data []interface{}) []InfrastructureComponent {
+	seenHosts := make(map[string]bool)
+	var components []InfrastructureComponent
+	for _, item := range data {
+		instance, ok := item.(plugin.EC2Instance)
+		if !ok {
+			fmt.Printf("expected type plugin.EC2Instance, but got: %T\n", item)
+			continue
+		}
+
+		// Instances on shared tenancy only expose their availability zone
+		hostID := instance.HostID
+		if hostID == "" {
+			hostID = instance.AvailabilityZone
+		}
+		if !seenHosts[hostID] {
+			components = append(components, InfrastructureComponent{
+				ID:               hostID,
+				Name:             hostID,
+				Type:             "PhysicalHost",
+				AvailabilityZone: instance.AvailabilityZone,
+			})
+			seenHosts[hostID] = true
+		}
+
+		relationships := []Relationship{
+			{Type: "BELONGS_TO", Target: instance.OwnerID},
+			{Type: "ASSIGNED_HOST", Target: hostID},
+		}
+		var volumeIDs []string
+		for _, device := range instance.BlockDevices {
+			if device.VolumeID == "" {
+				continue
+			}
+			volumeIDs = append(volumeIDs, device.VolumeID)
+			relationships = append(relationships, Relationship{Type: "ATTACHED_TO", Target: device.VolumeID})
+		}
+
+		var addresses []string
+		if instance.PrivateIPAddress != "" {
+			addresses = append(addresses, instance.PrivateIPAddress)
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:               instance.InstanceID,
+			Name:             nameOrID(instance.Tags, instance.InstanceID),
+			Type:             "Instance",
+			AvailabilityZone: instance.AvailabilityZone,
+			Metadata: map[string]interface{}{
+				"Status":          instance.State,
+				"TenantID":        instance.OwnerID,
+				"HostID":          hostID,
+				"Created":         instance.LaunchTime,
+				"VolumesAttached": volumeIDs,
+				"VpcID":           instance.VpcID,
+				"SubnetID":        instance.SubnetID,
+				"IPAddresses":     addresses,
+			},
+			Relationships: relationships,
+		})
+	}
+	return components
+}
+
+func handleEBSVolume(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		volume, ok := item.(plugin.EBSVolume)
+		if !ok {
+			fmt.Printf("expected type plugin.EBSVolume, but got: %T\n", item)
+			continue
+		}
+
+		metadata := map[string]interface{}{
+			"status":      volume.Status,
+			"size":        volume.Size,
+			"encrypted":   volume.Encrypted,
+			"multiattach": volume.MultiAttachEnabled,
+			"volume_type": volume.VolumeType,
+		}
+		if volume.SnapshotID != "" {
+			metadata["snapshotID"] = volume.SnapshotID
+		} else {
+			metadata["snapshotID"] = false
+		}
+
+		var relationships []Relationship
+		for _, attachment := range volume.Attachments {
+			metadata["device"] = attachment.Device
+			relationships = append(relationships, Relationship{Type: "ATTACHED_TO", Target: attachment.InstanceID})
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:               volume.VolumeID,
+			Name:             nameOrID(volume.Tags, volume.VolumeID),
+			Type:             "Volume",
+			AvailabilityZone: volume.AvailabilityZone,
+			Metadata:         metadata,
+			Relationships:    relationships,
+		})
+	}
+	return components
+}
+
+func handleEBSSnapshot(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		snapshot, ok := item.(plugin.EBSSnapshot)
+		if !ok {
+			fmt.Printf("expected type plugin.EBSSnapshot, but got: %T\n", item)
+			continue
+		}
+		components = append(components, InfrastructureComponent{
+			ID:   snapshot.SnapshotID,
+			Name: nameOrID(snapshot.Tags, snapshot.SnapshotID),
+			Type: "Snapshot",
+			Metadata: map[string]interface{}{
+				"Status":      snapshot.Status,
+				"CreatedAt":   snapshot.StartTime,
+				"Description": snapshot.Description,
+				"Size":        snapshot.VolumeSize,
+				"UserID":      snapshot.OwnerID,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "SNAPSHOT_OF",
+					Target: snapshot.VolumeID,
+				},
+			},
+		})
+	}
+	return components
+}
+
+// nameOrID returns the Name tag of an AWS resource, most resources are unnamed.
+func nameOrID(tags plugin.AWSTags, id string) string {
+	if name := tags.Get("Name"); name != "" {
+		return name
+	}
+	return id
+}
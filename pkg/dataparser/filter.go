@@ -0,0 +1,217 @@
+package dataparser
+
+import (
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Filter selects the raw data of a provider instance before it is transformed.
+// Empty include lists select everything. Resources without a namespace or
+// project, like cluster nodes, are never dropped by the selectors.
+type Filter struct {
+	Include           []string // raw data keys, e.g. os_instance
+	Exclude           []string
+	Namespaces        []string // Kubernetes namespaces
+	ExcludeNamespaces []string
+	Projects          []string // OpenStack project IDs or names, AWS account IDs
+	ExcludeProjects   []string
+}
+
+// ParseFilter reads the filters section of a provider entry.
+func ParseFilter(value interface{}) Filter {
+	v, ok := value.(map[string]interface{})
+	if !ok {
+		return Filter{}
+	}
+	return Filter{
+		Include:           stringList(v["include"]),
+		Exclude:           stringList(v["exclude"]),
+		Namespaces:        stringList(v["namespaces"]),
+		ExcludeNamespaces: stringList(v["exclude_namespaces"]),
+		Projects:          stringList(v["projects"]),
+		ExcludeProjects:   stringList(v["exclude_projects"]),
+	}
+}
+
+// Apply returns the selected raw data, the keys that were left out and the
+// number of items dropped per key.
+func (f Filter) Apply(rawData shared.RawData) (shared.RawData, []string, map[string]int) {
+	filtered := make(shared.RawData, len(rawData))
+	var excludedKeys []string
+	dropped := make(map[string]int)
+
+	projects := f.projectIDs(rawData)
+	for key, items := range rawData {
+		if !f.selectsKey(key) {
+			excludedKeys = append(excludedKeys, key)
+			dropped[key] = len(items)
+			continue
+		}
+		var kept []interface{}
+		for _, item := range items {
+			if f.selectsItem(item, projects) {
+				kept = append(kept, item)
+			} else {
+				dropped[key]++
+			}
+		}
+		filtered[key] = kept
+	}
+	return filtered, excludedKeys, dropped
+}
+
+func (f Filter) selectsKey(key string) bool {
+	if len(f.Include) > 0 && !contains(f.Include, key) {
+		return false
+	}
+	return !contains(f.Exclude, key)
+}
+
+func (f Filter) selectsItem(item interface{}, projects projectSelector) bool {
+	if namespace, ok := namespaceOf(item); ok {
+		if len(f.Namespaces) > 0 && !contains(f.Namespaces, namespace) {
+			return false
+		}
+		return !contains(f.ExcludeNamespaces, namespace)
+	}
+	if flavor, ok := item.(plugin.NovaFlavor); ok {
+		// Flavors have no project, they go with the instances booted from them
+		return projects.flavors[flavor.ID]
+	}
+	if project, ok := projectOf(item); ok {
+		return projects.selects(project)
+	}
+	return true
+}
+
+type projectSelector struct {
+	all     bool // no project is included explicitly
+	include map[string]bool
+	exclude map[string]bool
+	flavors map[string]bool // flavors of the selected instances
+}
+
+func (s projectSelector) selects(project string) bool {
+	if !s.all && !s.include[project] {
+		return false
+	}
+	return !s.exclude[project]
+}
+
+// projectIDs resolves the configured project names to IDs using the scanned
+// projects, so servers and volumes carrying only the tenant ID can be matched.
+func (f Filter) projectIDs(rawData shared.RawData) projectSelector {
+	s := projectSelector{all: len(f.Projects) == 0, include: make(map[string]bool), exclude: make(map[string]bool), flavors: make(map[string]bool)}
+	for _, p := range f.Projects {
+		s.include[p] = true
+	}
+	for _, p := range f.ExcludeProjects {
+		s.exclude[p] = true
+	}
+	for _, item := range rawData["os_project"] {
+		project, ok := item.(*models.ProjectDetails)
+		if !ok {
+			continue
+		}
+		if contains(f.Projects, project.Project.Name) {
+			s.include[project.Project.ID] = true
+		}
+		if contains(f.ExcludeProjects, project.Project.Name) {
+			s.exclude[project.Project.ID] = true
+		}
+	}
+	for _, item := range rawData["os_instance"] {
+		if server, ok := item.(models.ServerDetails); ok && s.selects(server.Server.TenantID) {
+			s.flavors[server.Server.Flavor.ID] = true
+		}
+	}
+	return s
+}
+
+// namespaceOf returns the namespace of namespaced Kubernetes resources,
+// PVs belong to the namespace of their claim.
+func namespaceOf(item interface{}) (string, bool) {
+	switch v := item.(type) {
+	case corev1.Pod:
+		return v.Namespace, true
+	case corev1.PersistentVolume:
+		if v.Spec.ClaimRef != nil {
+			return v.Spec.ClaimRef.Namespace, true
+		}
+	case plugin.ResourceEvent:
+		if v.Namespace != "" {
+			return v.Namespace, true
+		}
+	case corev1.Namespace:
+		return v.Name, true
+	case corev1.PersistentVolumeClaim:
+		return v.Namespace, true
+	case corev1.Service:
+		return v.Namespace, true
+	case networkingv1.Ingress:
+		return v.Namespace, true
+	default:
+		if meta, _, ok := workloadObject(item); ok {
+			return meta.Namespace, true
+		}
+	}
+	return "", false
+}
+
+// projectOf returns the project ID of OpenStack resources or the account ID of AWS resources.
+func projectOf(item interface{}) (string, bool) {
+	switch v := item.(type) {
+	case *models.ProjectDetails:
+		return v.Project.ID, true
+	case models.ServerDetails:
+		return v.Server.TenantID, true
+	case models.Volume:
+		return v.TenantID, true
+	case models.Snapshot:
+		return v.OSExtendedSnapshotAttributesProjectID, true
+	case plugin.NeutronNetwork:
+		// Shared and external networks belong to other projects but are used by this one
+		if !v.Shared && !v.External {
+			return v.ProjectID, true
+		}
+	case plugin.NeutronSubnet:
+		return v.ProjectID, true
+	case plugin.NeutronPort:
+		return v.ProjectID, true
+	case plugin.NeutronRouter:
+		return v.ProjectID, true
+	case plugin.NeutronFloatingIP:
+		return v.ProjectID, true
+	case plugin.NeutronSecurityGroup:
+		return v.ProjectID, true
+	case plugin.GlanceImage:
+		// Public, community and shared images are used by other projects than their owner
+		if v.Visibility == "private" {
+			return v.Owner, true
+		}
+	case plugin.SwiftContainer:
+		return v.ProjectID, true
+	case plugin.AWSAccount:
+		return v.ID, true
+	case plugin.EC2Instance:
+		return v.OwnerID, true
+	case plugin.EBSSnapshot:
+		return v.OwnerID, true
+	}
+	return "", false
+}
+
+func stringList(value interface{}) []string {
+	var list []string
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,231 @@
+package dataparser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	_ "github.com/regulatory-transparency-monitor/graph-builder/internal/testflags"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func pod(namespace, name string) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "-" + name)}}
+}
+
+func project(id, name string) *models.ProjectDetails {
+	p := &models.ProjectDetails{}
+	p.Project.ID = id
+	p.Project.Name = name
+	return p
+}
+
+func server(id, tenantID, flavorID string) models.ServerDetails {
+	s := models.ServerDetails{}
+	s.Server.ID = id
+	s.Server.TenantID = tenantID
+	s.Server.Flavor.ID = flavorID
+	return s
+}
+
+func filterRawData() shared.RawData {
+	return shared.RawData{
+		"k8s_node": {corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}},
+		"k8s_pod":  {pod("shop", "web"), pod("shop", "api"), pod("kube-system", "dns")},
+		"k8s_pv": {
+			corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-shop"}, Spec: corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: "shop", Name: "data"}}},
+			corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-unclaimed"}},
+		},
+		"os_project":  {project("p-1", "webshop"), project("p-2", "tenant-under-nda")},
+		"os_instance": {server("s-1", "p-1", "m1.small"), server("s-2", "p-2", "m1.large")},
+		"os_flavor":   {plugin.NovaFlavor{ID: "m1.small"}, plugin.NovaFlavor{ID: "m1.large"}},
+		"os_image": {
+			plugin.GlanceImage{ID: "img-public", Visibility: "public", Owner: "p-admin"},
+			plugin.GlanceImage{ID: "img-1", Visibility: "private", Owner: "p-1"},
+			plugin.GlanceImage{ID: "img-2", Visibility: "private", Owner: "p-2"},
+		},
+		"os_network": {
+			plugin.NeutronNetwork{ID: "net-1", ProjectID: "p-1"},
+			plugin.NeutronNetwork{ID: "net-2", ProjectID: "p-2"},
+			plugin.NeutronNetwork{ID: "net-ext", ProjectID: "p-admin", External: true},
+		},
+	}
+}
+
+// itemNames identifies the kept items of the test data by name or ID
+func itemNames(items []interface{}) []string {
+	var names []string
+	for _, item := range items {
+		switch v := item.(type) {
+		case corev1.Node:
+			names = append(names, v.Name)
+		case corev1.Pod:
+			names = append(names, v.Name)
+		case corev1.PersistentVolume:
+			names = append(names, v.Name)
+		case *models.ProjectDetails:
+			names = append(names, v.Project.ID)
+		case models.ServerDetails:
+			names = append(names, v.Server.ID)
+		case plugin.NovaFlavor:
+			names = append(names, v.ID)
+		case plugin.GlanceImage:
+			names = append(names, v.ID)
+		case plugin.NeutronNetwork:
+			names = append(names, v.ID)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFilterApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		kept     map[string][]string // kept items of the keys checked
+		excluded []string
+		dropped  map[string]int
+	}{
+		{
+			name:    "no filter",
+			kept:    map[string][]string{"k8s_pod": {"api", "dns", "web"}, "os_flavor": {"m1.large", "m1.small"}},
+			dropped: map[string]int{},
+		},
+		{
+			name:     "include keys",
+			filter:   Filter{Include: []string{"k8s_pod", "k8s_node"}},
+			kept:     map[string][]string{"k8s_pod": {"api", "dns", "web"}, "k8s_node": {"worker-1"}},
+			excluded: []string{"k8s_pv", "os_flavor", "os_image", "os_instance", "os_network", "os_project"},
+			dropped:  map[string]int{"k8s_pv": 2, "os_flavor": 2, "os_image": 3, "os_instance": 2, "os_network": 3, "os_project": 2},
+		},
+		{
+			name:     "exclude keys",
+			filter:   Filter{Exclude: []string{"k8s_pod"}},
+			kept:     map[string][]string{"k8s_node": {"worker-1"}},
+			excluded: []string{"k8s_pod"},
+			dropped:  map[string]int{"k8s_pod": 3},
+		},
+		{
+			name:    "namespaces",
+			filter:  Filter{Namespaces: []string{"kube-system"}},
+			kept:    map[string][]string{"k8s_pod": {"dns"}, "k8s_pv": {"pv-unclaimed"}, "k8s_node": {"worker-1"}},
+			dropped: map[string]int{"k8s_pod": 2, "k8s_pv": 1},
+		},
+		{
+			name:    "exclude namespaces",
+			filter:  Filter{ExcludeNamespaces: []string{"kube-system"}},
+			kept:    map[string][]string{"k8s_pod": {"api", "web"}, "k8s_pv": {"pv-shop", "pv-unclaimed"}},
+			dropped: map[string]int{"k8s_pod": 1},
+		},
+		{
+			name:   "project by ID",
+			filter: Filter{Projects: []string{"p-1"}},
+			kept: map[string][]string{
+				"os_project": {"p-1"}, "os_instance": {"s-1"}, "os_flavor": {"m1.small"},
+				"os_image": {"img-1", "img-public"}, "os_network": {"net-1", "net-ext"},
+			},
+			dropped: map[string]int{"os_project": 1, "os_instance": 1, "os_flavor": 1, "os_image": 1, "os_network": 1},
+		},
+		{
+			name:   "project by name",
+			filter: Filter{Projects: []string{"webshop"}},
+			kept: map[string][]string{
+				"os_project": {"p-1"}, "os_instance": {"s-1"}, "os_flavor": {"m1.small"},
+				"os_image": {"img-1", "img-public"}, "os_network": {"net-1", "net-ext"},
+			},
+			dropped: map[string]int{"os_project": 1, "os_instance": 1, "os_flavor": 1, "os_image": 1, "os_network": 1},
+		},
+		{
+			name:   "excluded project by name",
+			filter: Filter{ExcludeProjects: []string{"tenant-under-nda"}},
+			kept: map[string][]string{
+				"os_project": {"p-1"}, "os_instance": {"s-1"}, "os_flavor": {"m1.small"},
+				"os_image": {"img-1", "img-public"}, "k8s_pod": {"api", "dns", "web"},
+			},
+			dropped: map[string]int{"os_project": 1, "os_instance": 1, "os_flavor": 1, "os_image": 1, "os_network": 1},
+		},
+		{
+			name:    "unknown project",
+			filter:  Filter{Projects: []string{"p-9"}},
+			kept:    map[string][]string{"os_project": nil, "os_instance": nil, "os_flavor": nil, "os_image": {"img-public"}, "k8s_node": {"worker-1"}},
+			dropped: map[string]int{"os_project": 2, "os_instance": 2, "os_flavor": 2, "os_image": 2, "os_network": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, excluded, dropped := tt.filter.Apply(filterRawData())
+			for key, want := range tt.kept {
+				if got := itemNames(filtered[key]); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+			sort.Strings(excluded)
+			if !reflect.DeepEqual(excluded, tt.excluded) {
+				t.Errorf("excluded keys = %v, want %v", excluded, tt.excluded)
+			}
+			for key := range filtered {
+				if dropped[key] == 0 {
+					delete(dropped, key)
+				}
+			}
+			if !reflect.DeepEqual(dropped, tt.dropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	got := ParseFilter(map[string]interface{}{
+		"include":            []interface{}{"k8s_pod"},
+		"exclude_namespaces": []interface{}{"kube-system", 1},
+		"projects":           []interface{}{"webshop"},
+	})
+	want := Filter{Include: []string{"k8s_pod"}, ExcludeNamespaces: []string{"kube-system"}, Projects: []string{"webshop"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFilter = %+v, want %+v", got, want)
+	}
+	if got := ParseFilter(nil); !reflect.DeepEqual(got, Filter{}) {
+		t.Errorf("ParseFilter(nil) = %+v, want an empty filter", got)
+	}
+}
+
+// The counts of a filtered scan end up in the scan result on the Metadata node
+func TestTransformDataFilterCounts(t *testing.T) {
+	rawData := shared.RawData{
+		"k8s_pod":  {pod("shop", "web"), pod("kube-system", "dns")},
+		"k8s_node": {corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", UID: "uid-worker-1"}}},
+	}
+	filter := Filter{Exclude: []string{"k8s_node"}, ExcludeNamespaces: []string{"kube-system"}}
+	components, results, err := TransformData("k8s", rawData, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]ResourceScanResult)
+	for _, result := range results {
+		result.Duration = 0
+		got[result.Key] = result
+	}
+	want := map[string]ResourceScanResult{
+		"k8s_node": {Key: "k8s_node", Status: ScanStatusExcluded, FilteredCount: 1},
+		"k8s_pod":  {Key: "k8s_pod", Status: ScanStatusOK, ItemCount: 1, FilteredCount: 1, ComponentCount: got["k8s_pod"].ComponentCount},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %+v, want %+v", got, want)
+	}
+	for _, c := range components {
+		if c.Type == "Pod" && c.Name != "web" {
+			t.Errorf("pod %s of an excluded namespace was transformed", c.Name)
+		}
+		if c.Type == "ClusterNode" {
+			t.Errorf("node of an excluded key was transformed")
+		}
+	}
+}
@@ -1,11 +1,10 @@
 package dataparser
 
-import "time"
-
 type InfrastructureComponent struct {
 	ID               string
 	Name             string
 	Type             string
+	Provider         string // instance ID of the provider the component was collected from
 	AvailabilityZone string
 	Metadata         map[string]interface{}
 	Relationships    []Relationship
@@ -15,9 +14,3 @@ type Relationship struct {
 	Type   string
 	Target string
 }
-
-type ScanMetadata struct {
-	ScanID   string
-	ScanDate time.Time
-	Version  string // e.g., the version of the scanner or the source system
-}
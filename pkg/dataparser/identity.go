@@ -0,0 +1,219 @@
+package dataparser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+)
+
+// IdentityLink is an edge between components of two providers, like the
+// PROVISIONED_BY edge from a cluster node to the instance it runs on or the
+// DESCRIBED_BY edge from a pod to its TILT document, with the rule that
+// matched and its confidence.
+type IdentityLink struct {
+	Type           string
+	SourceType     string
+	SourceID       string
+	SourceProvider string
+	TargetID       string
+	TargetProvider string
+	Rule           string
+	Confidence     float64
+}
+
+// IdentityRule tells whether a cluster node runs on an instance.
+type IdentityRule struct {
+	Name       string
+	Confidence float64
+	Match      func(node InfrastructureComponent, instance InfrastructureComponent) bool
+	// Authoritative, if set, tells whether the rule has the final say on a
+	// node: a node it matches no instance for isn't linked by weaker rules
+	Authoritative func(node InfrastructureComponent) bool
+}
+
+// IdentityRules are tried by decreasing confidence, the first rule matching
+// exactly one instance links a node. A rule matching several instances is
+// ambiguous and skipped.
+var IdentityRules = []IdentityRule{
+	{Name: "providerID", Confidence: 1.0, Match: matchProviderID, Authoritative: hasProviderID},
+	{Name: "systemUUID", Confidence: 0.9, Match: matchSystemUUID},
+	{Name: "hostname", Confidence: 0.6, Match: matchHostname},
+	{Name: "ip", Confidence: 0.5, Match: matchIP},
+}
+
+// RegisterIdentityRule adds a rule to the resolver, keeping the rules ordered by confidence.
+func RegisterIdentityRule(rule IdentityRule) {
+	IdentityRules = append(IdentityRules, rule)
+	sort.SliceStable(IdentityRules, func(i, j int) bool {
+		return IdentityRules[i].Confidence > IdentityRules[j].Confidence
+	})
+}
+
+// ResolveIdentities links the cluster nodes to the instances of the other
+// providers of a version, and the pods and services to the TILT documents
+// describing them.
+func ResolveIdentities(components []InfrastructureComponent) []IdentityLink {
+	return append(resolveInstances(components), resolveDescriptions(components)...)
+}
+
+func resolveInstances(components []InfrastructureComponent) []IdentityLink {
+	var nodes, instances []InfrastructureComponent
+	for _, c := range components {
+		switch c.Type {
+		case "ClusterNode":
+			nodes = append(nodes, c)
+		case "Instance":
+			instances = append(instances, c)
+		}
+	}
+
+	var links []IdentityLink
+	for _, node := range nodes {
+		for _, rule := range IdentityRules {
+			var matches []InfrastructureComponent
+			for _, instance := range instances {
+				if instance.Provider != node.Provider && rule.Match(node, instance) {
+					matches = append(matches, instance)
+				}
+			}
+			if len(matches) > 1 {
+				logger.Debug("Ambiguous identity rule", logger.LogFields{"node": node.Name, "rule": rule.Name, "matches": len(matches)})
+				continue
+			}
+			if len(matches) == 0 && rule.Authoritative != nil && rule.Authoritative(node) {
+				// The instance isn't scanned, e.g. it's filtered or in another cloud
+				logger.Debug("Instance of node not found", logger.LogFields{"node": node.Name, "rule": rule.Name})
+				break
+			}
+			if len(matches) == 1 {
+				links = append(links, IdentityLink{
+					Type:           "PROVISIONED_BY",
+					SourceType:     node.Type,
+					SourceID:       node.ID,
+					SourceProvider: node.Provider,
+					TargetID:       matches[0].ID,
+					TargetProvider: matches[0].Provider,
+					Rule:           rule.Name,
+					Confidence:     rule.Confidence,
+				})
+				break
+			}
+		}
+	}
+	return links
+}
+
+// matchProviderID matches the instance ID at the end of the node's provider
+// ID, e.g. openstack:///<id> or aws:///<zone>/<id>.
+func matchProviderID(node InfrastructureComponent, instance InfrastructureComponent) bool {
+	if !hasProviderID(node) {
+		return false
+	}
+	providerID := node.Metadata["ProviderID"].(string)
+	return providerID[strings.LastIndex(providerID, "/")+1:] == instance.ID
+}
+
+// hasProviderID tells whether the cloud controller set the provider ID of the node.
+func hasProviderID(node InfrastructureComponent) bool {
+	providerID, _ := node.Metadata["ProviderID"].(string)
+	return strings.Contains(providerID, "://")
+}
+
+// matchSystemUUID matches the SMBIOS UUID of the node, which Nova sets to the instance ID.
+func matchSystemUUID(node InfrastructureComponent, instance InfrastructureComponent) bool {
+	systemUUID, _ := node.Metadata["SystemUUID"].(string)
+	return systemUUID != "" && strings.EqualFold(systemUUID, instance.ID)
+}
+
+// matchHostname matches the short hostname of the node to the instance name.
+func matchHostname(node InfrastructureComponent, instance InfrastructureComponent) bool {
+	hostname, _ := node.Metadata["Hostname"].(string)
+	return hostname != "" && shortHostname(hostname) == shortHostname(instance.Name)
+}
+
+// matchIP matches when the node and the instance share an address.
+func matchIP(node InfrastructureComponent, instance InfrastructureComponent) bool {
+	nodeIPs, _ := node.Metadata["IPAddresses"].([]string)
+	instanceIPs, _ := instance.Metadata["IPAddresses"].([]string)
+	for _, a := range nodeIPs {
+		for _, b := range instanceIPs {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveDescriptions links a TILT document to the services named like the
+// service it describes, and to the pods selected by those services or managed
+// by a controller of that name.
+func resolveDescriptions(components []InfrastructureComponent) []IdentityLink {
+	var documents, services, workloads, pods []InfrastructureComponent
+	for _, c := range components {
+		switch c.Type {
+		case "TransparencyDocument":
+			documents = append(documents, c)
+		case "Service":
+			services = append(services, c)
+		case "Pod":
+			pods = append(pods, c)
+		case "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob":
+			workloads = append(workloads, c)
+		}
+	}
+
+	var links []IdentityLink
+	describedBy := func(c InfrastructureComponent, doc InfrastructureComponent, rule string, confidence float64) {
+		links = append(links, IdentityLink{
+			Type:           "DESCRIBED_BY",
+			SourceType:     c.Type,
+			SourceID:       c.ID,
+			SourceProvider: c.Provider,
+			TargetID:       doc.ID,
+			TargetProvider: doc.Provider,
+			Rule:           rule,
+			Confidence:     confidence,
+		})
+	}
+	for _, doc := range documents {
+		matched := make(map[string]bool) // provider/ID of matching services and controllers
+		for _, svc := range services {
+			if strings.EqualFold(svc.Name, doc.Name) {
+				matched[svc.Provider+"/"+svc.ID] = true
+				describedBy(svc, doc, "serviceName", 0.9)
+			}
+		}
+		for _, w := range workloads {
+			if strings.EqualFold(w.Name, doc.Name) {
+				matched[w.Provider+"/"+w.ID] = true
+			}
+		}
+		for _, pod := range pods {
+			rule, confidence := "", 0.0
+			for _, rel := range pod.Relationships {
+				if !matched[pod.Provider+"/"+rel.Target] {
+					continue
+				}
+				switch {
+				case rel.Type == "SELECTED_BY":
+					rule, confidence = "selectedByService", 0.8
+				case rel.Type == "MANAGED_BY" && rule == "":
+					rule, confidence = "controllerName", 0.7
+				}
+			}
+			if rule != "" {
+				describedBy(pod, doc, rule, confidence)
+			}
+		}
+	}
+	return links
+}
+
+func shortHostname(name string) string {
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
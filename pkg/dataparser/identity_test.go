@@ -0,0 +1,132 @@
+package dataparser
+
+import (
+	"reflect"
+	"testing"
+
+	_ "github.com/regulatory-transparency-monitor/graph-builder/internal/testflags"
+)
+
+func clusterNode(id string, metadata map[string]interface{}) InfrastructureComponent {
+	return InfrastructureComponent{ID: id, Name: id, Type: "ClusterNode", Provider: "k8s", Metadata: metadata}
+}
+
+func instance(id, name, provider string, ips ...string) InfrastructureComponent {
+	return InfrastructureComponent{ID: id, Name: name, Type: "Instance", Provider: provider, Metadata: map[string]interface{}{"IPAddresses": ips}}
+}
+
+func TestResolveInstances(t *testing.T) {
+	instances := []InfrastructureComponent{
+		instance("6f1c0e4a-0001", "worker-1", "openstack", "10.0.0.11"),
+		instance("6f1c0e4a-0002", "worker-2", "openstack", "10.0.0.12"),
+		instance("i-0a1b2c3d4e5f60001", "worker-3", "aws", "10.0.0.12"),
+	}
+
+	tests := []struct {
+		name       string
+		node       InfrastructureComponent
+		target     string
+		rule       string
+		confidence float64
+	}{
+		{
+			name:   "openstack provider ID",
+			node:   clusterNode("n1", map[string]interface{}{"ProviderID": "openstack:///6f1c0e4a-0001", "Hostname": "worker-2"}),
+			target: "6f1c0e4a-0001", rule: "providerID", confidence: 1.0,
+		},
+		{
+			name:   "aws provider ID",
+			node:   clusterNode("n2", map[string]interface{}{"ProviderID": "aws:///eu-central-1a/i-0a1b2c3d4e5f60001"}),
+			target: "i-0a1b2c3d4e5f60001", rule: "providerID", confidence: 1.0,
+		},
+		{
+			name:   "system UUID in upper case",
+			node:   clusterNode("n3", map[string]interface{}{"SystemUUID": "6F1C0E4A-0002"}),
+			target: "6f1c0e4a-0002", rule: "systemUUID", confidence: 0.9,
+		},
+		{
+			name:   "hostname without domain",
+			node:   clusterNode("n4", map[string]interface{}{"Hostname": "Worker-1.cluster.local"}),
+			target: "6f1c0e4a-0001", rule: "hostname", confidence: 0.6,
+		},
+		{
+			name:   "ip",
+			node:   clusterNode("n5", map[string]interface{}{"IPAddresses": []string{"192.168.1.5", "10.0.0.11"}}),
+			target: "6f1c0e4a-0001", rule: "ip", confidence: 0.5,
+		},
+		{
+			name: "ambiguous ip",
+			node: clusterNode("n6", map[string]interface{}{"IPAddresses": []string{"10.0.0.12"}}),
+		},
+		{
+			name:   "hostname wins over ip",
+			node:   clusterNode("n7", map[string]interface{}{"Hostname": "worker-2", "IPAddresses": []string{"10.0.0.11"}}),
+			target: "6f1c0e4a-0002", rule: "hostname", confidence: 0.6,
+		},
+		{
+			name: "provider ID of an instance that isn't scanned",
+			node: clusterNode("n8", map[string]interface{}{"ProviderID": "openstack:///6f1c0e4a-9999", "Hostname": "worker-1", "IPAddresses": []string{"10.0.0.11"}}),
+		},
+		{
+			name:   "provider ID without scheme is ignored",
+			node:   clusterNode("n9", map[string]interface{}{"ProviderID": "6f1c0e4a-9999", "Hostname": "worker-1"}),
+			target: "6f1c0e4a-0001", rule: "hostname", confidence: 0.6,
+		},
+		{
+			name: "no match",
+			node: clusterNode("n10", map[string]interface{}{"Hostname": "db-1"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := ResolveIdentities(append([]InfrastructureComponent{tt.node}, instances...))
+			if tt.target == "" {
+				if len(links) != 0 {
+					t.Errorf("got links %+v, want none", links)
+				}
+				return
+			}
+			if len(links) != 1 {
+				t.Fatalf("got links %+v, want one", links)
+			}
+			link := links[0]
+			if link.Type != "PROVISIONED_BY" || link.SourceID != tt.node.ID || link.TargetID != tt.target || link.Rule != tt.rule || link.Confidence != tt.confidence {
+				t.Errorf("got link %+v, want %s to %s by %s with %v", link, tt.node.ID, tt.target, tt.rule, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestResolveInstancesSameProvider(t *testing.T) {
+	node := clusterNode("n1", map[string]interface{}{"Hostname": "worker-1"})
+	// An instance of the cluster's own provider isn't what the node runs on
+	links := ResolveIdentities([]InfrastructureComponent{node, instance("x", "worker-1", "k8s")})
+	if len(links) != 0 {
+		t.Errorf("got links %+v, want none", links)
+	}
+}
+
+func TestResolveDescriptions(t *testing.T) {
+	components := []InfrastructureComponent{
+		{ID: "tilt/billing", Name: "billing", Type: "TransparencyDocument", Provider: "tilt"},
+		{ID: "svc-1", Name: "Billing", Type: "Service", Provider: "k8s"},
+		{ID: "deploy-1", Name: "billing", Type: "Deployment", Provider: "k8s"},
+		{ID: "pod-1", Type: "Pod", Provider: "k8s", Relationships: []Relationship{{Type: "MANAGED_BY", Target: "deploy-1"}, {Type: "SELECTED_BY", Target: "svc-1"}}},
+		{ID: "pod-2", Type: "Pod", Provider: "k8s", Relationships: []Relationship{{Type: "MANAGED_BY", Target: "deploy-1"}}},
+		{ID: "pod-3", Type: "Pod", Provider: "k8s", Relationships: []Relationship{{Type: "MANAGED_BY", Target: "deploy-2"}}},
+		// Same IDs in another cluster aren't described by the document
+		{ID: "pod-4", Type: "Pod", Provider: "k8s-2", Relationships: []Relationship{{Type: "SELECTED_BY", Target: "svc-1"}}},
+	}
+
+	got := make(map[string]string)
+	for _, link := range ResolveIdentities(components) {
+		if link.Type != "DESCRIBED_BY" || link.TargetID != "tilt/billing" {
+			t.Errorf("unexpected link %+v", link)
+		}
+		got[link.SourceID] = link.Rule
+	}
+	want := map[string]string{"svc-1": "serviceName", "pod-1": "selectedByService", "pod-2": "controllerName"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got rules %v, want %v", got, want)
+	}
+}
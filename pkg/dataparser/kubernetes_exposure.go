@@ -0,0 +1,187 @@
+package dataparser
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// serviceSelector is what pods need of a service to be linked to it.
+type serviceSelector struct {
+	ID        string
+	Namespace string
+	Selector  labels.Selector
+}
+
+// services holds the services of a scan until ingresses and pods are transformed.
+type services struct {
+	byName    map[string]string // namespace/name to service UID
+	selectors []serviceSelector
+}
+
+// handleNamespace transforms namespaces and records their has_pd declarations
+// for the pods in them.
+func handleNamespace(data []interface{}, decl *pdDeclarations) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		ns, ok := item.(corev1.Namespace)
+		if !ok {
+			fmt.Printf("Expected type v1.Namespace, but got: %T\n", item)
+			continue
+		}
+		component := InfrastructureComponent{
+			ID:   string(ns.UID),
+			Name: ns.Name,
+			Type: "Namespace",
+			Metadata: map[string]interface{}{
+				"CreatedAt": ns.CreationTimestamp.Format(time.RFC3339),
+				"Phase":     string(ns.Status.Phase),
+			},
+		}
+		components = append(components, declarePD(decl.namespaces, ns.Name, ns.ObjectMeta, string(ns.UID), "Namespace", &component)...)
+		components = append(components, component)
+	}
+	return components
+}
+
+// handleService transforms services and records their selectors. A service is
+// exposed when it is reachable from outside the cluster on its own, through a
+// node port, a load balancer or external IPs.
+func handleService(data []interface{}, svcs *services) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		svc, ok := item.(corev1.Service)
+		if !ok {
+			fmt.Printf("Expected type v1.Service, but got: %T\n", item)
+			continue
+		}
+		svcs.byName[namespacedKey(svc.Namespace, svc.Name)] = string(svc.UID)
+		// Services without selector have their endpoints managed by hand
+		if len(svc.Spec.Selector) > 0 {
+			svcs.selectors = append(svcs.selectors, serviceSelector{
+				ID:        string(svc.UID),
+				Namespace: svc.Namespace,
+				Selector:  labels.SelectorFromSet(svc.Spec.Selector),
+			})
+		}
+
+		addresses := append([]string{}, svc.Spec.ExternalIPs...)
+		for _, lb := range svc.Status.LoadBalancer.Ingress {
+			addresses = append(addresses, loadBalancerAddress(lb.IP, lb.Hostname))
+		}
+		exposed := svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer || len(addresses) > 0
+
+		components = append(components, InfrastructureComponent{
+			ID:   string(svc.UID),
+			Name: svc.Name,
+			Type: "Service",
+			Metadata: map[string]interface{}{
+				"Namespace":         svc.Namespace,
+				"CreatedAt":         svc.CreationTimestamp.Format(time.RFC3339),
+				"ServiceType":       string(svc.Spec.Type),
+				"ClusterIP":         svc.Spec.ClusterIP,
+				"ExternalAddresses": addresses,
+				"Selector":          labels.Set(svc.Spec.Selector).String(),
+				"Exposed":           exposed,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "IN_NAMESPACE",
+					Target: svc.Namespace,
+				},
+			},
+		})
+	}
+	return components
+}
+
+// handleIngress transforms ingresses and links them to the services of their
+// rules and default backend, services of other scans or namespaces are skipped.
+func handleIngress(data []interface{}, svcs *services) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		ing, ok := item.(networkingv1.Ingress)
+		if !ok {
+			fmt.Printf("Expected type v1.Ingress, but got: %T\n", item)
+			continue
+		}
+
+		relationships := []Relationship{{Type: "IN_NAMESPACE", Target: ing.Namespace}}
+		seen := make(map[string]bool)
+		routeTo := func(backend *networkingv1.IngressBackend) {
+			if backend == nil || backend.Service == nil {
+				return
+			}
+			id, exists := svcs.byName[namespacedKey(ing.Namespace, backend.Service.Name)]
+			if !exists || seen[id] {
+				return
+			}
+			seen[id] = true
+			relationships = append(relationships, Relationship{Type: "ROUTES_TO", Target: id})
+		}
+
+		routeTo(ing.Spec.DefaultBackend)
+		var hosts []string
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				hosts = append(hosts, rule.Host)
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, p := range rule.HTTP.Paths {
+				routeTo(&p.Backend)
+			}
+		}
+
+		var addresses []string
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			addresses = append(addresses, loadBalancerAddress(lb.IP, lb.Hostname))
+		}
+		ingressClass := ""
+		if ing.Spec.IngressClassName != nil {
+			ingressClass = *ing.Spec.IngressClassName
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:   string(ing.UID),
+			Name: ing.Name,
+			Type: "Ingress",
+			Metadata: map[string]interface{}{
+				"Namespace":    ing.Namespace,
+				"CreatedAt":    ing.CreationTimestamp.Format(time.RFC3339),
+				"IngressClass": ingressClass,
+				"Hosts":        hosts,
+				"Addresses":    addresses,
+				"TLS":          len(ing.Spec.TLS) > 0,
+			},
+			Relationships: relationships,
+		})
+	}
+	return components
+}
+
+// serviceRelationships links a pod to every service selecting it.
+func serviceRelationships(pod corev1.Pod, svcs *services) []Relationship {
+	var relationships []Relationship
+	if svcs == nil {
+		return relationships
+	}
+	podLabels := labels.Set(pod.Labels)
+	for _, s := range svcs.selectors {
+		if s.Namespace == pod.Namespace && s.Selector.Matches(podLabels) {
+			relationships = append(relationships, Relationship{Type: "SELECTED_BY", Target: s.ID})
+		}
+	}
+	return relationships
+}
+
+func loadBalancerAddress(ip string, hostname string) string {
+	if ip != "" {
+		return ip
+	}
+	return hostname
+}
@@ -0,0 +1,132 @@
+package dataparser
+
+import (
+	"reflect"
+	"testing"
+
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestServiceExposed(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      corev1.ServiceSpec
+		status    corev1.ServiceStatus
+		exposed   bool
+		addresses []string
+	}{
+		{name: "cluster IP", spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}, addresses: []string{}},
+		{name: "node port", spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort}, exposed: true, addresses: []string{}},
+		{name: "pending load balancer", spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}, exposed: true, addresses: []string{}},
+		{
+			name: "load balancer",
+			spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+				{IP: "203.0.113.7"}, {Hostname: "lb.example.com"},
+			}}},
+			exposed:   true,
+			addresses: []string{"203.0.113.7", "lb.example.com"},
+		},
+		{
+			name:      "external IPs",
+			spec:      corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ExternalIPs: []string{"198.51.100.1"}},
+			exposed:   true,
+			addresses: []string{"198.51.100.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := corev1.Service{ObjectMeta: object("svc"), Spec: tt.spec, Status: tt.status}
+			c := component(t, transformK8s(t, shared.RawData{"k8s_service": {svc}}), "svc")
+			if got := c.Metadata["Exposed"]; got != tt.exposed {
+				t.Errorf("Exposed = %v, want %v", got, tt.exposed)
+			}
+			if got := c.Metadata["ExternalAddresses"]; !reflect.DeepEqual(got, tt.addresses) {
+				t.Errorf("ExternalAddresses = %v, want %v", got, tt.addresses)
+			}
+		})
+	}
+}
+
+func TestServiceSelectedPods(t *testing.T) {
+	service := func(uid string, selector map[string]string) corev1.Service {
+		return corev1.Service{ObjectMeta: object(uid), Spec: corev1.ServiceSpec{Selector: selector}}
+	}
+	labelled := func(uid, namespace string, labels map[string]string) corev1.Pod {
+		meta := object(uid)
+		meta.Namespace = namespace
+		meta.Labels = labels
+		return corev1.Pod{ObjectMeta: meta}
+	}
+	components := transformK8s(t, shared.RawData{
+		"k8s_service": {
+			service("web", map[string]string{"app": "web"}),
+			service("web-canary", map[string]string{"app": "web", "track": "canary"}),
+			// Endpoints of services without selector are managed by hand
+			service("external-db", nil),
+		},
+		"k8s_pod": {
+			labelled("web-1", "shop", map[string]string{"app": "web"}),
+			labelled("web-2", "shop", map[string]string{"app": "web", "track": "canary"}),
+			labelled("api-1", "shop", map[string]string{"app": "api"}),
+			labelled("web-other", "staging", map[string]string{"app": "web"}),
+			labelled("unlabelled", "shop", nil),
+		},
+	})
+	tests := map[string][]string{
+		"web-1":      {"web"},
+		"web-2":      {"web", "web-canary"},
+		"api-1":      nil,
+		"web-other":  nil,
+		"unlabelled": nil,
+	}
+	for pod, want := range tests {
+		if got := targets(component(t, components, pod), "SELECTED_BY"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s SELECTED_BY = %v, want %v", pod, got, want)
+		}
+	}
+}
+
+func TestIngressRoutes(t *testing.T) {
+	backend := func(service string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: service}}
+	}
+	paths := func(services ...string) *networkingv1.HTTPIngressRuleValue {
+		rule := &networkingv1.HTTPIngressRuleValue{}
+		for _, s := range services {
+			rule.Paths = append(rule.Paths, networkingv1.HTTPIngressPath{Path: "/" + s, Backend: backend(s)})
+		}
+		return rule
+	}
+	defaultBackend := backend("web")
+	svc := func(uid, namespace string) corev1.Service {
+		meta := object(uid)
+		meta.Namespace = namespace
+		return corev1.Service{ObjectMeta: meta}
+	}
+	ingress := networkingv1.Ingress{
+		ObjectMeta: object("ing"),
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &defaultBackend,
+			Rules: []networkingv1.IngressRule{
+				{Host: "shop.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: paths("web", "api")}},
+				// admin is a service of another namespace, missing one of the scan
+				{Host: "admin.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: paths("admin", "missing")}},
+				{Host: "empty.example.com"},
+			},
+		},
+	}
+	components := transformK8s(t, shared.RawData{
+		"k8s_service": {svc("web", "shop"), svc("api", "shop"), svc("admin", "ops")},
+		"k8s_ingress": {ingress},
+	})
+	ing := component(t, components, "ing")
+	if got, want := targets(ing, "ROUTES_TO"), []string{"api", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ROUTES_TO = %v, want %v", got, want)
+	}
+	if got, want := ing.Metadata["Hosts"], []string{"shop.example.com", "admin.example.com", "empty.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Hosts = %v, want %v", got, want)
+	}
+}
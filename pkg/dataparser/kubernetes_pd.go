@@ -0,0 +1,96 @@
+package dataparser
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pdDeclarations holds the has_pd declarations of namespaces, workload
+// controllers and claims until the pods and volumes they cover are transformed.
+type pdDeclarations struct {
+	namespaces map[string]string // namespace name to PDIndicator ID
+	workloads  map[string]string // workload UID to PDIndicator ID
+	claims     map[string]string // namespace/name of a claim to PDIndicator ID
+}
+
+func newPDDeclarations() *pdDeclarations {
+	return &pdDeclarations{namespaces: make(map[string]string), workloads: make(map[string]string), claims: make(map[string]string)}
+}
+
+// declarePD turns the has_pd annotation of a namespace, controller or claim
+// into a PDIndicator, and a Finding if it's invalid, and records the
+// declaration under key.
+func declarePD(declared map[string]string, key string, meta metav1.ObjectMeta, ownerID string, ownerType string, component *InfrastructureComponent) []InfrastructureComponent {
+	annotation, hasPD := meta.Annotations["has_pd"]
+	if !hasPD {
+		return nil
+	}
+	pd, pdRelationships := pdComponents(ownerID, meta.Name, ownerType, annotation)
+	component.Relationships = append(component.Relationships, pdRelationships...)
+	declared[key] = pd[0].ID
+	return pd
+}
+
+// inherited links a pod to the declarations of its namespace and of every
+// controller in its owner chain, e.g. the Deployment and its ReplicaSet.
+func (d *pdDeclarations) inherited(pod corev1.Pod, owners map[string]string) []Relationship {
+	if d == nil {
+		return nil
+	}
+	var relationships []Relationship
+	if id, exists := d.namespaces[pod.Namespace]; exists {
+		relationships = append(relationships, Relationship{Type: "INHERITS_PD", Target: id})
+	}
+	for _, owner := range ownerChain(pod.ObjectMeta, owners) {
+		if id, exists := d.workloads[owner]; exists {
+			relationships = append(relationships, Relationship{Type: "INHERITS_PD", Target: id})
+		}
+	}
+	return relationships
+}
+
+// claimed links a PV to the declaration of the claim bound to it.
+func (d *pdDeclarations) claimed(pv corev1.PersistentVolume) []Relationship {
+	if d == nil || pv.Spec.ClaimRef == nil {
+		return nil
+	}
+	if id, exists := d.claims[namespacedKey(pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)]; exists {
+		return []Relationship{{Type: "INHERITS_PD", Target: id}}
+	}
+	return nil
+}
+
+// handlePVC transforms the listed claims and records their declarations for
+// the PVs bound to them. Pods only create the claims missing here.
+func handlePVC(data []interface{}, claims map[string]bool, decl *pdDeclarations) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		pvc, ok := item.(corev1.PersistentVolumeClaim)
+		if !ok {
+			fmt.Printf("Expected type v1.PersistentVolumeClaim, but got: %T\n", item)
+			continue
+		}
+		// PVC names are only unique within a namespace
+		id := namespacedKey(pvc.Namespace, pvc.Name)
+		claims[id] = true
+
+		component := InfrastructureComponent{
+			ID:   id,
+			Name: pvc.Name,
+			Type: "PersistentVolumeClaim",
+			Metadata: map[string]interface{}{
+				"Namespace": pvc.Namespace,
+				"CreatedAt": pvc.CreationTimestamp.Format(time.RFC3339),
+			},
+		}
+		if pvc.Spec.VolumeName != "" {
+			component.Relationships = append(component.Relationships, Relationship{Type: "BINDS_TO", Target: pvc.Spec.VolumeName})
+		}
+		components = append(components, declarePD(decl.claims, id, pvc.ObjectMeta, id, "PersistentVolumeClaim", &component)...)
+		components = append(components, component)
+	}
+	return components
+}
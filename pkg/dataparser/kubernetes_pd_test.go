@@ -0,0 +1,138 @@
+package dataparser
+
+import (
+	"reflect"
+	"testing"
+
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testPD = `{"dataCategories": [{"name": "email", "purpose": "billing", "legalBasis": "contract", "storage": "10 years"}]}`
+
+// declaring adds a has_pd annotation to meta
+func declaring(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	meta.Annotations = map[string]string{"has_pd": testPD}
+	return meta
+}
+
+func TestPDInheritance(t *testing.T) {
+	namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", UID: "ns-shop"}}
+	deployment := appsv1.Deployment{ObjectMeta: object("deploy")}
+	replicaSet := appsv1.ReplicaSet{ObjectMeta: object("rs", "deploy")}
+	pod := corev1.Pod{ObjectMeta: object("pod", "rs")}
+
+	tests := []struct {
+		name      string
+		declared  []string // of namespace, deploy, rs and pod
+		hasPD     []string
+		inherited []string
+	}{
+		{name: "undeclared"},
+		{name: "namespace", declared: []string{"namespace"}, inherited: []string{"pd_indicator_ns-shop"}},
+		{name: "top controller", declared: []string{"deploy"}, inherited: []string{"pd_indicator_deploy"}},
+		{name: "direct controller", declared: []string{"rs"}, inherited: []string{"pd_indicator_rs"}},
+		{name: "pod", declared: []string{"pod"}, hasPD: []string{"pd_indicator_pod"}},
+		{
+			// Declarations add up, the pod's own stays apart from the inherited ones
+			name:      "every level",
+			declared:  []string{"namespace", "deploy", "rs", "pod"},
+			hasPD:     []string{"pd_indicator_pod"},
+			inherited: []string{"pd_indicator_deploy", "pd_indicator_ns-shop", "pd_indicator_rs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, d, rs, p := namespace, deployment, replicaSet, pod
+			for _, level := range tt.declared {
+				switch level {
+				case "namespace":
+					ns.ObjectMeta = declaring(ns.ObjectMeta)
+				case "deploy":
+					d.ObjectMeta = declaring(d.ObjectMeta)
+				case "rs":
+					rs.ObjectMeta = declaring(rs.ObjectMeta)
+				case "pod":
+					p.ObjectMeta = declaring(p.ObjectMeta)
+				}
+			}
+			components := transformK8s(t, shared.RawData{
+				"k8s_namespace":  {ns},
+				"k8s_deployment": {d},
+				"k8s_replicaset": {rs},
+				"k8s_pod":        {p},
+			})
+			c := component(t, components, "pod")
+			if got := targets(c, "HAS_PD"); !reflect.DeepEqual(got, tt.hasPD) {
+				t.Errorf("HAS_PD = %v, want %v", got, tt.hasPD)
+			}
+			if got := targets(c, "INHERITS_PD"); !reflect.DeepEqual(got, tt.inherited) {
+				t.Errorf("INHERITS_PD = %v, want %v", got, tt.inherited)
+			}
+		})
+	}
+}
+
+// PVs inherit the declaration of the claim bound to them, claims of the same
+// name in other namespaces don't count
+func TestPDClaimInheritance(t *testing.T) {
+	claim := func(namespace string, declared bool) corev1.PersistentVolumeClaim {
+		meta := metav1.ObjectMeta{Namespace: namespace, Name: "data"}
+		if declared {
+			meta = declaring(meta)
+		}
+		return corev1.PersistentVolumeClaim{ObjectMeta: meta}
+	}
+	pv := func(name, namespace string) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+			Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: namespace, Name: "data"}},
+		}
+	}
+	components := transformK8s(t, shared.RawData{
+		"k8s_pvc": {claim("shop", true), claim("staging", false)},
+		"k8s_pv":  {pv("pv-shop", "shop"), pv("pv-staging", "staging"), pv("pv-unbound", "")},
+	})
+	tests := map[string][]string{
+		"uid-pv-shop":    {"pd_indicator_shop/data"},
+		"uid-pv-staging": nil,
+		"uid-pv-unbound": nil,
+	}
+	for id, want := range tests {
+		if got := targets(component(t, components, id), "INHERITS_PD"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s INHERITS_PD = %v, want %v", id, got, want)
+		}
+	}
+}
+
+// Indicators name the kind and name of the object that declared them
+func TestPDDeclaredOn(t *testing.T) {
+	components := transformK8s(t, shared.RawData{
+		"k8s_namespace":  {corev1.Namespace{ObjectMeta: declaring(metav1.ObjectMeta{Name: "shop", UID: "ns-shop"})}},
+		"k8s_deployment": {appsv1.Deployment{ObjectMeta: declaring(object("deploy"))}},
+		"k8s_pvc":        {corev1.PersistentVolumeClaim{ObjectMeta: declaring(metav1.ObjectMeta{Namespace: "shop", Name: "data"})}},
+		"k8s_pod":        {corev1.Pod{ObjectMeta: declaring(object("pod"))}},
+	})
+	tests := []struct {
+		id         string
+		declaredOn string
+		declaredBy string
+	}{
+		{id: "pd_indicator_ns-shop", declaredOn: "Namespace", declaredBy: "shop"},
+		{id: "pd_indicator_deploy", declaredOn: "Deployment", declaredBy: "deploy"},
+		{id: "pd_indicator_shop/data", declaredOn: "PersistentVolumeClaim", declaredBy: "data"},
+		{id: "pd_indicator_pod", declaredOn: "Pod", declaredBy: "pod"},
+	}
+	for _, tt := range tests {
+		c := component(t, components, tt.id)
+		if c.Metadata["DeclaredOn"] != tt.declaredOn || c.Metadata["DeclaredBy"] != tt.declaredBy {
+			t.Errorf("%s declared on %v by %v, want on %s by %s", tt.id, c.Metadata["DeclaredOn"], c.Metadata["DeclaredBy"], tt.declaredOn, tt.declaredBy)
+		}
+		if c.Metadata["Valid"] != true {
+			t.Errorf("%s isn't valid", tt.id)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package dataparser
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CSI drivers whose volume handle is the ID of a Volume of a cloud provider
+var volumeCSIDrivers = map[string]bool{
+	"cinder.csi.openstack.org": true,
+	"ebs.csi.aws.com":          true,
+}
+
+// pvStorage resolves where a PV stores its data. Block volumes of a cloud
+// provider resolve to the ID of their Volume, everything else to a storage
+// component of its own type. The source names the kind of PV source, it is
+// "unknown" when neither could be resolved.
+func pvStorage(pv corev1.PersistentVolume) (source string, volumeID string, storage *InfrastructureComponent) {
+	src := pv.Spec.PersistentVolumeSource
+	node := pvNodeName(pv)
+	switch {
+	case src.Cinder != nil:
+		return "cinder", src.Cinder.VolumeID, nil
+	case src.AWSElasticBlockStore != nil:
+		// In-tree EBS volume IDs may be given as aws://<zone>/<volume-id>
+		id := src.AWSElasticBlockStore.VolumeID
+		return "awsElasticBlockStore", id[strings.LastIndex(id, "/")+1:], nil
+	case src.CSI != nil:
+		if volumeCSIDrivers[src.CSI.Driver] {
+			return "csi", src.CSI.VolumeHandle, nil
+		}
+		return "csi", "", &InfrastructureComponent{
+			ID:   "csi:" + src.CSI.Driver + ":" + src.CSI.VolumeHandle,
+			Name: src.CSI.VolumeHandle,
+			Type: "CSIVolume",
+			Metadata: map[string]interface{}{
+				"Driver":       src.CSI.Driver,
+				"VolumeHandle": src.CSI.VolumeHandle,
+				"FSType":       src.CSI.FSType,
+				"ReadOnly":     src.CSI.ReadOnly,
+			},
+		}
+	case src.NFS != nil:
+		return "nfs", "", &InfrastructureComponent{
+			ID:   "nfs:" + src.NFS.Server + ":" + src.NFS.Path,
+			Name: src.NFS.Server + ":" + src.NFS.Path,
+			Type: "NFSShare",
+			Metadata: map[string]interface{}{
+				"Server":   src.NFS.Server,
+				"Path":     src.NFS.Path,
+				"ReadOnly": src.NFS.ReadOnly,
+			},
+		}
+	case src.HostPath != nil:
+		return "hostPath", "", nodeStorage("HostPathVolume", "hostpath:", node, src.HostPath.Path)
+	case src.Local != nil:
+		return "local", "", nodeStorage("LocalVolume", "local:", node, src.Local.Path)
+	}
+	return "unknown", "", nil
+}
+
+// nodeStorage is a directory or device of a cluster node, the node is unknown
+// for hostPath volumes without node affinity.
+func nodeStorage(storageType string, prefix string, node string, path string) *InfrastructureComponent {
+	storage := &InfrastructureComponent{
+		ID:   prefix + node + ":" + path,
+		Name: path,
+		Type: storageType,
+		Metadata: map[string]interface{}{
+			"Path": path,
+			"Node": node,
+		},
+	}
+	if node != "" {
+		storage.Relationships = []Relationship{{Type: "LOCATED_ON", Target: node}}
+	}
+	return storage
+}
+
+// pvNodeName returns the node a PV is pinned to by its node affinity, if it is exactly one.
+func pvNodeName(pv corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	terms := pv.Spec.NodeAffinity.Required.NodeSelectorTerms
+	if len(terms) != 1 {
+		return ""
+	}
+	for _, expr := range terms[0].MatchExpressions {
+		if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+			return expr.Values[0]
+		}
+	}
+	return ""
+}
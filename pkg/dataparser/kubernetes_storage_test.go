@@ -0,0 +1,178 @@
+package dataparser
+
+import (
+	"reflect"
+	"testing"
+
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pinned is the node affinity of a PV pinned to node
+func pinned(node string) *corev1.VolumeNodeAffinity {
+	return &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{node}}},
+	}}}}
+}
+
+func TestPVStorage(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     corev1.PersistentVolumeSpec
+		source   string
+		volumeID string
+		storage  *InfrastructureComponent // ID, Type and relationships checked
+	}{
+		{
+			name:     "cinder",
+			spec:     corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{Cinder: &corev1.CinderPersistentVolumeSource{VolumeID: "vol-1"}}},
+			source:   "cinder",
+			volumeID: "vol-1",
+		},
+		{
+			name:     "aws EBS",
+			spec:     corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{AWSElasticBlockStore: &corev1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-0abc"}}},
+			source:   "awsElasticBlockStore",
+			volumeID: "vol-0abc",
+		},
+		{
+			name:     "aws EBS with zone",
+			spec:     corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{AWSElasticBlockStore: &corev1.AWSElasticBlockStoreVolumeSource{VolumeID: "aws://eu-central-1a/vol-0abc"}}},
+			source:   "awsElasticBlockStore",
+			volumeID: "vol-0abc",
+		},
+		{
+			name:     "CSI volume of a cloud provider",
+			spec:     corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "cinder.csi.openstack.org", VolumeHandle: "vol-2"}}},
+			source:   "csi",
+			volumeID: "vol-2",
+		},
+		{
+			name:    "other CSI volume",
+			spec:    corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "rbd.csi.ceph.com", VolumeHandle: "0001-abc"}}},
+			source:  "csi",
+			storage: &InfrastructureComponent{ID: "csi:rbd.csi.ceph.com:0001-abc", Type: "CSIVolume"},
+		},
+		{
+			name:    "NFS",
+			spec:    corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{NFS: &corev1.NFSVolumeSource{Server: "nas-1", Path: "/exports/shop"}}},
+			source:  "nfs",
+			storage: &InfrastructureComponent{ID: "nfs:nas-1:/exports/shop", Type: "NFSShare"},
+		},
+		{
+			name: "hostPath pinned to a node",
+			spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}},
+				NodeAffinity:           pinned("worker-1"),
+			},
+			source: "hostPath",
+			storage: &InfrastructureComponent{ID: "hostpath:worker-1:/data", Type: "HostPathVolume",
+				Relationships: []Relationship{{Type: "LOCATED_ON", Target: "worker-1"}}},
+		},
+		{
+			name:    "hostPath on any node",
+			spec:    corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}}},
+			source:  "hostPath",
+			storage: &InfrastructureComponent{ID: "hostpath::/data", Type: "HostPathVolume"},
+		},
+		{
+			name: "local",
+			spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/ssd1"}},
+				NodeAffinity:           pinned("worker-2"),
+			},
+			source: "local",
+			storage: &InfrastructureComponent{ID: "local:worker-2:/mnt/disks/ssd1", Type: "LocalVolume",
+				Relationships: []Relationship{{Type: "LOCATED_ON", Target: "worker-2"}}},
+		},
+		{
+			name:   "unknown",
+			spec:   corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{ISCSI: &corev1.ISCSIPersistentVolumeSource{TargetPortal: "10.0.0.1"}}},
+			source: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, volumeID, storage := pvStorage(corev1.PersistentVolume{Spec: tt.spec})
+			if source != tt.source || volumeID != tt.volumeID {
+				t.Errorf("pvStorage = %q, %q, want %q, %q", source, volumeID, tt.source, tt.volumeID)
+			}
+			if (storage == nil) != (tt.storage == nil) {
+				t.Fatalf("storage = %+v, want %+v", storage, tt.storage)
+			}
+			if storage == nil {
+				return
+			}
+			if storage.ID != tt.storage.ID || storage.Type != tt.storage.Type || !reflect.DeepEqual(storage.Relationships, tt.storage.Relationships) {
+				t.Errorf("storage = %+v, want %+v", storage, tt.storage)
+			}
+		})
+	}
+}
+
+// PVCs are keyed by namespace/name, pods and PVs of claims of the same name in
+// different namespaces link up with their own claim
+func TestPVCLinks(t *testing.T) {
+	pv := func(name, namespace string) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+			Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: namespace, Name: "data"}},
+		}
+	}
+	podUsing := func(uid, namespace string) corev1.Pod {
+		meta := object(uid)
+		meta.Namespace = namespace
+		return corev1.Pod{ObjectMeta: meta, Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+		}}}}
+	}
+	tests := []struct {
+		name string
+		raw  shared.RawData
+	}{
+		{
+			name: "claims created by pods",
+			raw: shared.RawData{
+				"k8s_pv":  {pv("pv-shop", "shop"), pv("pv-staging", "staging")},
+				"k8s_pod": {podUsing("web", "shop"), podUsing("web-staging", "staging")},
+			},
+		},
+		{
+			name: "listed claims",
+			raw: shared.RawData{
+				"k8s_pvc": {
+					corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "data"}, Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-shop"}},
+					corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "staging", Name: "data"}, Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-staging"}},
+				},
+				"k8s_pv":  {pv("pv-shop", "shop"), pv("pv-staging", "staging")},
+				"k8s_pod": {podUsing("web", "shop"), podUsing("web-staging", "staging")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := transformK8s(t, tt.raw)
+			claims := 0
+			for _, c := range components {
+				if c.Type == "PersistentVolumeClaim" {
+					claims++
+				}
+			}
+			if claims != 2 {
+				t.Errorf("%d claims, want one per namespace", claims)
+			}
+			links := map[string][2]string{"web": {"shop/data", "pv-shop"}, "web-staging": {"staging/data", "pv-staging"}}
+			for pod, want := range links {
+				if got := targets(component(t, components, pod), "USES_PVC"); !reflect.DeepEqual(got, []string{want[0]}) {
+					t.Errorf("%s USES_PVC = %v, want %s", pod, got, want[0])
+				}
+				if got := targets(component(t, components, want[0]), "BINDS_TO"); !reflect.DeepEqual(got, []string{want[1]}) {
+					t.Errorf("%s BINDS_TO = %v, want %s", want[0], got, want[1])
+				}
+			}
+		})
+	}
+}
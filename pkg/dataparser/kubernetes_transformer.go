@@ -1,11 +1,11 @@
 package dataparser
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -14,11 +14,42 @@ func (k *KubernetesTransformer) Transform(key string, data []interface{}) ([]Inf
 	switch key {
 	case "k8s_pv":
 		k.pvcToPVMap = createPVCToPVMapFromRawData(data)
-		return handlePV(data), nil
+		return handlePV(data, k.declarations), nil
+	case "k8s_pvc":
+		if k.declarations == nil {
+			k.declarations = newPDDeclarations()
+		}
+		k.claims = make(map[string]bool)
+		return handlePVC(data, k.claims, k.declarations), nil
 	case "k8s_node":
 		return handleNode(data), nil
 	case "k8s_pod":
-		return handlePod(data, k.pvcToPVMap), nil
+		return handlePod(data, k.pvcToPVMap, k.owners, k.services, k.claims, k.declarations), nil
+	case "k8s_namespace":
+		if k.declarations == nil {
+			k.declarations = newPDDeclarations()
+		}
+		return handleNamespace(data, k.declarations), nil
+	case "k8s_service":
+		if k.services == nil {
+			k.services = &services{byName: make(map[string]string)}
+		}
+		return handleService(data, k.services), nil
+	case "k8s_ingress":
+		if k.services == nil {
+			k.services = &services{byName: make(map[string]string)}
+		}
+		return handleIngress(data, k.services), nil
+	case "k8s_event":
+		return handleEvent(data), nil
+	case "k8s_deployment", "k8s_statefulset", "k8s_daemonset", "k8s_replicaset", "k8s_job", "k8s_cronjob":
+		if k.owners == nil {
+			k.owners = make(map[string]string)
+		}
+		if k.declarations == nil {
+			k.declarations = newPDDeclarations()
+		}
+		return handleWorkload(workloadKinds[key], data, k.owners, k.declarations), nil
 	default:
 		return nil, fmt.Errorf("unknown key for OpenStack: %s", key)
 	}
@@ -33,29 +64,41 @@ func handleNode(data []interface{}) []InfrastructureComponent {
 			fmt.Printf("Expected type v1.Node, but got: %T\n", item)
 			continue
 		}
+		// The instance a node runs on is resolved across providers, see ResolveIdentities
+		hostname := node.Name
+		var addresses []string
+		for _, address := range node.Status.Addresses {
+			switch address.Type {
+			case corev1.NodeHostName:
+				hostname = address.Address
+			case corev1.NodeInternalIP, corev1.NodeExternalIP:
+				addresses = append(addresses, address.Address)
+			}
+		}
 		component := InfrastructureComponent{
 			ID:   string(node.UID),
 			Name: node.Name,
 			Type: "ClusterNode",
 			Metadata: map[string]interface{}{
-				"CreatedAt": node.CreationTimestamp.Format(time.RFC3339),
-			},
-			Relationships: []Relationship{
-				{
-					Type:   "PROVISIONED_BY",
-					Target: node.Status.NodeInfo.SystemUUID,
-				},
+				"CreatedAt":   node.CreationTimestamp.Format(time.RFC3339),
+				"ProviderID":  node.Spec.ProviderID,
+				"SystemUUID":  node.Status.NodeInfo.SystemUUID,
+				"Hostname":    hostname,
+				"IPAddresses": addresses,
 			},
 		}
-		//logger.Debug(logger.LogFields{"node.Status.NodeInfo.SystemUUID": node.Status.NodeInfo.SystemUUID})
 		components = append(components, component)
 	}
 	return components
 }
 
-func handlePod(data []interface{}, pvcToPVMap map[string]string) []InfrastructureComponent {
+func handlePod(data []interface{}, pvcToPVMap map[string]string, owners map[string]string, svcs *services, claims map[string]bool, decl *pdDeclarations) []InfrastructureComponent {
 	var components []InfrastructureComponent
 	seenPVCs := make(map[string]bool) // track the PVCs we've already created
+	if owners == nil {
+		// No workloads were scanned, pods are managed by their direct controller
+		owners = make(map[string]string)
+	}
 	for _, item := range data {
 		pod, ok := item.(corev1.Pod)
 		if !ok {
@@ -72,18 +115,27 @@ func handlePod(data []interface{}, pvcToPVMap map[string]string) []Infrastructur
 			Target: pod.Spec.NodeName,
 		})
 
+		// Relationships of the Pod to its owners and its top-level controller
+		podRelationships = append(podRelationships, ownerRelationships(pod.ObjectMeta, owners)...)
+
+		// Relationships of the Pod to its namespace and the services selecting it
+		podRelationships = append(podRelationships, Relationship{Type: "IN_NAMESPACE", Target: pod.Namespace})
+		podRelationships = append(podRelationships, serviceRelationships(pod, svcs)...)
+
 		// Process volumes and establish relationships to PVCs
 		for _, volume := range pod.Spec.Volumes {
 			volumeNames = append(volumeNames, volume.Name)
 
 			if volume.PersistentVolumeClaim != nil {
 				pvcName := volume.PersistentVolumeClaim.ClaimName
-				pvName, exists := pvcToPVMap[pvcName]
+				// PVC names are only unique within a namespace
+				pvcID := namespacedKey(pod.Namespace, pvcName)
+				pvName, exists := pvcToPVMap[pvcID]
 
-				// Create PVC entity only if it hasn't been created before
-				if exists && !seenPVCs[pvcName] {
+				// Create PVC entity only if it hasn't been created before or listed
+				if exists && !seenPVCs[pvcID] && !claims[pvcID] {
 					pvcComponent := InfrastructureComponent{
-						ID:   pvcName,
+						ID:   pvcID,
 						Name: pvcName,
 						Type: "PersistentVolumeClaim",
 						Relationships: []Relationship{
@@ -95,13 +147,13 @@ func handlePod(data []interface{}, pvcToPVMap map[string]string) []Infrastructur
 					}
 					//logger.Debug(logger.LogFields{"pvcComponent": pvcComponent})
 					components = append(components, pvcComponent)
-					seenPVCs[pvcName] = true
+					seenPVCs[pvcID] = true
 				}
 
 				// Relationship of the Pod to the PVC
 				podRelationships = append(podRelationships, Relationship{
 					Type:   "USES_PVC",
-					Target: pvcName,
+					Target: pvcID,
 				})
 				//logger.Debug(logger.LogFields{"pvcName": pvcName})
 			}
@@ -111,49 +163,29 @@ func handlePod(data []interface{}, pvcToPVMap map[string]string) []Infrastructur
 			ID:            string(pod.UID),
 			Name:          pod.Name,
 			Type:          "Pod",
-			Metadata:      map[string]interface{}{"CreatedAt": pod.CreationTimestamp.Format(time.RFC3339), "Volumes": volumeNames},
+			Metadata:      map[string]interface{}{"CreatedAt": pod.CreationTimestamp.Format(time.RFC3339), "Volumes": volumeNames, "Namespace": pod.Namespace},
 			Relationships: podRelationships,
 		}
-		// If pod is attached with pd_annotations create transparency pod
-		pdAnnotation, hasPD := pod.Annotations["has_pd"]
-		// logger.Debug(logger.LogFields{"pdComponent ID": pdAnnotation})
-		if hasPD {
-			// Parse or validate JSON if necessary
-			var pdData interface{}
-			err := json.Unmarshal([]byte(pdAnnotation), &pdData)
-			if err != nil {
-				// Log or handle error
-				logger.Error("Invalid JSON in pdAnnotation", err)
-				continue // Skip this iteration
-			}
-			pdJSON, err := json.Marshal(pdData)
-			if err != nil {
-				// Log or handle error
-				logger.Error("Error marshalling JSON for pdAnnotation", err)
-				continue // Skip this iteration
-			}
-
-			pdComponent := InfrastructureComponent{
-				ID:       "pd_indicator_" + string(pod.UID),
-				Name:     "pd_indicator_" + pod.Name,
-				Type:     "PDIndicator",
-				Metadata: map[string]interface{}{"has_pd": string(pdJSON)},
-			}
-
-			components = append(components, pdComponent)
-			// Create a relationship from pd_indicator to the Pod
-			podComponent.Relationships = append(podComponent.Relationships, Relationship{
-				Type:   "HAS_PD",
-				Target: "pd_indicator_" + string(pod.UID),
-			})
+		// Pods deleted between two scans are still reported by the watch plugin
+		if pod.DeletionTimestamp != nil {
+			podComponent.Metadata["DeletedAt"] = pod.DeletionTimestamp.Format(time.RFC3339)
+		}
+		// Pods declaring personal data get a PDIndicator, broken declarations a Finding too
+		if pdAnnotation, hasPD := pod.Annotations["has_pd"]; hasPD {
+			pd, pdRelationships := pdComponents(string(pod.UID), pod.Name, "Pod", pdAnnotation)
+			components = append(components, pd...)
+			podComponent.Relationships = append(podComponent.Relationships, pdRelationships...)
 		}
+		// and inherit the declarations of their namespace and controllers
+		podComponent.Relationships = append(podComponent.Relationships, decl.inherited(pod, owners)...)
 		components = append(components, podComponent)
 	}
 	return components
 }
 
-func handlePV(data []interface{}) []InfrastructureComponent {
+func handlePV(data []interface{}, decl *pdDeclarations) []InfrastructureComponent {
 	var components []InfrastructureComponent
+	seenStorage := make(map[string]bool) // NFS shares and CSI volumes may back several PVs
 	for _, item := range data {
 		pv, ok := item.(corev1.PersistentVolume)
 		if !ok {
@@ -161,7 +193,20 @@ func handlePV(data []interface{}) []InfrastructureComponent {
 			continue
 		}
 
-		cinderVolumeID := pv.Spec.PersistentVolumeSource.Cinder.VolumeID
+		source, volumeID, storage := pvStorage(pv)
+		var relationships []Relationship
+		switch {
+		case volumeID != "":
+			relationships = append(relationships, Relationship{Type: "STORED_ON", Target: volumeID})
+		case storage != nil:
+			if !seenStorage[storage.ID] {
+				components = append(components, *storage)
+				seenStorage[storage.ID] = true
+			}
+			relationships = append(relationships, Relationship{Type: "BACKED_BY", Target: storage.ID})
+		default:
+			logger.Warning("Storage of PersistentVolume not resolved", logger.LogFields{"pv": pv.Name})
+		}
 
 		component := InfrastructureComponent{
 			ID:   string(pv.UID),
@@ -169,16 +214,43 @@ func handlePV(data []interface{}) []InfrastructureComponent {
 			Type: "PersistentVolume",
 			Metadata: map[string]interface{}{
 				"CreatedAt": pv.CreationTimestamp.Format(time.RFC3339),
-				// Add any other relevant metadata here
+				"Source":    source,
+			},
+			Relationships: append(relationships, decl.claimed(pv)...),
+		}
+		//logger.Debug(logger.LogFields{"PV": pv})
+		components = append(components, component)
+	}
+	return components
+}
+
+func handleEvent(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		event, ok := item.(plugin.ResourceEvent)
+		if !ok {
+			fmt.Printf("Expected type plugin.ResourceEvent, but got: %T\n", item)
+			continue
+		}
+		observedAt := event.ObservedAt.Format(time.RFC3339Nano)
+		component := InfrastructureComponent{
+			ID:   fmt.Sprintf("event_%s_%s_%s", event.UID, event.Action, observedAt),
+			Name: fmt.Sprintf("%s %s %s", event.Action, event.Kind, event.Name),
+			Type: "ResourceEvent",
+			Metadata: map[string]interface{}{
+				"Kind":       event.Kind,
+				"Action":     event.Action,
+				"ObjectName": event.Name,
+				"Namespace":  event.Namespace,
+				"ObservedAt": observedAt,
 			},
 			Relationships: []Relationship{
 				{
-					Type:   "STORED_ON",
-					Target: cinderVolumeID,
+					Type:   "CHANGED",
+					Target: event.UID,
 				},
 			},
 		}
-		//logger.Debug(logger.LogFields{"PV": pv})
 		components = append(components, component)
 	}
 	return components
@@ -188,10 +260,16 @@ func createPVCToPVMapFromRawData(data []interface{}) map[string]string {
 	pvcToPV := make(map[string]string)
 	for _, item := range data {
 		if pv, ok := item.(corev1.PersistentVolume); ok && pv.Spec.ClaimRef != nil {
-			pvcToPV[pv.Spec.ClaimRef.Name] = pv.Name
+			pvcToPV[namespacedKey(pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)] = pv.Name
 
 		}
 
 	}
 	return pvcToPV
 }
+
+// namespacedKey identifies a namespaced object of a kind, like a PVC or a
+// service, within a cluster
+func namespacedKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
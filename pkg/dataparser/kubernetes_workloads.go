@@ -0,0 +1,120 @@
+package dataparser
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Raw data keys of workload controllers and the node type they become
+var workloadKinds = map[string]string{
+	"k8s_deployment":  "Deployment",
+	"k8s_statefulset": "StatefulSet",
+	"k8s_daemonset":   "DaemonSet",
+	"k8s_replicaset":  "ReplicaSet",
+	"k8s_job":         "Job",
+	"k8s_cronjob":     "CronJob",
+}
+
+// handleWorkload transforms workload controllers and records their owners and
+// has_pd declarations, so pods transformed afterwards can be linked to the
+// controller at the top of their owner chain, e.g. the Deployment instead of
+// its current ReplicaSet, and inherit the declarations along it.
+func handleWorkload(kind string, data []interface{}, owners map[string]string, decl *pdDeclarations) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		meta, spec, ok := workloadObject(item)
+		if !ok {
+			fmt.Printf("Expected a %s, but got: %T\n", kind, item)
+			continue
+		}
+		if controller := metav1.GetControllerOfNoCopy(&meta); controller != nil {
+			owners[string(meta.UID)] = string(controller.UID)
+		}
+
+		metadata := map[string]interface{}{
+			"Kind":      kind,
+			"Namespace": meta.Namespace,
+			"CreatedAt": meta.CreationTimestamp.Format(time.RFC3339),
+		}
+		for k, v := range spec {
+			metadata[k] = v
+		}
+		component := InfrastructureComponent{
+			ID:            string(meta.UID),
+			Name:          meta.Name,
+			Type:          kind,
+			Metadata:      metadata,
+			Relationships: ownerRelationships(meta, nil),
+		}
+		// Declarations of a controller cover the pods in its owner chain
+		components = append(components, declarePD(decl.workloads, string(meta.UID), meta, string(meta.UID), kind, &component)...)
+		components = append(components, component)
+	}
+	return components
+}
+
+// workloadObject returns the object meta of a workload controller and the spec
+// fields worth keeping, like the desired replicas or the schedule of a CronJob.
+func workloadObject(item interface{}) (metav1.ObjectMeta, map[string]interface{}, bool) {
+	switch w := item.(type) {
+	case appsv1.Deployment:
+		return w.ObjectMeta, map[string]interface{}{"Replicas": replicas(w.Spec.Replicas)}, true
+	case appsv1.StatefulSet:
+		return w.ObjectMeta, map[string]interface{}{"Replicas": replicas(w.Spec.Replicas)}, true
+	case appsv1.ReplicaSet:
+		return w.ObjectMeta, map[string]interface{}{"Replicas": replicas(w.Spec.Replicas)}, true
+	case appsv1.DaemonSet:
+		return w.ObjectMeta, nil, true
+	case batchv1.Job:
+		return w.ObjectMeta, nil, true
+	case batchv1.CronJob:
+		return w.ObjectMeta, map[string]interface{}{"Schedule": w.Spec.Schedule}, true
+	}
+	return metav1.ObjectMeta{}, nil, false
+}
+
+// ownerRelationships links an object to its owners. Given the owners of the
+// workloads, it also links it to the controller at the top of its owner chain.
+func ownerRelationships(meta metav1.ObjectMeta, owners map[string]string) []Relationship {
+	var relationships []Relationship
+	for _, ref := range meta.OwnerReferences {
+		relationships = append(relationships, Relationship{Type: "OWNED_BY", Target: string(ref.UID)})
+	}
+	if owners == nil {
+		return relationships
+	}
+	if chain := ownerChain(meta, owners); len(chain) > 0 {
+		relationships = append(relationships, Relationship{Type: "MANAGED_BY", Target: chain[len(chain)-1]})
+	}
+	return relationships
+}
+
+// ownerChain returns the UIDs of the controller of an object and of its
+// controllers in turn, ending with the controller at the top.
+func ownerChain(meta metav1.ObjectMeta, owners map[string]string) []string {
+	controller := metav1.GetControllerOfNoCopy(&meta)
+	if controller == nil {
+		return nil
+	}
+	chain := []string{string(controller.UID)}
+	// Bounded walk, owner references can't form cycles but the data is untrusted
+	for i := 0; i < 10; i++ {
+		owner, exists := owners[chain[len(chain)-1]]
+		if !exists {
+			break
+		}
+		chain = append(chain, owner)
+	}
+	return chain
+}
+
+func replicas(r *int32) int {
+	if r == nil {
+		return 1
+	}
+	return int(*r)
+}
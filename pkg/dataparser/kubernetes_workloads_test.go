@@ -0,0 +1,166 @@
+package dataparser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	shared "github.com/regulatory-transparency-monitor/commons/models"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// object returns the meta of an object named after its UID, controlled by the
+// first of owners
+func object(uid string, owners ...string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Namespace: "shop", Name: uid, UID: types.UID(uid)}
+	for i, owner := range owners {
+		controller := i == 0
+		meta.OwnerReferences = append(meta.OwnerReferences, metav1.OwnerReference{UID: types.UID(owner), Controller: &controller})
+	}
+	return meta
+}
+
+// transformK8s transforms the raw data of a cluster the way a scan does
+func transformK8s(t *testing.T, raw shared.RawData) []InfrastructureComponent {
+	t.Helper()
+	components, _, err := TransformData("k8s", raw, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return components
+}
+
+// component returns the component with id
+func component(t *testing.T, components []InfrastructureComponent, id string) InfrastructureComponent {
+	t.Helper()
+	for _, c := range components {
+		if c.ID == id {
+			return c
+		}
+	}
+	t.Fatalf("component %s wasn't transformed", id)
+	return InfrastructureComponent{}
+}
+
+// targets returns the sorted targets of the relationships of c of a type
+func targets(c InfrastructureComponent, relationshipType string) []string {
+	var got []string
+	for _, r := range c.Relationships {
+		if r.Type == relationshipType {
+			got = append(got, r.Target)
+		}
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestWorkloadOwnerChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       shared.RawData
+		ownedBy   []string
+		managedBy []string
+	}{
+		{
+			name: "deployment",
+			raw: shared.RawData{
+				"k8s_deployment": {appsv1.Deployment{ObjectMeta: object("deploy")}},
+				"k8s_replicaset": {appsv1.ReplicaSet{ObjectMeta: object("rs", "deploy")}},
+				"k8s_pod":        {corev1.Pod{ObjectMeta: object("pod", "rs")}},
+			},
+			ownedBy:   []string{"rs"},
+			managedBy: []string{"deploy"},
+		},
+		{
+			name: "cronjob",
+			raw: shared.RawData{
+				"k8s_cronjob": {batchv1.CronJob{ObjectMeta: object("cron")}},
+				"k8s_job":     {batchv1.Job{ObjectMeta: object("job", "cron")}},
+				"k8s_pod":     {corev1.Pod{ObjectMeta: object("pod", "job")}},
+			},
+			ownedBy:   []string{"job"},
+			managedBy: []string{"cron"},
+		},
+		{
+			name: "controller not scanned",
+			raw: shared.RawData{
+				"k8s_pod": {corev1.Pod{ObjectMeta: object("pod", "rs")}},
+			},
+			ownedBy:   []string{"rs"},
+			managedBy: []string{"rs"},
+		},
+		{
+			name: "owner that isn't the controller",
+			raw: shared.RawData{
+				"k8s_deployment": {appsv1.Deployment{ObjectMeta: object("deploy")}},
+				"k8s_replicaset": {appsv1.ReplicaSet{ObjectMeta: object("rs", "deploy")}},
+				"k8s_pod":        {corev1.Pod{ObjectMeta: object("pod", "rs", "other")}},
+			},
+			ownedBy:   []string{"other", "rs"},
+			managedBy: []string{"deploy"},
+		},
+		{
+			name: "orphan",
+			raw: shared.RawData{
+				"k8s_deployment": {appsv1.Deployment{ObjectMeta: object("deploy")}},
+				"k8s_pod":        {corev1.Pod{ObjectMeta: object("pod")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := component(t, transformK8s(t, tt.raw), "pod")
+			if got := targets(pod, "OWNED_BY"); !reflect.DeepEqual(got, tt.ownedBy) {
+				t.Errorf("OWNED_BY = %v, want %v", got, tt.ownedBy)
+			}
+			if got := targets(pod, "MANAGED_BY"); !reflect.DeepEqual(got, tt.managedBy) {
+				t.Errorf("MANAGED_BY = %v, want %v", got, tt.managedBy)
+			}
+		})
+	}
+}
+
+// Controllers are linked to their owners but only pods to the top controller
+func TestWorkloadRelationships(t *testing.T) {
+	components := transformK8s(t, shared.RawData{
+		"k8s_deployment": {appsv1.Deployment{ObjectMeta: object("deploy")}},
+		"k8s_replicaset": {appsv1.ReplicaSet{ObjectMeta: object("rs", "deploy")}},
+	})
+	rs := component(t, components, "rs")
+	if got := targets(rs, "OWNED_BY"); !reflect.DeepEqual(got, []string{"deploy"}) {
+		t.Errorf("OWNED_BY = %v, want [deploy]", got)
+	}
+	if got := targets(rs, "MANAGED_BY"); got != nil {
+		t.Errorf("MANAGED_BY = %v, want none", got)
+	}
+	if rs.Type != "ReplicaSet" || rs.Metadata["Kind"] != "ReplicaSet" {
+		t.Errorf("type = %s, kind = %v, want ReplicaSet", rs.Type, rs.Metadata["Kind"])
+	}
+}
+
+func TestWorkloadReplicas(t *testing.T) {
+	three := int32(3)
+	zero := int32(0)
+	tests := []struct {
+		name     string
+		replicas *int32
+		want     int
+	}{
+		{name: "unset", replicas: nil, want: 1},
+		{name: "scaled", replicas: &three, want: 3},
+		{name: "scaled down", replicas: &zero, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := appsv1.Deployment{ObjectMeta: object("deploy"), Spec: appsv1.DeploymentSpec{Replicas: tt.replicas}}
+			c := component(t, transformK8s(t, shared.RawData{"k8s_deployment": {deployment}}), "deploy")
+			if got := c.Metadata["Replicas"]; got != tt.want {
+				t.Errorf("Replicas = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package dataparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping declares how the items of a raw data key become components. All
+// fields but Key and Type are path expressions into an item, like
+// "server.id", "addresses[0].ip" or "disks[*].source". Items are addressed by
+// their JSON field names.
+type Mapping struct {
+	Key              string                `yaml:"key"`
+	Type             string                `yaml:"type"`
+	ID               string                `yaml:"id"`
+	Name             string                `yaml:"name"`
+	AvailabilityZone string                `yaml:"availabilityZone"`
+	Metadata         map[string]string     `yaml:"metadata"`
+	Relationships    []RelationshipMapping `yaml:"relationships"`
+}
+
+// RelationshipMapping creates a relationship of Type to every value Target resolves to.
+type RelationshipMapping struct {
+	Type   string `yaml:"type"`
+	Target string `yaml:"target"`
+}
+
+// MappingTransformer transforms a single raw data key as declared by its Mapping.
+type MappingTransformer struct {
+	Mapping Mapping
+
+	id, name, zone path
+	metadata       map[string]path
+	relationships  []path
+}
+
+// Registry of mapping transformers by raw data key, they take precedence over
+// the transformers registered by prefix.
+var MappingRegistry = make(map[string]*MappingTransformer)
+
+// LoadMappings registers the mappings of all *.yaml files in dir. A missing
+// directory is not an error, invalid files and files mapping a key already
+// mapped by an earlier file are skipped and reported.
+func LoadMappings(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	var invalid []string
+	loaded := make(map[string]string) // file by key
+	for _, file := range files {
+		m, err := readMapping(file)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if earlier, ok := loaded[m.Mapping.Key]; ok {
+			invalid = append(invalid, fmt.Sprintf("%s: key %s is already mapped by %s", file, m.Mapping.Key, earlier))
+			continue
+		}
+		loaded[m.Mapping.Key] = file
+		MappingRegistry[m.Mapping.Key] = m
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid mappings: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+func readMapping(file string) (*MappingTransformer, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var m Mapping
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return NewMappingTransformer(m)
+}
+
+// NewMappingTransformer validates a mapping and compiles its path expressions.
+func NewMappingTransformer(m Mapping) (*MappingTransformer, error) {
+	if m.Key == "" || m.Type == "" || m.ID == "" {
+		return nil, fmt.Errorf("key, type and id are required")
+	}
+	t := &MappingTransformer{Mapping: m, metadata: make(map[string]path)}
+
+	var err error
+	if t.id, err = parsePath(m.ID); err != nil {
+		return nil, err
+	}
+	if t.name, err = parsePath(m.Name); err != nil {
+		return nil, err
+	}
+	if t.zone, err = parsePath(m.AvailabilityZone); err != nil {
+		return nil, err
+	}
+	for field, expr := range m.Metadata {
+		if t.metadata[field], err = parsePath(expr); err != nil {
+			return nil, err
+		}
+	}
+	for _, rel := range m.Relationships {
+		if rel.Type == "" {
+			return nil, fmt.Errorf("relationship type is required")
+		}
+		p, err := parsePath(rel.Target)
+		if err != nil {
+			return nil, err
+		}
+		t.relationships = append(t.relationships, p)
+	}
+	return t, nil
+}
+
+func (t *MappingTransformer) Transform(key string, data []interface{}) ([]InfrastructureComponent, error) {
+	if key != t.Mapping.Key {
+		return nil, fmt.Errorf("mapping for %s can't transform %s", t.Mapping.Key, key)
+	}
+
+	var components []InfrastructureComponent
+	for _, item := range data {
+		doc, err := toDocument(item)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s item: %v", key, err)
+		}
+		id := t.id.first(doc)
+		if id == "" {
+			fmt.Printf("%s item without %s, skipping\n", key, t.Mapping.ID)
+			continue
+		}
+
+		component := InfrastructureComponent{
+			ID:               id,
+			Name:             t.name.first(doc),
+			Type:             t.Mapping.Type,
+			AvailabilityZone: t.zone.first(doc),
+			Metadata:         make(map[string]interface{}),
+		}
+		for field, p := range t.metadata {
+			values := p.eval(doc)
+			switch {
+			case p.multiple():
+				if values != nil {
+					component.Metadata[field] = values
+				}
+			case len(values) > 0:
+				component.Metadata[field] = values[0]
+			}
+		}
+		for i, p := range t.relationships {
+			for _, target := range p.eval(doc) {
+				if s := toString(target); s != "" {
+					component.Relationships = append(component.Relationships, Relationship{Type: t.Mapping.Relationships[i].Type, Target: s})
+				}
+			}
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+// toDocument converts a typed item into the generic form of its JSON encoding.
+func toDocument(item interface{}) (interface{}, error) {
+	if doc, ok := item.(map[string]interface{}); ok {
+		return doc, nil
+	}
+	b, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	err = json.Unmarshal(b, &doc)
+	return doc, err
+}
+
+// path is a compiled path expression, an empty path resolves to nothing.
+type path []pathSegment
+
+type pathSegment struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// parsePath compiles expressions like "$.a.b[0].c" or "a[*].b", the leading "$." is optional.
+func parsePath(expr string) (path, error) {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	if expr == "" {
+		return nil, nil
+	}
+	var p path
+	for _, part := range strings.Split(expr, ".") {
+		field := part
+		brackets := ""
+		if i := strings.Index(part, "["); i >= 0 {
+			field, brackets = part[:i], part[i:]
+		}
+		if strings.Contains(field, "]") || (field == "" && brackets == "") {
+			return nil, fmt.Errorf("invalid path %q", expr)
+		}
+		if field != "" {
+			p = append(p, pathSegment{field: field})
+		}
+		for brackets != "" {
+			end := strings.Index(brackets, "]")
+			if !strings.HasPrefix(brackets, "[") || end < 0 {
+				return nil, fmt.Errorf("invalid path %q", expr)
+			}
+			inner := brackets[1:end]
+			brackets = brackets[end+1:]
+			if inner == "*" {
+				p = append(p, pathSegment{wildcard: true})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in path %q", inner, expr)
+			}
+			p = append(p, pathSegment{index: index, isIndex: true})
+		}
+	}
+	return p, nil
+}
+
+// multiple tells whether the path may resolve to several values, they are
+// kept as list even if there is only one
+func (p path) multiple() bool {
+	for _, seg := range p {
+		if seg.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// eval returns every value the path resolves to in doc, missing fields resolve to nothing.
+func (p path) eval(doc interface{}) []interface{} {
+	if len(p) == 0 {
+		return nil
+	}
+	values := []interface{}{doc}
+	for _, seg := range p {
+		var next []interface{}
+		for _, v := range values {
+			switch {
+			case seg.wildcard:
+				if list, ok := v.([]interface{}); ok {
+					next = append(next, list...)
+				}
+			case seg.isIndex:
+				if list, ok := v.([]interface{}); ok && seg.index >= 0 && seg.index < len(list) {
+					next = append(next, list[seg.index])
+				}
+			default:
+				if m, ok := v.(map[string]interface{}); ok {
+					if field, exists := m[seg.field]; exists && field != nil {
+						next = append(next, field)
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// first returns the first value of the path as string.
+func (p path) first(doc interface{}) string {
+	values := p.eval(doc)
+	if len(values) == 0 {
+		return ""
+	}
+	return toString(values[0])
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(s)
+	}
+}
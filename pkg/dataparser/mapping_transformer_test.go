@@ -0,0 +1,158 @@
+package dataparser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    path
+		wantErr bool
+	}{
+		{expr: "", want: nil},
+		{expr: "$", want: nil},
+		{expr: "$.a.b", want: path{{field: "a"}, {field: "b"}}},
+		{expr: "a.b", want: path{{field: "a"}, {field: "b"}}},
+		{expr: "a[0].c", want: path{{field: "a"}, {index: 0, isIndex: true}, {field: "c"}}},
+		{expr: "a[*].c", want: path{{field: "a"}, {wildcard: true}, {field: "c"}}},
+		{expr: "a[1][*]", want: path{{field: "a"}, {index: 1, isIndex: true}, {wildcard: true}}},
+		{expr: "[0]", want: path{{index: 0, isIndex: true}}},
+		{expr: "a[", wantErr: true},
+		{expr: "a[0", wantErr: true},
+		{expr: "a]", wantErr: true},
+		{expr: "a[0]b", wantErr: true},
+		{expr: "a[]", wantErr: true},
+		{expr: "a[x]", wantErr: true},
+		{expr: "a[-1]", wantErr: true},
+		{expr: "a..b", wantErr: true},
+		{expr: "a.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parsePath(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePath(%q) = %v, want an error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathEval(t *testing.T) {
+	doc := map[string]interface{}{
+		"name": "web",
+		"none": nil,
+		"disks": []interface{}{
+			map[string]interface{}{"id": "d1"},
+			map[string]interface{}{"id": "d2"},
+			map[string]interface{}{"size": 10.0},
+		},
+		"tags": []interface{}{"a", "b"},
+	}
+	tests := []struct {
+		expr string
+		want []interface{}
+	}{
+		{expr: "", want: nil},
+		{expr: "name", want: []interface{}{"web"}},
+		{expr: "missing", want: nil},
+		{expr: "none", want: nil},
+		{expr: "name.first", want: nil},
+		{expr: "tags[0]", want: []interface{}{"a"}},
+		{expr: "tags[1]", want: []interface{}{"b"}},
+		{expr: "tags[2]", want: nil},
+		{expr: "name[0]", want: nil},
+		{expr: "tags[*]", want: []interface{}{"a", "b"}},
+		{expr: "disks[*].id", want: []interface{}{"d1", "d2"}},
+		{expr: "disks[5].id", want: nil},
+		{expr: "name[*]", want: nil},
+		{expr: "disks[*].missing", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := parsePath(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.eval(doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingTransformerMetadata(t *testing.T) {
+	m, err := NewMappingTransformer(Mapping{
+		Key:  "inhouse_server",
+		Type: "Instance",
+		ID:   "$.uuid",
+		Metadata: map[string]string{
+			"Status": "$.state",
+			"Disks":  "$.disks[*].volume_id",
+			"Tags":   "$.tags[*]",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []interface{}{
+		map[string]interface{}{
+			"uuid":  "s1",
+			"state": "ACTIVE",
+			"disks": []interface{}{map[string]interface{}{"volume_id": "v1"}, map[string]interface{}{"volume_id": "v2"}},
+		},
+		map[string]interface{}{
+			"uuid":  "s2",
+			"disks": []interface{}{map[string]interface{}{"volume_id": "v3"}},
+		},
+	}
+	components, err := m.Transform("inhouse_server", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]interface{}{
+		{"Status": "ACTIVE", "Disks": []interface{}{"v1", "v2"}},
+		// A wildcard keeps its list type with a single value and leaves out no values
+		{"Disks": []interface{}{"v3"}},
+	}
+	for i, c := range components {
+		if !reflect.DeepEqual(c.Metadata, want[i]) {
+			t.Errorf("%s metadata = %v, want %v", c.ID, c.Metadata, want[i])
+		}
+	}
+}
+
+func TestLoadMappingsDuplicateKey(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": "key: dup_key\ntype: Instance\nid: $.id\nname: $.first\n",
+		"b.yaml": "key: dup_key\ntype: Instance\nid: $.id\nname: $.second\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { delete(MappingRegistry, "dup_key") })
+
+	err := LoadMappings(dir)
+	if err == nil || !strings.Contains(err.Error(), "b.yaml: key dup_key is already mapped by") {
+		t.Errorf("LoadMappings = %v, want the duplicate key reported", err)
+	}
+	if m := MappingRegistry["dup_key"]; m == nil || m.Mapping.Name != "$.first" {
+		t.Errorf("the mapping of the earlier file was replaced: %+v", m)
+	}
+}
@@ -0,0 +1,317 @@
+package dataparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+)
+
+// networking holds what ports and floating IPs tell about the devices they
+// belong to, until instances and routers are transformed.
+type networking struct {
+	portDevice map[string]string
+	devices    map[string]*deviceNetworking
+}
+
+// deviceNetworking is the networking of an instance or a router.
+type deviceNetworking struct {
+	Ports          []string
+	Networks       []string
+	FloatingIPs    []string
+	SecurityGroups []string
+	IPAddresses    []string
+}
+
+func newNetworking() *networking {
+	return &networking{portDevice: make(map[string]string), devices: make(map[string]*deviceNetworking)}
+}
+
+func (n *networking) device(id string) *deviceNetworking {
+	d, exists := n.devices[id]
+	if !exists {
+		d = &deviceNetworking{}
+		n.devices[id] = d
+	}
+	return d
+}
+
+// ipAddresses are the fixed and floating addresses of an instance.
+func (n *networking) ipAddresses(instanceID string) []string {
+	if d, exists := n.devices[instanceID]; exists {
+		return d.IPAddresses
+	}
+	return nil
+}
+
+// relationships links an instance to its ports, networks, floating IPs and security groups.
+func (n *networking) relationships(instanceID string) []Relationship {
+	d, exists := n.devices[instanceID]
+	if !exists {
+		return nil
+	}
+	var relationships []Relationship
+	for _, id := range d.Ports {
+		relationships = append(relationships, Relationship{Type: "HAS_PORT", Target: id})
+	}
+	for _, id := range d.Networks {
+		relationships = append(relationships, Relationship{Type: "CONNECTED_TO", Target: id})
+	}
+	for _, id := range d.FloatingIPs {
+		relationships = append(relationships, Relationship{Type: "HAS_FLOATING_IP", Target: id})
+	}
+	for _, id := range d.SecurityGroups {
+		relationships = append(relationships, Relationship{Type: "PROTECTED_BY", Target: id})
+	}
+	return relationships
+}
+
+func handleNetwork(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		network, ok := item.(plugin.NeutronNetwork)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronNetwork, but got: %T\n", item)
+			continue
+		}
+		components = append(components, InfrastructureComponent{
+			ID:   network.ID,
+			Name: network.Name,
+			Type: "Network",
+			Metadata: map[string]interface{}{
+				"Status":   network.Status,
+				"Shared":   network.Shared,
+				"External": network.External,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "BELONGS_TO",
+					Target: network.ProjectID,
+				},
+			},
+		})
+	}
+	return components
+}
+
+func handleSubnet(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		subnet, ok := item.(plugin.NeutronSubnet)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronSubnet, but got: %T\n", item)
+			continue
+		}
+		components = append(components, InfrastructureComponent{
+			ID:   subnet.ID,
+			Name: subnet.Name,
+			Type: "Subnet",
+			Metadata: map[string]interface{}{
+				"CIDR":      subnet.CIDR,
+				"IPVersion": subnet.IPVersion,
+				"GatewayIP": subnet.GatewayIP,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "PART_OF",
+					Target: subnet.NetworkID,
+				},
+			},
+		})
+	}
+	return components
+}
+
+// handlePort transforms ports and records them for the instances and routers they belong to.
+func handlePort(data []interface{}, net *networking) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		port, ok := item.(plugin.NeutronPort)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronPort, but got: %T\n", item)
+			continue
+		}
+
+		relationships := []Relationship{{Type: "ON_NETWORK", Target: port.NetworkID}}
+		var addresses []string
+		for _, ip := range port.FixedIPs {
+			addresses = append(addresses, ip.IPAddress)
+			relationships = append(relationships, Relationship{Type: "IN_SUBNET", Target: ip.SubnetID})
+		}
+		for _, sg := range port.SecurityGroups {
+			relationships = append(relationships, Relationship{Type: "SECURED_BY", Target: sg})
+		}
+
+		// Instance ports are owned by compute:<zone>, router ports by network:router_*
+		if port.DeviceID != "" && (strings.HasPrefix(port.DeviceOwner, "compute:") || strings.HasPrefix(port.DeviceOwner, "network:router_interface")) {
+			net.portDevice[port.ID] = port.DeviceID
+			d := net.device(port.DeviceID)
+			d.Ports = appendUnique(d.Ports, port.ID)
+			d.Networks = appendUnique(d.Networks, port.NetworkID)
+			for _, sg := range port.SecurityGroups {
+				d.SecurityGroups = appendUnique(d.SecurityGroups, sg)
+			}
+			for _, ip := range port.FixedIPs {
+				d.IPAddresses = appendUnique(d.IPAddresses, ip.IPAddress)
+			}
+		}
+
+		name := port.Name
+		if name == "" {
+			name = port.ID
+		}
+		components = append(components, InfrastructureComponent{
+			ID:   port.ID,
+			Name: name,
+			Type: "Port",
+			Metadata: map[string]interface{}{
+				"Status":      port.Status,
+				"MACAddress":  port.MACAddress,
+				"IPAddresses": addresses,
+				"DeviceID":    port.DeviceID,
+				"DeviceOwner": port.DeviceOwner,
+			},
+			Relationships: relationships,
+		})
+	}
+	return components
+}
+
+// handleFloatingIP transforms floating IPs and records them for the instance
+// behind their port, which makes that instance reachable from the external network.
+func handleFloatingIP(data []interface{}, net *networking) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		fip, ok := item.(plugin.NeutronFloatingIP)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronFloatingIP, but got: %T\n", item)
+			continue
+		}
+
+		relationships := []Relationship{{Type: "ON_NETWORK", Target: fip.FloatingNetworkID}}
+		if fip.PortID != "" {
+			relationships = append(relationships, Relationship{Type: "ASSOCIATED_WITH", Target: fip.PortID})
+			if device, exists := net.portDevice[fip.PortID]; exists {
+				d := net.device(device)
+				d.FloatingIPs = appendUnique(d.FloatingIPs, fip.ID)
+				d.IPAddresses = appendUnique(d.IPAddresses, fip.FloatingIPAddress)
+			}
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:   fip.ID,
+			Name: fip.FloatingIPAddress,
+			Type: "FloatingIP",
+			Metadata: map[string]interface{}{
+				"Status":         fip.Status,
+				"Address":        fip.FloatingIPAddress,
+				"FixedIPAddress": fip.FixedIPAddress,
+			},
+			Relationships: relationships,
+		})
+	}
+	return components
+}
+
+// handleRouter links routers to the networks of their interfaces and their external gateway.
+func handleRouter(data []interface{}, net *networking) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		router, ok := item.(plugin.NeutronRouter)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronRouter, but got: %T\n", item)
+			continue
+		}
+
+		var relationships []Relationship
+		if router.ExternalGatewayInfo != nil && router.ExternalGatewayInfo.NetworkID != "" {
+			relationships = append(relationships, Relationship{Type: "GATEWAY_TO", Target: router.ExternalGatewayInfo.NetworkID})
+		}
+		if d, exists := net.devices[router.ID]; exists {
+			for _, network := range d.Networks {
+				relationships = append(relationships, Relationship{Type: "CONNECTS", Target: network})
+			}
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:   router.ID,
+			Name: router.Name,
+			Type: "Router",
+			Metadata: map[string]interface{}{
+				"Status": router.Status,
+			},
+			Relationships: relationships,
+		})
+	}
+	return components
+}
+
+// handleSecurityGroup transforms security groups, a group is open to the
+// internet when one of its ingress rules allows any source address.
+func handleSecurityGroup(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		sg, ok := item.(plugin.NeutronSecurityGroup)
+		if !ok {
+			fmt.Printf("expected type plugin.NeutronSecurityGroup, but got: %T\n", item)
+			continue
+		}
+
+		var rules []string
+		open := false
+		for _, rule := range sg.Rules {
+			rules = append(rules, ruleString(rule))
+			anySource := rule.RemoteGroupID == "" && (rule.RemoteIPPrefix == "" || rule.RemoteIPPrefix == "0.0.0.0/0" || rule.RemoteIPPrefix == "::/0")
+			if rule.Direction == "ingress" && anySource {
+				open = true
+			}
+		}
+
+		components = append(components, InfrastructureComponent{
+			ID:   sg.ID,
+			Name: sg.Name,
+			Type: "SecurityGroup",
+			Metadata: map[string]interface{}{
+				"Description":    sg.Description,
+				"Rules":          rules,
+				"OpenToInternet": open,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "BELONGS_TO",
+					Target: sg.ProjectID,
+				},
+			},
+		})
+	}
+	return components
+}
+
+// ruleString formats a rule like "ingress IPv4 tcp 22-22 from 0.0.0.0/0".
+func ruleString(rule plugin.NeutronSecurityGroupRule) string {
+	protocol := rule.Protocol
+	if protocol == "" {
+		protocol = "any"
+	}
+	ports := "any"
+	if rule.PortRangeMin != nil && rule.PortRangeMax != nil {
+		ports = fmt.Sprintf("%d-%d", *rule.PortRangeMin, *rule.PortRangeMax)
+	}
+	remote := "any"
+	switch {
+	case rule.RemoteGroupID != "":
+		remote = "group " + rule.RemoteGroupID
+	case rule.RemoteIPPrefix != "":
+		remote = rule.RemoteIPPrefix
+	}
+	return fmt.Sprintf("%s %s %s %s from %s", rule.Direction, rule.EtherType, protocol, ports, remote)
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -2,7 +2,9 @@ package dataparser
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
 	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
 )
 
@@ -12,11 +14,38 @@ func (o *OpenStackTransformer) Transform(key string, data []interface{}) ([]Infr
 	case "os_project":
 		return handleProject(data), nil
 	case "os_instance":
-		return handleCompute(data), nil
+		return handleCompute(data, o.networking), nil
 	case "os_volume":
 		return handleVolume(data), nil
 	case "os_snapshot":
 		return handleSnapshots(data), nil
+	case "os_port":
+		if o.networking == nil {
+			o.networking = newNetworking()
+		}
+		return handlePort(data, o.networking), nil
+	case "os_floatingip":
+		if o.networking == nil {
+			o.networking = newNetworking()
+		}
+		return handleFloatingIP(data, o.networking), nil
+	case "os_router":
+		if o.networking == nil {
+			o.networking = newNetworking()
+		}
+		return handleRouter(data, o.networking), nil
+	case "os_network":
+		return handleNetwork(data), nil
+	case "os_subnet":
+		return handleSubnet(data), nil
+	case "os_security_group":
+		return handleSecurityGroup(data), nil
+	case "os_image":
+		return handleImage(data), nil
+	case "os_flavor":
+		return handleFlavor(data), nil
+	case "os_container":
+		return handleContainer(data), nil
 	default:
 		return nil, fmt.Errorf("unknown key for OpenStack: %s", key)
 	}
@@ -45,7 +74,7 @@ func handleProject(data []interface{}) []InfrastructureComponent {
 	return components
 }
 
-func handleCompute(data []interface{}) []InfrastructureComponent {
+func handleCompute(data []interface{}, net *networking) []InfrastructureComponent {
 	seenHosts := make(map[string]bool)
 	var components []InfrastructureComponent
 	for _, data := range data {
@@ -86,6 +115,28 @@ func handleCompute(data []interface{}) []InfrastructureComponent {
 				Target: volumeID, // point to the volumes
 			})
 		}
+		var addresses []string
+		for _, address := range instance.Addresses.DemoCluster {
+			addresses = appendUnique(addresses, address.Address)
+		}
+		for _, address := range []string{instance.AccessIPv4, instance.AccessIPv6} {
+			if address != "" {
+				addresses = appendUnique(addresses, address)
+			}
+		}
+		if net != nil {
+			relationships = append(relationships, net.relationships(instance.ID)...)
+			for _, address := range net.ipAddresses(instance.ID) {
+				addresses = appendUnique(addresses, address)
+			}
+		}
+		// Instances booted from a volume have no image
+		if instance.Image.ID != "" {
+			relationships = append(relationships, Relationship{Type: "BOOTED_FROM", Target: instance.Image.ID})
+		}
+		if instance.Flavor.ID != "" {
+			relationships = append(relationships, Relationship{Type: "HAS_FLAVOR", Target: instance.Flavor.ID})
+		}
 
 		component := InfrastructureComponent{
 			ID:               instance.ID,
@@ -100,6 +151,7 @@ func handleCompute(data []interface{}) []InfrastructureComponent {
 				"Created":         instance.Created,
 				"Updated":         instance.Updated,
 				"VolumesAttached": volumeIDs,
+				"IPAddresses":     addresses,
 			},
 			Relationships: relationships,
 		}
@@ -108,6 +160,122 @@ func handleCompute(data []interface{}) []InfrastructureComponent {
 	return components
 }
 
+func handleImage(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		image, ok := item.(plugin.GlanceImage)
+		if !ok {
+			fmt.Printf("expected type plugin.GlanceImage, but got: %T\n", item)
+			continue
+		}
+		components = append(components, InfrastructureComponent{
+			ID:   image.ID,
+			Name: image.Name,
+			Type: "Image",
+			Metadata: map[string]interface{}{
+				"Status":       image.Status,
+				"Visibility":   image.Visibility,
+				"Owner":        image.Owner,
+				"OSDistro":     image.OSDistro,
+				"OSVersion":    image.OSVersion,
+				"OSType":       image.OSType,
+				"Architecture": image.Architecture,
+				"Hidden":       image.Hidden,
+				"CreatedAt":    image.CreatedAt,
+				"UpdatedAt":    image.UpdatedAt,
+			},
+		})
+	}
+	return components
+}
+
+func handleFlavor(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		flavor, ok := item.(plugin.NovaFlavor)
+		if !ok {
+			fmt.Printf("expected type plugin.NovaFlavor, but got: %T\n", item)
+			continue
+		}
+		metadata := map[string]interface{}{
+			"VCPUs":     flavor.VCPUs,
+			"RAM":       flavor.RAM,
+			"Disk":      flavor.Disk,
+			"Ephemeral": flavor.Ephemeral,
+			"IsPublic":  flavor.IsPublic,
+		}
+		if len(flavor.ExtraSpecs) > 0 {
+			metadata["ExtraSpecs"] = flavor.ExtraSpecs
+		}
+		components = append(components, InfrastructureComponent{
+			ID:       flavor.ID,
+			Name:     flavor.Name,
+			Type:     "Flavor",
+			Metadata: metadata,
+		})
+	}
+	return components
+}
+
+// handleContainer transforms Swift containers. Containers holding personal
+// data are annotated like pods, with the has_pd JSON as container metadata.
+func handleContainer(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	for _, item := range data {
+		container, ok := item.(plugin.SwiftContainer)
+		if !ok {
+			fmt.Printf("expected type plugin.SwiftContainer, but got: %T\n", item)
+			continue
+		}
+		// Container names are only unique within an account
+		id := container.ProjectID + "/" + container.Name
+		publicRead, publicListing := publicACL(container.ReadACL)
+
+		component := InfrastructureComponent{
+			ID:   id,
+			Name: container.Name,
+			Type: "ObjectContainer",
+			Metadata: map[string]interface{}{
+				"Size":          container.Bytes,
+				"ObjectCount":   container.Count,
+				"LastModified":  container.LastModified,
+				"ReadACL":       container.ReadACL,
+				"PublicRead":    publicRead,
+				"PublicListing": publicListing,
+				"StoragePolicy": container.StoragePolicy,
+			},
+			Relationships: []Relationship{
+				{
+					Type:   "BELONGS_TO",
+					Target: container.ProjectID,
+				},
+			},
+		}
+
+		if container.HasPD != "" {
+			pd, pdRelationships := pdComponents(id, container.Name, "ObjectContainer", container.HasPD)
+			components = append(components, pd...)
+			component.Relationships = append(component.Relationships, pdRelationships...)
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// publicACL tells whether a Swift read ACL lets anyone read objects (".r:*")
+// or list the container (".rlistings").
+func publicACL(acl string) (read bool, listing bool) {
+	for _, element := range strings.Split(acl, ",") {
+		switch strings.TrimSpace(element) {
+		case ".r:*":
+			read = true
+		case ".rlistings":
+			listing = true
+		}
+	}
+	return read, listing
+}
+
 // Helper function to extract volume IDs
 func extractVolumeIDs(volumesAttached []interface{}) []string {
 	var ids []string
@@ -0,0 +1,142 @@
+package dataparser
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// Schemas of the has_pd annotation, one file per version named has_pd.v<version>.json
+//
+//go:embed schemas/has_pd.*.json
+var pdSchemaFiles embed.FS
+
+// PDSchemaDefault is the version of annotations without schemaVersion
+const PDSchemaDefault = "1"
+
+var pdSchemas = loadPDSchemas()
+
+// loadPDSchemas parses the embedded schemas, a broken schema is a bug of the build.
+func loadPDSchemas() map[string]*spec.Schema {
+	files, err := pdSchemaFiles.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	schemas := make(map[string]*spec.Schema)
+	for _, f := range files {
+		raw, err := pdSchemaFiles.ReadFile("schemas/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+		schema := &spec.Schema{}
+		if err := json.Unmarshal(raw, schema); err != nil {
+			panic(fmt.Sprintf("invalid schema %s: %v", f.Name(), err))
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(f.Name(), "has_pd.v"), ".json")
+		schemas[version] = schema
+	}
+	return schemas
+}
+
+// validatePD validates a has_pd annotation against the schema of its version.
+// It returns the annotation as compact JSON and the violations. The JSON of an
+// annotation with violations only keeps its well-formed data categories.
+func validatePD(annotation string) (pdJSON string, schemaVersion string, violations []string) {
+	schemaVersion = PDSchemaDefault
+	var pdData interface{}
+	if err := json.Unmarshal([]byte(annotation), &pdData); err != nil {
+		return "{}", schemaVersion, []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	if fields, ok := pdData.(map[string]interface{}); ok {
+		if v, ok := fields["schemaVersion"].(string); ok {
+			schemaVersion = v
+		}
+	}
+	if schema, exists := pdSchemas[schemaVersion]; !exists {
+		violations = []string{fmt.Sprintf("unknown schemaVersion %q", schemaVersion)}
+	} else {
+		result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(pdData)
+		for _, err := range result.Errors {
+			violations = append(violations, err.Error())
+		}
+		sort.Strings(violations)
+	}
+	if len(violations) > 0 {
+		pdData = wellFormedCategories(pdData)
+	}
+
+	compact, err := json.Marshal(pdData)
+	if err != nil {
+		return "{}", schemaVersion, []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return string(compact), schemaVersion, violations
+}
+
+// wellFormedCategories keeps the data categories of an invalid annotation
+// that have a name, and the string fields of each.
+func wellFormedCategories(pdData interface{}) map[string]interface{} {
+	categories := []interface{}{}
+	fields, _ := pdData.(map[string]interface{})
+	list, _ := fields["dataCategories"].([]interface{})
+	for _, item := range list {
+		category, ok := item.(map[string]interface{})
+		if name, _ := category["name"].(string); !ok || name == "" {
+			continue
+		}
+		wellFormed := make(map[string]interface{})
+		for _, key := range []string{"name", "purpose", "legalBasis", "storage"} {
+			if v, ok := category[key].(string); ok {
+				wellFormed[key] = v
+			}
+		}
+		categories = append(categories, wellFormed)
+	}
+	return map[string]interface{}{"dataCategories": categories}
+}
+
+// pdComponents turns the has_pd annotation of a pod, container or other
+// declaring object into a PDIndicator recording where it was declared. An
+// annotation that doesn't match its schema still declares personal data: its
+// indicator is flagged invalid and a Finding lists the violations. The
+// returned relationships link the owner to them.
+func pdComponents(ownerID string, ownerName string, ownerType string, annotation string) ([]InfrastructureComponent, []Relationship) {
+	pdJSON, schemaVersion, violations := validatePD(annotation)
+	indicator := InfrastructureComponent{
+		ID:   "pd_indicator_" + ownerID,
+		Name: "pd_indicator_" + ownerName,
+		Type: "PDIndicator",
+		Metadata: map[string]interface{}{
+			"has_pd":        pdJSON,
+			"SchemaVersion": schemaVersion,
+			"DeclaredOn":    ownerType,
+			"DeclaredBy":    ownerName,
+			"Valid":         len(violations) == 0,
+		},
+	}
+	components := []InfrastructureComponent{indicator}
+	relationships := []Relationship{{Type: "HAS_PD", Target: indicator.ID}}
+	if len(violations) > 0 {
+		finding := InfrastructureComponent{
+			ID:   "pd_finding_" + ownerID,
+			Name: "pd_finding_" + ownerName,
+			Type: "Finding",
+			Metadata: map[string]interface{}{
+				"Kind":          "InvalidPDAnnotation",
+				"SchemaVersion": schemaVersion,
+				"Violations":    violations,
+				"Annotation":    annotation,
+				"Subject":       ownerName,
+				"SubjectType":   ownerType,
+			},
+		}
+		components = append(components, finding)
+		relationships = append(relationships, Relationship{Type: "HAS_FINDING", Target: finding.ID})
+	}
+	return components, relationships
+}
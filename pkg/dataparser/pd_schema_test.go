@@ -0,0 +1,150 @@
+package dataparser
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+func TestValidatePD(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotation    string
+		pdJSON        string
+		schemaVersion string
+		violations    []string // substrings of the violations, in order
+	}{
+		{
+			name:          "valid without schemaVersion",
+			annotation:    `{"dataCategories": [{"name": "health", "purpose": "treatment", "legalBasis": "consent", "storage": "30 days"}]}`,
+			pdJSON:        `{"dataCategories":[{"legalBasis":"consent","name":"health","purpose":"treatment","storage":"30 days"}]}`,
+			schemaVersion: "1",
+		},
+		{
+			name:          "valid with schemaVersion",
+			annotation:    `{"schemaVersion": "1", "dataCategories": [{"name": "email", "purpose": "billing", "legalBasis": "contract", "storage": "10 years"}]}`,
+			pdJSON:        `{"dataCategories":[{"legalBasis":"contract","name":"email","purpose":"billing","storage":"10 years"}],"schemaVersion":"1"}`,
+			schemaVersion: "1",
+		},
+		{
+			name:          "invalid JSON",
+			annotation:    `{"dataCategories": [`,
+			pdJSON:        `{}`,
+			schemaVersion: "1",
+			violations:    []string{"invalid JSON"},
+		},
+		{
+			name:          "unknown schemaVersion",
+			annotation:    `{"schemaVersion": "9", "dataCategories": [{"name": "health"}]}`,
+			pdJSON:        `{"dataCategories":[{"name":"health"}]}`,
+			schemaVersion: "9",
+			violations:    []string{`unknown schemaVersion "9"`},
+		},
+		{
+			name:          "missing dataCategories",
+			annotation:    `{"categories": ["health"]}`,
+			pdJSON:        `{"dataCategories":[]}`,
+			schemaVersion: "1",
+			violations:    []string{"dataCategories in body is required"},
+		},
+		{
+			name:          "empty dataCategories",
+			annotation:    `{"dataCategories": []}`,
+			pdJSON:        `{"dataCategories":[]}`,
+			schemaVersion: "1",
+			violations:    []string{"dataCategories in body should have at least 1 items"},
+		},
+		{
+			name:          "missing fields keep the named categories",
+			annotation:    `{"dataCategories": [{"name": "health", "purpose": "treatment"}, {"purpose": "billing"}]}`,
+			pdJSON:        `{"dataCategories":[{"name":"health","purpose":"treatment"}]}`,
+			schemaVersion: "1",
+			violations:    []string{"dataCategories[0].legalBasis in body is required", "dataCategories[0].storage in body is required", "dataCategories[1].legalBasis in body is required", "dataCategories[1].name in body is required", "dataCategories[1].storage in body is required"},
+		},
+		{
+			name:          "wrong types are dropped",
+			annotation:    `{"dataCategories": [{"name": "health", "purpose": {"primary": "treatment"}, "legalBasis": "consent", "storage": 30}]}`,
+			pdJSON:        `{"dataCategories":[{"legalBasis":"consent","name":"health"}]}`,
+			schemaVersion: "1",
+			violations:    []string{"dataCategories[0].purpose in body must be of type string", "dataCategories[0].storage in body must be of type string"},
+		},
+		{
+			name:          "empty name",
+			annotation:    `{"dataCategories": [{"name": "", "purpose": "treatment", "legalBasis": "consent", "storage": "30 days"}]}`,
+			pdJSON:        `{"dataCategories":[]}`,
+			schemaVersion: "1",
+			violations:    []string{"dataCategories[0].name in body should be at least 1 chars long"},
+		},
+		{
+			name:          "not an object",
+			annotation:    `["health"]`,
+			pdJSON:        `{"dataCategories":[]}`,
+			schemaVersion: "1",
+			violations:    []string{"must be of type object"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdJSON, schemaVersion, violations := validatePD(tt.annotation)
+			if pdJSON != tt.pdJSON {
+				t.Errorf("pdJSON = %s, want %s", pdJSON, tt.pdJSON)
+			}
+			if schemaVersion != tt.schemaVersion {
+				t.Errorf("schemaVersion = %q, want %q", schemaVersion, tt.schemaVersion)
+			}
+			if len(violations) != len(tt.violations) {
+				t.Fatalf("violations = %q, want %d matching %q", violations, len(tt.violations), tt.violations)
+			}
+			for i, want := range tt.violations {
+				if !strings.Contains(violations[i], want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, violations[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPDComponents(t *testing.T) {
+	valid, validRelationships := pdComponents("uid-1", "web", "Pod", `{"dataCategories": [{"name": "health", "purpose": "treatment", "legalBasis": "consent", "storage": "30 days"}]}`)
+	if len(valid) != 1 || valid[0].Type != "PDIndicator" || valid[0].Metadata["Valid"] != true {
+		t.Errorf("valid annotation gave %+v", valid)
+	}
+	if len(validRelationships) != 1 || validRelationships[0] != (Relationship{Type: "HAS_PD", Target: "pd_indicator_uid-1"}) {
+		t.Errorf("valid annotation gave relationships %+v", validRelationships)
+	}
+
+	// An invalid declaration keeps the pod among the ones with personal data
+	invalid, invalidRelationships := pdComponents("uid-2", "api", "Pod", `{"dataCategories": [{"name": "health"}]}`)
+	if len(invalid) != 2 || invalid[0].Type != "PDIndicator" || invalid[0].Metadata["Valid"] != false || invalid[1].Type != "Finding" {
+		t.Fatalf("invalid annotation gave %+v", invalid)
+	}
+	if invalid[0].Metadata["has_pd"] != `{"dataCategories":[{"name":"health"}]}` {
+		t.Errorf("invalid indicator has_pd = %v", invalid[0].Metadata["has_pd"])
+	}
+	want := []Relationship{{Type: "HAS_PD", Target: "pd_indicator_uid-2"}, {Type: "HAS_FINDING", Target: "pd_finding_uid-2"}}
+	if len(invalidRelationships) != 2 || invalidRelationships[0] != want[0] || invalidRelationships[1] != want[1] {
+		t.Errorf("invalid annotation gave relationships %+v, want %+v", invalidRelationships, want)
+	}
+}
+
+// The validator implements the OpenAPI subset of JSON Schema, keywords outside
+// of it are ignored, see schemas/README.md.
+func TestPDSchemaValidatorIgnoresUnsupportedKeywords(t *testing.T) {
+	schema := &spec.Schema{}
+	if err := schema.UnmarshalJSON([]byte(`{"type": "object", "const": {"a": 1}, "contains": {"type": "string"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(map[string]interface{}{"b": 2})
+	if len(result.Errors) != 0 {
+		t.Errorf("unsupported keywords were enforced: %v", result.Errors)
+	}
+}
+
+func TestPDSchemasLoaded(t *testing.T) {
+	if _, exists := pdSchemas[PDSchemaDefault]; !exists {
+		t.Errorf("default schema version %s isn't embedded, got %v", PDSchemaDefault, pdSchemas)
+	}
+}
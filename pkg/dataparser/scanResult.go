@@ -0,0 +1,103 @@
+package dataparser
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status of a scan, a provider or a resource key
+const (
+	ScanStatusOK       = "ok"
+	ScanStatusPartial  = "partial"
+	ScanStatusFailed   = "failed"
+	ScanStatusExcluded = "excluded" // resource key left out by the provider's filters
+)
+
+// ScanResult describes the outcome of a scan, it is stored on the version's Metadata node.
+type ScanResult struct {
+	Version   string               `json:"version"`
+	Trigger   string               `json:"trigger"` // initial, schedule or manual
+	StartedAt time.Time            `json:"startedAt"`
+	Duration  time.Duration        `json:"duration"`
+	Providers []ProviderScanResult `json:"providers"`
+	// Cross-provider links, e.g. cluster nodes to the instances they run on
+	IdentityLinks int      `json:"identityLinks"`
+	LinkErrors    []string `json:"linkErrors,omitempty"`
+}
+
+// ProviderScanResult describes fetching, transforming and storing the data of a single provider.
+type ProviderScanResult struct {
+	Provider      string        `json:"provider"` // instance ID
+	Type          string        `json:"type"`
+	Status        string        `json:"status"`
+	FetchedAt     time.Time     `json:"fetchedAt"`
+	FetchDuration time.Duration `json:"fetchDuration"`
+	Reused        bool          `json:"reused,omitempty"` // provider wasn't due, its last fetch was stored again
+	Error         string        `json:"error,omitempty"`
+	StoreErrors   []string      `json:"storeErrors,omitempty"`
+	// Relationships whose target isn't part of the version, e.g. filtered
+	// out or of an unscanned provider. They don't make the provider partial.
+	UnmatchedRelationships []string             `json:"unmatchedRelationships,omitempty"`
+	Resources              []ResourceScanResult `json:"resources"`
+}
+
+// ResourceScanResult describes transforming the raw data of a single resource key.
+type ResourceScanResult struct {
+	Key            string        `json:"key"`
+	Status         string        `json:"status"`
+	ItemCount      int           `json:"itemCount"`
+	FilteredCount  int           `json:"filteredCount,omitempty"` // items dropped by the provider's filters
+	ComponentCount int           `json:"componentCount"`
+	Duration       time.Duration `json:"duration"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// Status summarizes the scan, it is only ok when every provider is.
+func (s *ScanResult) Status() string {
+	if len(s.Providers) == 0 {
+		return ScanStatusFailed
+	}
+	failed := 0
+	status := ScanStatusOK
+	for _, p := range s.Providers {
+		switch p.Status {
+		case ScanStatusFailed:
+			failed++
+			status = ScanStatusPartial
+		case ScanStatusPartial:
+			status = ScanStatusPartial
+		}
+	}
+	if failed == len(s.Providers) {
+		return ScanStatusFailed
+	}
+	return status
+}
+
+// Complete reports whether all providers were scanned and stored without errors.
+func (s *ScanResult) Complete() bool {
+	return s.Status() == ScanStatusOK
+}
+
+// JSON serializes the result, Neo4j properties can't hold nested maps.
+func (s *ScanResult) JSON() (string, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+// UpdateStatus derives the provider status from the fetch, transform and store errors.
+func (p *ProviderScanResult) UpdateStatus() {
+	switch {
+	case p.Error != "" && len(p.Resources) == 0:
+		p.Status = ScanStatusFailed
+	case p.Error != "" || len(p.StoreErrors) > 0:
+		p.Status = ScanStatusPartial
+	default:
+		p.Status = ScanStatusOK
+		for _, r := range p.Resources {
+			if r.Status != ScanStatusOK && r.Status != ScanStatusExcluded {
+				p.Status = ScanStatusPartial
+			}
+		}
+	}
+}
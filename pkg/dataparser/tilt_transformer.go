@@ -0,0 +1,150 @@
+package dataparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+)
+
+// Transform maps TILT documents onto a TransparencyDocument per service with
+// the DataCategory, Purpose, LegalBasis and Controller nodes it declares. The
+// documents are linked to the pods and services they describe once all
+// providers are stored, see ResolveIdentities.
+func (t *TILTTransformer) Transform(key string, data []interface{}) ([]InfrastructureComponent, error) {
+	switch key {
+	case "tilt_document":
+		return handleTILTDocument(data), nil
+	default:
+		return nil, fmt.Errorf("unknown key for TILT: %s", key)
+	}
+}
+
+func handleTILTDocument(data []interface{}) []InfrastructureComponent {
+	var components []InfrastructureComponent
+	// Controllers, purposes and legal bases are shared between documents
+	seen := make(map[string]bool)
+	shared := func(c InfrastructureComponent) {
+		if !seen[c.Type+"/"+c.ID] {
+			seen[c.Type+"/"+c.ID] = true
+			components = append(components, c)
+		}
+	}
+
+	for _, item := range data {
+		doc, ok := item.(plugin.TILTDocument)
+		if !ok {
+			fmt.Printf("expected type plugin.TILTDocument, but got: %T\n", item)
+			continue
+		}
+		// The service a document describes, named in meta or by its file
+		service := doc.Meta.Name
+		if service == "" {
+			service = strings.TrimSuffix(doc.File, ".json")
+		}
+		docID := doc.Meta.ID
+		if docID == "" {
+			docID = service
+		}
+
+		document := InfrastructureComponent{
+			ID:   docID,
+			Name: service,
+			Type: "TransparencyDocument",
+			Metadata: map[string]interface{}{
+				"Service":  service,
+				"File":     doc.File,
+				"Status":   doc.Meta.Status,
+				"URL":      doc.Meta.URL,
+				"Hash":     doc.Meta.Hash,
+				"DPO":      doc.DataProtectionOfficer.Name,
+				"DPOEmail": doc.DataProtectionOfficer.Email,
+			},
+		}
+
+		if doc.Controller.Name != "" {
+			shared(InfrastructureComponent{
+				ID:   doc.Controller.Name,
+				Name: doc.Controller.Name,
+				Type: "Controller",
+				Metadata: map[string]interface{}{
+					"Division": doc.Controller.Division,
+					"Address":  doc.Controller.Address,
+					"Country":  doc.Controller.Country,
+				},
+			})
+			document.Relationships = append(document.Relationships, Relationship{Type: "CONTROLLED_BY", Target: doc.Controller.Name})
+		}
+
+		for i, disclosed := range doc.DataDisclosed {
+			category := tiltCategory(docID, i, disclosed)
+			for _, p := range disclosed.Purposes {
+				if p.Purpose == "" {
+					continue
+				}
+				shared(InfrastructureComponent{
+					ID:       p.Purpose,
+					Name:     p.Purpose,
+					Type:     "Purpose",
+					Metadata: map[string]interface{}{"Description": p.Description},
+				})
+				category.Relationships = append(category.Relationships, Relationship{Type: "FOR_PURPOSE", Target: p.Purpose})
+			}
+			for _, l := range disclosed.LegalBases {
+				if l.Reference == "" {
+					continue
+				}
+				shared(InfrastructureComponent{
+					ID:       l.Reference,
+					Name:     l.Reference,
+					Type:     "LegalBasis",
+					Metadata: map[string]interface{}{"Description": l.Description},
+				})
+				category.Relationships = append(category.Relationships, Relationship{Type: "BASED_ON", Target: l.Reference})
+			}
+			components = append(components, category)
+			document.Relationships = append(document.Relationships, Relationship{Type: "HAS_CATEGORY", Target: category.ID})
+		}
+		components = append(components, document)
+	}
+	return components
+}
+
+// tiltCategory turns a disclosed data category into a DataCategory with the
+// same properties as the categories of has_pd annotations.
+func tiltCategory(docID string, index int, disclosed plugin.TILTDataDisclosed) InfrastructureComponent {
+	id := disclosed.ID
+	if id == "" {
+		id = fmt.Sprintf("%s/%d", docID, index)
+	}
+	var purposes, legalBases, storage, recipients []string
+	for _, p := range disclosed.Purposes {
+		purposes = append(purposes, p.Purpose)
+	}
+	for _, l := range disclosed.LegalBases {
+		legalBases = append(legalBases, l.Reference)
+	}
+	for _, s := range disclosed.Storage {
+		for _, t := range s.Temporal {
+			if t.TTL != "" {
+				storage = append(storage, t.TTL)
+			} else {
+				storage = append(storage, t.Description)
+			}
+		}
+	}
+	for _, r := range disclosed.Recipients {
+		recipients = append(recipients, r.Name)
+	}
+	return InfrastructureComponent{
+		ID:   id,
+		Name: disclosed.Category,
+		Type: "DataCategory",
+		Metadata: map[string]interface{}{
+			"Purpose":    strings.Join(purposes, ", "),
+			"LegalBasis": strings.Join(legalBases, ", "),
+			"Storage":    strings.Join(storage, ", "),
+			"Recipients": recipients,
+		},
+	}
+}
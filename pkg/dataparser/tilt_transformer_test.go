@@ -0,0 +1,101 @@
+package dataparser
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/plugin"
+)
+
+// tiltDocuments reads the TILT fixture of the plugin
+func tiltDocuments(t *testing.T) []interface{} {
+	t.Helper()
+	p := &plugin.TILTPlugin{}
+	if err := p.Initialize(map[string]interface{}{"path": "../plugin/testdata/tilt"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data["tilt_document"]
+}
+
+// Controllers, purposes and legal bases shared by documents and categories
+// are transformed once
+func TestTILTDocumentShared(t *testing.T) {
+	components := handleTILTDocument(tiltDocuments(t))
+	byType := make(map[string][]string)
+	for _, c := range components {
+		byType[c.Type] = append(byType[c.Type], c.ID)
+	}
+	for _, ids := range byType {
+		sort.Strings(ids)
+	}
+	want := map[string][]string{
+		"TransparencyDocument": {"newsletter", "tilt-shop"},
+		"Controller":           {"Example Retail GmbH"},
+		"Purpose":              {"billing", "marketing"},
+		"LegalBasis":           {"GDPR-6-1-a", "GDPR-6-1-b"},
+		"DataCategory":         {"newsletter-email", "shop-email", "tilt-shop/1"},
+	}
+	if !reflect.DeepEqual(byType, want) {
+		t.Errorf("components = %v, want %v", byType, want)
+	}
+
+	// Both documents link to the one controller
+	for _, id := range []string{"newsletter", "tilt-shop"} {
+		if got := targets(component(t, components, id), "CONTROLLED_BY"); !reflect.DeepEqual(got, []string{"Example Retail GmbH"}) {
+			t.Errorf("%s CONTROLLED_BY = %v", id, got)
+		}
+	}
+}
+
+func TestTILTDocumentCategories(t *testing.T) {
+	components := handleTILTDocument(tiltDocuments(t))
+	shop := component(t, components, "tilt-shop")
+	if got, want := targets(shop, "HAS_CATEGORY"), []string{"shop-email", "tilt-shop/1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HAS_CATEGORY = %v, want %v", got, want)
+	}
+	if shop.Name != "shop" || shop.Metadata["DPO"] != "Jane Doe" {
+		t.Errorf("document = %+v", shop)
+	}
+	// Documents without a name in meta are named by their file
+	if newsletter := component(t, components, "newsletter"); newsletter.Metadata["Service"] != "newsletter" {
+		t.Errorf("service of newsletter.json = %v", newsletter.Metadata["Service"])
+	}
+
+	tests := []struct {
+		id       string
+		purposes []string
+		metadata map[string]interface{}
+	}{
+		{
+			id:       "shop-email",
+			purposes: []string{"billing", "marketing"},
+			metadata: map[string]interface{}{
+				"Purpose": "billing, marketing", "LegalBasis": "GDPR-6-1-b",
+				"Storage": "Until the account is deleted", "Recipients": []string{"Mail Provider Inc."},
+			},
+		},
+		{
+			// Purposes without a name aren't linked but stay in the property
+			id:       "tilt-shop/1",
+			purposes: []string{"billing"},
+			metadata: map[string]interface{}{
+				"Purpose": "billing, ", "LegalBasis": "GDPR-6-1-b",
+				"Storage": "P10Y", "Recipients": []string(nil),
+			},
+		},
+	}
+	for _, tt := range tests {
+		c := component(t, components, tt.id)
+		if got := targets(c, "FOR_PURPOSE"); !reflect.DeepEqual(got, tt.purposes) {
+			t.Errorf("%s FOR_PURPOSE = %v, want %v", tt.id, got, tt.purposes)
+		}
+		if !reflect.DeepEqual(c.Metadata, tt.metadata) {
+			t.Errorf("%s metadata = %#v, want %#v", tt.id, c.Metadata, tt.metadata)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package dataparser
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	shared "github.com/regulatory-transparency-monitor/commons/models"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
@@ -14,40 +17,154 @@ type Transformer interface {
 
 var TransformerRegistry = make(map[string]Transformer)
 
-type OpenStackTransformer struct{}
+// scanScoped is implemented by transformers keeping state between the keys of
+// a provider instance, like the Kubernetes PVC to PV map. TransformData
+// transforms every instance with a fresh transformer, so no state leaks from
+// one cluster or region into the next.
+type scanScoped interface {
+	newScan() Transformer
+}
+
+type OpenStackTransformer struct {
+	// networking of instances and routers, recorded while ports are transformed
+	networking *networking
+}
 type KubernetesTransformer struct {
 	pvcToPVMap map[string]string
+	// owners maps workload UIDs to the UID of their controller until pods are transformed
+	owners map[string]string
+	// services of the scan until ingresses and pods are transformed
+	services *services
+	// has_pd declarations until the pods and PVs they cover are transformed
+	declarations *pdDeclarations
+	// claims listed under k8s_pvc, pods only create the claims missing there
+	claims map[string]bool
 }
+type AWSTransformer struct{}
+type TILTTransformer struct{}
 type DefaultTransformerFactory struct{}
 
+func (o *OpenStackTransformer) newScan() Transformer {
+	return &OpenStackTransformer{}
+}
+
+func (k *KubernetesTransformer) newScan() Transformer {
+	return &KubernetesTransformer{}
+}
+
 func init() {
 	TransformerRegistry["os"] = &OpenStackTransformer{}
 	TransformerRegistry["k8s"] = &KubernetesTransformer{}
+	TransformerRegistry["aws"] = &AWSTransformer{}
+	TransformerRegistry["tilt"] = &TILTTransformer{}
 }
 
-// Look for key
-func TransformData(rawData shared.RawData) ([]InfrastructureComponent, error) {
+// Keys other keys depend on, e.g. pods are linked to PVs through the PVC to PV
+// map, to their controllers through the workload owners and to services
+// through their selectors, and instances to their networks through their ports.
+// Pods and PVs inherit the has_pd declarations of namespaces, controllers and claims.
+// They are transformed first, all other keys follow in name order.
+var transformFirst = []string{"k8s_namespace", "k8s_pvc", "k8s_pv", "k8s_cronjob", "k8s_daemonset", "k8s_deployment", "k8s_job", "k8s_replicaset", "k8s_statefulset", "k8s_service", "os_port", "os_floatingip"}
+
+// TransformData filters the raw data of a provider instance and transforms it
+// key by key, tagging every component with the instance ID. A result is
+// returned for every key, the error is non-nil when at least one key failed.
+func TransformData(provider string, rawData shared.RawData, filter Filter) ([]InfrastructureComponent, []ResourceScanResult, error) {
 	var components []InfrastructureComponent
+	var results []ResourceScanResult
+	var failed []string
+	// transformers of this instance, by registered transformer
+	scoped := make(map[Transformer]Transformer)
+
+	rawData, excludedKeys, dropped := filter.Apply(rawData)
+	sort.Strings(excludedKeys)
+	for _, key := range excludedKeys {
+		results = append(results, ResourceScanResult{Key: key, Status: ScanStatusExcluded, FilteredCount: dropped[key]})
+	}
 
-	for key, dataList := range rawData {
-		prefix := getPrefix(key) // e.g., "os" from "os_server" / "aws" from "aws_instance"
-		transformer := TransformerRegistry[prefix]
+	for _, key := range orderedKeys(rawData) {
+		dataList := rawData[key]
+		start := time.Now()
+		result := ResourceScanResult{Key: key, Status: ScanStatusOK, ItemCount: len(dataList), FilteredCount: dropped[key]}
 
+		transformer := transformerFor(key)
 		if transformer == nil {
 			logger.Warning(logger.LogFields{"error": "no transformer found for key:", "key": key})
+			result.Status = ScanStatusFailed
+			result.Error = "no transformer found"
+			results = append(results, result)
+			failed = append(failed, key)
 			continue
 		}
+		if s, ok := transformer.(scanScoped); ok {
+			if _, exists := scoped[transformer]; !exists {
+				scoped[transformer] = s.newScan()
+			}
+			transformer = scoped[transformer]
+		}
 
 		transformedData, err := transformer.Transform(key, dataList)
+		result.Duration = time.Since(start)
 		if err != nil {
 			logger.Error(logger.LogFields{"error": "transforming data for:", "key": key})
+			result.Status = ScanStatusFailed
+			result.Error = err.Error()
+			results = append(results, result)
+			failed = append(failed, key)
 			continue
 		}
+		for i := range transformedData {
+			transformedData[i].Provider = provider
+		}
+		result.ComponentCount = len(transformedData)
+		results = append(results, result)
 		components = append(components, transformedData...)
 	}
 
-	return components, nil
+	if len(failed) > 0 {
+		return components, results, fmt.Errorf("transforming failed for keys: %s", strings.Join(failed, ", "))
+	}
+	return components, results, nil
+}
+
+// transformerFor returns the mapping declared for key, or the transformer
+// registered for its prefix, e.g. "os" from "os_server".
+func transformerFor(key string) Transformer {
+	if m, exists := MappingRegistry[key]; exists {
+		return m
+	}
+	if t, exists := TransformerRegistry[getPrefix(key)]; exists {
+		return t
+	}
+	return nil
+}
+
+func orderedKeys(rawData shared.RawData) []string {
+	var keys []string
+	for _, key := range transformFirst {
+		if _, exists := rawData[key]; exists {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range rawData {
+		if !contains(transformFirst, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
+
 func getPrefix(key string) string {
 	parts := strings.Split(key, "_")
 	if len(parts) > 0 {
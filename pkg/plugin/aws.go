@@ -0,0 +1,340 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+)
+
+// API versions of the AWS Query APIs used by the plugin
+const (
+	ec2APIVersion = "2016-11-15"
+	stsAPIVersion = "2011-06-15"
+)
+
+// AWSAccount is the account the credentials belong to, it plays the role of an OpenStack project.
+type AWSAccount struct {
+	ID     string `json:"id" xml:"GetCallerIdentityResult>Account"`
+	ARN    string `json:"arn" xml:"GetCallerIdentityResult>Arn"`
+	Region string `json:"region"`
+}
+
+// AWSTag is a key value tag of an EC2 resource.
+type AWSTag struct {
+	Key   string `json:"key" xml:"key"`
+	Value string `json:"value" xml:"value"`
+}
+
+// AWSTags are the tags of an EC2 resource.
+type AWSTags []AWSTag
+
+// EC2Instance is an EC2 instance as returned by DescribeInstances.
+type EC2Instance struct {
+	InstanceID       string `json:"instanceId" xml:"instanceId"`
+	InstanceType     string `json:"instanceType" xml:"instanceType"`
+	ImageID          string `json:"imageId" xml:"imageId"`
+	OwnerID          string `json:"ownerId" xml:"-"` // set from the reservation
+	State            string `json:"state" xml:"instanceState>name"`
+	LaunchTime       string `json:"launchTime" xml:"launchTime"`
+	AvailabilityZone string `json:"availabilityZone" xml:"placement>availabilityZone"`
+	Tenancy          string `json:"tenancy" xml:"placement>tenancy"`
+	HostID           string `json:"hostId,omitempty" xml:"placement>hostId"`
+	VpcID            string `json:"vpcId" xml:"vpcId"`
+	SubnetID         string `json:"subnetId" xml:"subnetId"`
+	PrivateIPAddress string `json:"privateIpAddress" xml:"privateIpAddress"`
+	BlockDevices     []struct {
+		DeviceName string `json:"deviceName" xml:"deviceName"`
+		VolumeID   string `json:"volumeId" xml:"ebs>volumeId"`
+	} `json:"blockDevices" xml:"blockDeviceMapping>item"`
+	Tags AWSTags `json:"tags" xml:"tagSet>item"`
+}
+
+// EBSVolume is an EBS volume as returned by DescribeVolumes.
+type EBSVolume struct {
+	VolumeID           string `json:"volumeId" xml:"volumeId"`
+	Size               int    `json:"size" xml:"size"`
+	SnapshotID         string `json:"snapshotId" xml:"snapshotId"`
+	AvailabilityZone   string `json:"availabilityZone" xml:"availabilityZone"`
+	Status             string `json:"status" xml:"status"`
+	CreateTime         string `json:"createTime" xml:"createTime"`
+	VolumeType         string `json:"volumeType" xml:"volumeType"`
+	Encrypted          bool   `json:"encrypted" xml:"encrypted"`
+	MultiAttachEnabled bool   `json:"multiAttachEnabled" xml:"multiAttachEnabled"`
+	Attachments        []struct {
+		InstanceID string `json:"instanceId" xml:"instanceId"`
+		Device     string `json:"device" xml:"device"`
+		Status     string `json:"status" xml:"status"`
+		AttachTime string `json:"attachTime" xml:"attachTime"`
+	} `json:"attachments" xml:"attachmentSet>item"`
+	Tags AWSTags `json:"tags" xml:"tagSet>item"`
+}
+
+// EBSSnapshot is an EBS snapshot as returned by DescribeSnapshots.
+type EBSSnapshot struct {
+	SnapshotID  string  `json:"snapshotId" xml:"snapshotId"`
+	VolumeID    string  `json:"volumeId" xml:"volumeId"`
+	OwnerID     string  `json:"ownerId" xml:"ownerId"`
+	Status      string  `json:"status" xml:"status"`
+	StartTime   string  `json:"startTime" xml:"startTime"`
+	Progress    string  `json:"progress" xml:"progress"`
+	VolumeSize  int     `json:"volumeSize" xml:"volumeSize"`
+	Description string  `json:"description" xml:"description"`
+	Encrypted   bool    `json:"encrypted" xml:"encrypted"`
+	Tags        AWSTags `json:"tags" xml:"tagSet>item"`
+}
+
+// AWSPlugin scans EC2 instances, EBS volumes and snapshots of a single region
+// through the EC2 Query API. base_url replaces the regional endpoints, e.g.
+// to scan a local AWS API stand-in like LocalStack or moto.
+type AWSPlugin struct {
+	Region      string
+	Endpoint    string
+	Credentials AWSCredentials
+	Client      *http.Client
+	// Scope skips the fetch if the account isn't selected by the filters
+	Scope Scope
+}
+
+// Initialize reads the aws provider config. Credentials fall back to the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables.
+func (a *AWSPlugin) Initialize(config map[string]interface{}) error {
+	a.Region, _ = config["region"].(string)
+	if a.Region == "" {
+		a.Region = os.Getenv("AWS_REGION")
+	}
+	if a.Region == "" {
+		return fmt.Errorf("aws region configuration is missing")
+	}
+	if apiAccess, ok := config["api_access"].(map[string]interface{}); ok {
+		a.Endpoint, _ = apiAccess["base_url"].(string)
+	}
+	a.Endpoint = strings.TrimSuffix(a.Endpoint, "/")
+
+	a.Credentials = AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds, ok := config["credentials"].(map[string]interface{}); ok {
+		if id, ok := creds["access_key_id"].(string); ok && id != "" {
+			a.Credentials.AccessKeyID = id
+			a.Credentials.SecretAccessKey, _ = creds["secret_access_key"].(string)
+			a.Credentials.SessionToken, _ = creds["session_token"].(string)
+		}
+	}
+	if a.Credentials.AccessKeyID == "" || a.Credentials.SecretAccessKey == "" {
+		return fmt.Errorf("aws credentials are missing")
+	}
+
+	a.Scope = ParseScope(config)
+	a.Client = &http.Client{Timeout: 60 * time.Second}
+	return nil
+}
+
+func (a *AWSPlugin) FetchData() (models.RawData, error) {
+	return a.FetchDataContext(context.Background())
+}
+
+// FetchDataContext returns the account under aws_account and the EC2 and EBS
+// resources under aws_instance, aws_volume and aws_snapshot.
+func (a *AWSPlugin) FetchDataContext(ctx context.Context) (models.RawData, error) {
+	data := make(models.RawData)
+
+	var account AWSAccount
+	if err := a.call(ctx, "sts", url.Values{"Action": {"GetCallerIdentity"}, "Version": {stsAPIVersion}}, &account); err != nil {
+		return nil, fmt.Errorf("error fetching aws account: %v", err)
+	}
+	account.Region = a.Region
+	data["aws_account"] = []interface{}{account}
+	if !a.Scope.SelectsProject(account.ID) {
+		logger.Info("Skipping account excluded by filters", logger.LogFields{"account": account.ID})
+		return data, nil
+	}
+
+	instances, err := a.describeInstances(ctx)
+	if err != nil {
+		return data, fmt.Errorf("error fetching ec2 instances: %v", err)
+	}
+	data["aws_instance"] = instances
+
+	volumes, err := a.describeVolumes(ctx)
+	if err != nil {
+		return data, fmt.Errorf("error fetching ebs volumes: %v", err)
+	}
+	data["aws_volume"] = volumes
+
+	snapshots, err := a.describeSnapshots(ctx)
+	if err != nil {
+		return data, fmt.Errorf("error fetching ebs snapshots: %v", err)
+	}
+	data["aws_snapshot"] = snapshots
+
+	return data, nil
+}
+
+func (a *AWSPlugin) describeInstances(ctx context.Context) ([]interface{}, error) {
+	var items []interface{}
+	err := a.paginate(ctx, "DescribeInstances", nil, func(body []byte) (string, error) {
+		var page struct {
+			Reservations []struct {
+				OwnerID   string        `xml:"ownerId"`
+				Instances []EC2Instance `xml:"instancesSet>item"`
+			} `xml:"reservationSet>item"`
+			NextToken string `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, r := range page.Reservations {
+			for _, instance := range r.Instances {
+				instance.OwnerID = r.OwnerID
+				items = append(items, instance)
+			}
+		}
+		return page.NextToken, nil
+	})
+	return items, err
+}
+
+func (a *AWSPlugin) describeVolumes(ctx context.Context) ([]interface{}, error) {
+	var items []interface{}
+	err := a.paginate(ctx, "DescribeVolumes", nil, func(body []byte) (string, error) {
+		var page struct {
+			Volumes   []EBSVolume `xml:"volumeSet>item"`
+			NextToken string      `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, volume := range page.Volumes {
+			items = append(items, volume)
+		}
+		return page.NextToken, nil
+	})
+	return items, err
+}
+
+func (a *AWSPlugin) describeSnapshots(ctx context.Context) ([]interface{}, error) {
+	var items []interface{}
+	// Only snapshots owned by the account, public snapshots are not ours to monitor
+	params := url.Values{"Owner.1": {"self"}}
+	err := a.paginate(ctx, "DescribeSnapshots", params, func(body []byte) (string, error) {
+		var page struct {
+			Snapshots []EBSSnapshot `xml:"snapshotSet>item"`
+			NextToken string        `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, snapshot := range page.Snapshots {
+			items = append(items, snapshot)
+		}
+		return page.NextToken, nil
+	})
+	return items, err
+}
+
+// paginate calls an EC2 action until the response carries no next token,
+// handing every response body to page.
+func (a *AWSPlugin) paginate(ctx context.Context, action string, params url.Values, page func(body []byte) (string, error)) error {
+	token := ""
+	for {
+		values := url.Values{"Action": {action}, "Version": {ec2APIVersion}, "MaxResults": {"500"}}
+		for k, v := range params {
+			values[k] = v
+		}
+		if token != "" {
+			values.Set("NextToken", token)
+		}
+		body, err := a.do(ctx, "ec2", values)
+		if err != nil {
+			return err
+		}
+		token, err = page(body)
+		if err != nil {
+			return fmt.Errorf("error decoding %s response: %v", action, err)
+		}
+		if token == "" {
+			return nil
+		}
+	}
+}
+
+// call runs a single Query API action and decodes its response into out.
+func (a *AWSPlugin) call(ctx context.Context, service string, values url.Values, out interface{}) error {
+	body, err := a.do(ctx, service, values)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(body, out)
+}
+
+// do sends a signed Query API request and returns the response body.
+func (a *AWSPlugin) do(ctx context.Context, service string, values url.Values) ([]byte, error) {
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", service, a.Region)
+	}
+	body := []byte(values.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signV4(req, body, a.Credentials, a.Region, service, time.Now())
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// EC2 nests errors in Errors, STS doesn't
+		var apiErr struct {
+			EC2 struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Errors>Error"`
+			STS struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		if xml.Unmarshal(respBody, &apiErr) == nil {
+			if e := apiErr.EC2; e.Code != "" {
+				return nil, fmt.Errorf("%s %s: %s: %s", service, values.Get("Action"), e.Code, e.Message)
+			}
+			if e := apiErr.STS; e.Code != "" {
+				return nil, fmt.Errorf("%s %s: %s: %s", service, values.Get("Action"), e.Code, e.Message)
+			}
+		}
+		return nil, fmt.Errorf("%s %s: unexpected status %s", service, values.Get("Action"), resp.Status)
+	}
+	return respBody, nil
+}
+
+// Get returns the value of a tag, e.g. the Name of a resource.
+func (tags AWSTags) Get(key string) string {
+	for _, t := range tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
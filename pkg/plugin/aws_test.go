@@ -0,0 +1,218 @@
+package plugin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Query API responses of a local AWS API stand-in holding the resources of
+// deployments/fixtures/aws.json. DescribeInstances is split into two pages.
+var awsStandInResponses = map[string][]string{
+	"GetCallerIdentity": {`<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/graph-builder</Arn>
+    <UserId>AIDACKCEVSQ6C2EXAMPLE</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+</GetCallerIdentityResponse>`},
+	"DescribeInstances": {`<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <reservationSet>
+    <item>
+      <reservationId>r-0a1b2c3d4e5f60001</reservationId>
+      <ownerId>123456789012</ownerId>
+      <instancesSet>
+        <item>
+          <instanceId>i-0a1b2c3d4e5f60001</instanceId>
+          <imageId>ami-0abcdef1234567890</imageId>
+          <instanceState><code>16</code><name>running</name></instanceState>
+          <instanceType>t3.medium</instanceType>
+          <launchTime>2024-03-01T10:00:00.000Z</launchTime>
+          <placement><availabilityZone>eu-central-1a</availabilityZone><tenancy>default</tenancy></placement>
+          <subnetId>subnet-0a1b2c3d</subnetId>
+          <vpcId>vpc-0a1b2c3d</vpcId>
+          <privateIpAddress>10.0.1.12</privateIpAddress>
+          <blockDeviceMapping>
+            <item>
+              <deviceName>/dev/xvda</deviceName>
+              <ebs><volumeId>vol-0a1b2c3d4e5f60001</volumeId><status>attached</status></ebs>
+            </item>
+          </blockDeviceMapping>
+          <tagSet>
+            <item><key>Name</key><value>customer-db</value></item>
+          </tagSet>
+        </item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+  <nextToken>page-2</nextToken>
+</DescribeInstancesResponse>`, `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <reservationSet>
+    <item>
+      <reservationId>r-0a1b2c3d4e5f60002</reservationId>
+      <ownerId>123456789012</ownerId>
+      <instancesSet>
+        <item>
+          <instanceId>i-0a1b2c3d4e5f60002</instanceId>
+          <instanceState><code>16</code><name>running</name></instanceState>
+          <instanceType>m5.large</instanceType>
+          <launchTime>2024-03-02T10:00:00.000Z</launchTime>
+          <placement>
+            <availabilityZone>eu-central-1b</availabilityZone>
+            <tenancy>host</tenancy>
+            <hostId>h-0a1b2c3d4e5f60001</hostId>
+          </placement>
+          <subnetId>subnet-0e1f2a3b</subnetId>
+          <vpcId>vpc-0a1b2c3d</vpcId>
+        </item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+</DescribeInstancesResponse>`},
+	"DescribeVolumes": {`<DescribeVolumesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <volumeSet>
+    <item>
+      <volumeId>vol-0a1b2c3d4e5f60001</volumeId>
+      <size>50</size>
+      <snapshotId/>
+      <availabilityZone>eu-central-1a</availabilityZone>
+      <status>in-use</status>
+      <createTime>2024-03-01T10:00:00.000Z</createTime>
+      <attachmentSet>
+        <item>
+          <volumeId>vol-0a1b2c3d4e5f60001</volumeId>
+          <instanceId>i-0a1b2c3d4e5f60001</instanceId>
+          <device>/dev/xvda</device>
+          <status>attached</status>
+          <attachTime>2024-03-01T10:00:01.000Z</attachTime>
+          <deleteOnTermination>true</deleteOnTermination>
+        </item>
+      </attachmentSet>
+      <volumeType>gp3</volumeType>
+      <encrypted>true</encrypted>
+      <multiAttachEnabled>false</multiAttachEnabled>
+    </item>
+  </volumeSet>
+</DescribeVolumesResponse>`},
+	"DescribeSnapshots": {`<DescribeSnapshotsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <snapshotSet>
+    <item>
+      <snapshotId>snap-0a1b2c3d4e5f60001</snapshotId>
+      <volumeId>vol-0a1b2c3d4e5f60001</volumeId>
+      <status>completed</status>
+      <startTime>2024-03-05T02:00:00.000Z</startTime>
+      <progress>100%</progress>
+      <ownerId>123456789012</ownerId>
+      <volumeSize>50</volumeSize>
+      <description>nightly backup</description>
+      <encrypted>true</encrypted>
+    </item>
+  </snapshotSet>
+</DescribeSnapshotsResponse>`},
+}
+
+// awsStandIn serves awsStandInResponses, the page of a response is picked by
+// the NextToken of the request.
+func awsStandIn(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			t.Errorf("request is not signed: %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Errorf("error parsing request body %q: %v", body, err)
+		}
+		if values.Get("Action") == "DescribeSnapshots" && values.Get("Owner.1") != "self" {
+			t.Errorf("DescribeSnapshots isn't limited to owned snapshots: %v", values)
+		}
+		pages := awsStandInResponses[values.Get("Action")]
+		page := 0
+		if values.Get("NextToken") == "page-2" {
+			page = 1
+		}
+		if page >= len(pages) {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `<Response><Errors><Error><Code>InvalidAction</Code><Message>unexpected request</Message></Error></Errors></Response>`)
+			return
+		}
+		io.WriteString(w, pages[page])
+	}))
+}
+
+func TestAWSPluginFetchData(t *testing.T) {
+	server := awsStandIn(t)
+	defer server.Close()
+
+	p := &AWSPlugin{}
+	err := p.Initialize(map[string]interface{}{
+		"region":      "eu-central-1",
+		"api_access":  map[string]interface{}{"base_url": server.URL + "/"},
+		"credentials": map[string]interface{}{"access_key_id": "AKIDEXAMPLE", "secret_access_key": "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := ReadRecording("../../deployments/fixtures/aws.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := DecodeRawData(rec.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, items := range want {
+		if !reflect.DeepEqual(data[key], items) {
+			t.Errorf("%s = %+v, want %+v", key, data[key], items)
+		}
+	}
+	if len(data) != len(want) {
+		t.Errorf("got resource keys %v, want those of the fixture", reflect.ValueOf(data).MapKeys())
+	}
+}
+
+func TestAWSPluginExcludedAccount(t *testing.T) {
+	server := awsStandIn(t)
+	defer server.Close()
+
+	p := &AWSPlugin{}
+	err := p.Initialize(map[string]interface{}{
+		"region":      "eu-central-1",
+		"api_access":  map[string]interface{}{"base_url": server.URL},
+		"credentials": map[string]interface{}{"access_key_id": "AKIDEXAMPLE", "secret_access_key": "secret"},
+		"filters":     map[string]interface{}{"exclude_projects": []interface{}{"123456789012"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || len(data["aws_account"]) != 1 {
+		t.Errorf("excluded account was scanned: %v", data)
+	}
+}
+
+func TestAWSPluginAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidClientTokenId</Code><Message>The security token included in the request is invalid.</Message></Error></ErrorResponse>`)
+	}))
+	defer server.Close()
+
+	p := &AWSPlugin{Region: "eu-central-1", Endpoint: server.URL, Credentials: AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, Client: server.Client()}
+	_, err := p.FetchData()
+	if err == nil || !strings.Contains(err.Error(), "InvalidClientTokenId") {
+		t.Errorf("FetchData() error = %v, want the STS error code", err)
+	}
+}
@@ -0,0 +1,113 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	osModels "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// EncodedRawData is the serialized form of models.RawData, every item is kept as raw JSON.
+type EncodedRawData map[string][]json.RawMessage
+
+// RawDataDecoder decodes a single serialized item into the type the transformers expect.
+type RawDataDecoder func(raw json.RawMessage) (interface{}, error)
+
+// Registry of decoders by resource key, keys without a decoder are decoded into generic maps.
+var RawDataDecoders = make(map[string]RawDataDecoder)
+
+func init() {
+	RawDataDecoders["os_project"] = decodePointer[osModels.ProjectDetails]
+	RawDataDecoders["os_instance"] = decodeValue[osModels.ServerDetails]
+	RawDataDecoders["os_volume"] = decodeValue[osModels.Volume]
+	RawDataDecoders["os_snapshot"] = decodeValue[osModels.Snapshot]
+	RawDataDecoders["os_network"] = decodeValue[NeutronNetwork]
+	RawDataDecoders["os_subnet"] = decodeValue[NeutronSubnet]
+	RawDataDecoders["os_port"] = decodeValue[NeutronPort]
+	RawDataDecoders["os_router"] = decodeValue[NeutronRouter]
+	RawDataDecoders["os_floatingip"] = decodeValue[NeutronFloatingIP]
+	RawDataDecoders["os_security_group"] = decodeValue[NeutronSecurityGroup]
+	RawDataDecoders["os_image"] = decodeValue[GlanceImage]
+	RawDataDecoders["os_flavor"] = decodeValue[NovaFlavor]
+	RawDataDecoders["os_container"] = decodeValue[SwiftContainer]
+	RawDataDecoders["k8s_node"] = decodeValue[corev1.Node]
+	RawDataDecoders["k8s_pod"] = decodeValue[corev1.Pod]
+	RawDataDecoders["k8s_pv"] = decodeValue[corev1.PersistentVolume]
+	RawDataDecoders["k8s_pvc"] = decodeValue[corev1.PersistentVolumeClaim]
+	RawDataDecoders["k8s_event"] = decodeValue[ResourceEvent]
+	RawDataDecoders["k8s_deployment"] = decodeValue[appsv1.Deployment]
+	RawDataDecoders["k8s_statefulset"] = decodeValue[appsv1.StatefulSet]
+	RawDataDecoders["k8s_daemonset"] = decodeValue[appsv1.DaemonSet]
+	RawDataDecoders["k8s_replicaset"] = decodeValue[appsv1.ReplicaSet]
+	RawDataDecoders["k8s_job"] = decodeValue[batchv1.Job]
+	RawDataDecoders["k8s_cronjob"] = decodeValue[batchv1.CronJob]
+	RawDataDecoders["k8s_namespace"] = decodeValue[corev1.Namespace]
+	RawDataDecoders["k8s_service"] = decodeValue[corev1.Service]
+	RawDataDecoders["k8s_ingress"] = decodeValue[networkingv1.Ingress]
+	RawDataDecoders["aws_account"] = decodeValue[AWSAccount]
+	RawDataDecoders["aws_instance"] = decodeValue[EC2Instance]
+	RawDataDecoders["aws_volume"] = decodeValue[EBSVolume]
+	RawDataDecoders["aws_snapshot"] = decodeValue[EBSSnapshot]
+	RawDataDecoders["tilt_document"] = decodeValue[TILTDocument]
+}
+
+func decodeValue[T any](raw json.RawMessage) (interface{}, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+func decodePointer[T any](raw json.RawMessage) (interface{}, error) {
+	v := new(T)
+	err := json.Unmarshal(raw, v)
+	return v, err
+}
+
+func decodeGeneric(raw json.RawMessage) (interface{}, error) {
+	var v map[string]interface{}
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// EncodeRawData serializes raw data so it can be sent over the wire or written to disk.
+func EncodeRawData(data models.RawData) (EncodedRawData, error) {
+	encoded := make(EncodedRawData, len(data))
+	for key, items := range data {
+		list := make([]json.RawMessage, 0, len(items))
+		for _, item := range items {
+			raw, err := json.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding %s item: %v", key, err)
+			}
+			list = append(list, raw)
+		}
+		encoded[key] = list
+	}
+	return encoded, nil
+}
+
+// DecodeRawData restores raw data, items of known keys get their original Go type back.
+func DecodeRawData(encoded EncodedRawData) (models.RawData, error) {
+	data := make(models.RawData, len(encoded))
+	for key, list := range encoded {
+		decode, exists := RawDataDecoders[key]
+		if !exists {
+			decode = decodeGeneric
+		}
+		items := make([]interface{}, 0, len(list))
+		for _, raw := range list {
+			item, err := decode(raw)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding %s item: %v", key, err)
+			}
+			items = append(items, item)
+		}
+		data[key] = items
+	}
+	return data, nil
+}
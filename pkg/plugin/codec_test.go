@@ -0,0 +1,250 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	osModels "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// fill sets every exported field reachable from v, so a round trip checks
+// each field of a type instead of the few a hand-written sample would set.
+// Types with their own JSON encoding get a value valid in that encoding.
+func fill(v reflect.Value, name string, depth int) {
+	// Guards against recursive types, the registered ones nest less deep
+	if depth > 40 {
+		return
+	}
+	switch v.Addr().Interface().(type) {
+	case *metav1.Time:
+		// Kubernetes timestamps have second precision and decode as local time
+		v.Set(reflect.ValueOf(metav1.NewTime(time.Date(2024, time.March, 4, 10, 30, 0, 0, time.Local))))
+		return
+	case *metav1.MicroTime:
+		v.Set(reflect.ValueOf(metav1.NewMicroTime(time.Date(2024, time.March, 4, 10, 30, 0, 1000, time.Local))))
+		return
+	case *time.Time:
+		v.Set(reflect.ValueOf(time.Date(2024, time.March, 4, 10, 30, 0, 1, time.UTC)))
+		return
+	case *resource.Quantity:
+		v.Set(reflect.ValueOf(resource.MustParse("1536Mi")))
+		return
+	case *intstr.IntOrString:
+		v.Set(reflect.ValueOf(intstr.FromString(name)))
+		return
+	case *runtime.RawExtension:
+		v.Set(reflect.ValueOf(runtime.RawExtension{Raw: []byte(`{"name":"` + name + `"}`)}))
+		return
+	case *metav1.FieldsV1:
+		v.Set(reflect.ValueOf(metav1.FieldsV1{Raw: []byte(`{"f:metadata":{}}`)}))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(depth + 1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(depth + 1))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Interface:
+		// Untyped values decode as the JSON types, strings round-trip unchanged
+		v.Set(reflect.ValueOf(name))
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), name, depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), name, depth+1)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, name+"-key", depth+1)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value, name, depth+1)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fill(v.Field(i), name+"."+field.Name, depth+1)
+		}
+	}
+}
+
+// rawDataTypes are the types the transformers expect for each key
+var rawDataTypes = map[string]interface{}{
+	"os_project":        &osModels.ProjectDetails{},
+	"os_instance":       osModels.ServerDetails{},
+	"os_volume":         osModels.Volume{},
+	"os_snapshot":       osModels.Snapshot{},
+	"os_network":        NeutronNetwork{},
+	"os_subnet":         NeutronSubnet{},
+	"os_port":           NeutronPort{},
+	"os_router":         NeutronRouter{},
+	"os_floatingip":     NeutronFloatingIP{},
+	"os_security_group": NeutronSecurityGroup{},
+	"os_image":          GlanceImage{},
+	"os_flavor":         NovaFlavor{},
+	"os_container":      SwiftContainer{},
+	"k8s_node":          corev1.Node{},
+	"k8s_pod":           corev1.Pod{},
+	"k8s_pv":            corev1.PersistentVolume{},
+	"k8s_pvc":           corev1.PersistentVolumeClaim{},
+	"k8s_event":         ResourceEvent{},
+	"k8s_deployment":    appsv1.Deployment{},
+	"k8s_statefulset":   appsv1.StatefulSet{},
+	"k8s_daemonset":     appsv1.DaemonSet{},
+	"k8s_replicaset":    appsv1.ReplicaSet{},
+	"k8s_job":           batchv1.Job{},
+	"k8s_cronjob":       batchv1.CronJob{},
+	"k8s_namespace":     corev1.Namespace{},
+	"k8s_service":       corev1.Service{},
+	"k8s_ingress":       networkingv1.Ingress{},
+	"aws_account":       AWSAccount{},
+	"aws_instance":      EC2Instance{},
+	"aws_volume":        EBSVolume{},
+	"aws_snapshot":      EBSSnapshot{},
+	"tilt_document":     TILTDocument{},
+}
+
+// TestRawDataRoundTrip encodes a filled item of every registered key and
+// expects the decoded item to equal it, including its Go type
+func TestRawDataRoundTrip(t *testing.T) {
+	var keys []string
+	for key := range RawDataDecoders {
+		keys = append(keys, key)
+		if _, ok := rawDataTypes[key]; !ok {
+			t.Errorf("no type is expected for %s", key)
+		}
+	}
+	for key := range rawDataTypes {
+		if _, ok := RawDataDecoders[key]; !ok {
+			t.Errorf("no decoder is registered for %s", key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			typ := reflect.TypeOf(rawDataTypes[key])
+			if typ == nil {
+				t.Skip("no type is expected")
+			}
+			item := reflect.New(typ).Elem()
+			if typ.Kind() == reflect.Ptr {
+				item.Set(reflect.New(typ.Elem()))
+				fill(item.Elem(), key, 0)
+			} else {
+				fill(item, key, 0)
+			}
+
+			data := models.RawData{key: {item.Interface()}}
+			encoded, err := EncodeRawData(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Recordings are written to disk and read back as JSON
+			raw, err := json.Marshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var read EncodedRawData
+			if err := json.Unmarshal(raw, &read); err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeRawData(read)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, data) {
+				t.Errorf("%s doesn't round-trip: %s", key, difference(item, reflect.ValueOf(decoded[key][0]), typ.String()))
+			}
+		})
+	}
+}
+
+// difference returns the path of the first field in which want and got differ
+func difference(want, got reflect.Value, path string) string {
+	if want.Type() != got.Type() {
+		return fmt.Sprintf("%s: type %s, want %s", path, got.Type(), want.Type())
+	}
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				return fmt.Sprintf("%s: %v, want %v", path, got, want)
+			}
+			return ""
+		}
+		return difference(want.Elem(), got.Elem(), path)
+	case reflect.Slice:
+		if want.Len() != got.Len() {
+			return fmt.Sprintf("%s: %d items, want %d", path, got.Len(), want.Len())
+		}
+		for i := 0; i < want.Len(); i++ {
+			if d := difference(want.Index(i), got.Index(i), fmt.Sprintf("%s[%d]", path, i)); d != "" {
+				return d
+			}
+		}
+	case reflect.Map:
+		for _, key := range want.MapKeys() {
+			if !got.MapIndex(key).IsValid() {
+				return fmt.Sprintf("%s[%v]: missing", path, key)
+			}
+			if d := difference(want.MapIndex(key), got.MapIndex(key), fmt.Sprintf("%s[%v]", path, key)); d != "" {
+				return d
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			if !want.Type().Field(i).IsExported() {
+				continue
+			}
+			if d := difference(want.Field(i), got.Field(i), path+"."+want.Type().Field(i).Name); d != "" {
+				return d
+			}
+		}
+	}
+	if want.CanInterface() && !reflect.DeepEqual(want.Interface(), got.Interface()) {
+		return fmt.Sprintf("%s: %#v, want %#v", path, got.Interface(), want.Interface())
+	}
+	return ""
+}
+
+func TestDecodeRawDataGeneric(t *testing.T) {
+	encoded := EncodedRawData{"custom_key": {json.RawMessage(`{"id":"x-1","size":3,"tags":["a"]}`)}}
+	decoded, err := DecodeRawData(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.RawData{"custom_key": {map[string]interface{}{"id": "x-1", "size": float64(3), "tags": []interface{}{"a"}}}}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("DecodeRawData = %v, want %v", decoded, want)
+	}
+
+	_, err = DecodeRawData(EncodedRawData{"k8s_pod": {json.RawMessage(`{"metadata":"not an object"}`)}})
+	if err == nil || !strings.Contains(err.Error(), "k8s_pod") {
+		t.Errorf("DecodeRawData of a broken item = %v, want an error naming the key", err)
+	}
+}
@@ -0,0 +1,353 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+)
+
+// DefaultStartTimeout bounds the handshake and initialize call of a plugin
+// process unless its provider entry sets start_timeout.
+const DefaultStartTimeout = 30 * time.Second
+
+// ExternalPlugin serves the Plugin interface from a separate executable
+// speaking the wire protocol described in protocol.go.
+type ExternalPlugin struct {
+	Path         string
+	StartTimeout time.Duration
+
+	config map[string]interface{}
+	// maxLine overrides MaxLineSize when set
+	maxLine int
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Scanner
+	nextID  uint64
+
+	// process is the running process, Close kills it without holding mu
+	procMu  sync.Mutex
+	process *os.Process
+}
+
+// NewExternalPlugin returns a plugin backed by the executable at path.
+func NewExternalPlugin(path string) *ExternalPlugin {
+	return &ExternalPlugin{Path: path}
+}
+
+func (e *ExternalPlugin) Initialize(config map[string]interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.config = config
+	e.StartTimeout = parseDuration("start_timeout", config["start_timeout"])
+	return e.start(context.Background())
+}
+
+func (e *ExternalPlugin) FetchData() (models.RawData, error) {
+	return e.FetchDataContext(context.Background())
+}
+
+func (e *ExternalPlugin) FetchDataContext(ctx context.Context) (models.RawData, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// A previous call may have killed the process, start a fresh one
+	if e.cmd == nil {
+		if err := e.start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var encoded EncodedRawData
+	fetchErr := e.call(ctx, MethodFetchData, nil, &encoded)
+	if encoded == nil {
+		return nil, fetchErr
+	}
+	data, err := DecodeRawData(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return data, fetchErr
+}
+
+// Close asks the plugin process to exit. The process is killed if it isn't
+// gone within the start timeout, also when a call still running holds the
+// plugin by then.
+func (e *ExternalPlugin) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.startTimeout())
+	defer cancel()
+
+	locked := make(chan struct{})
+	go func() {
+		e.mu.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-ctx.Done():
+		// The running call returns once its process is gone
+		e.killProcess()
+		<-locked
+	}
+	defer e.mu.Unlock()
+
+	if e.cmd == nil {
+		return nil
+	}
+	_ = e.call(ctx, MethodShutdown, nil, nil)
+	if e.cmd == nil {
+		// The plugin failed to answer and was killed
+		return nil
+	}
+	e.stdin.Close()
+
+	exited := make(chan error, 1)
+	go func() { exited <- e.cmd.Wait() }()
+	var err error
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		e.killProcess()
+		<-exited
+		err = fmt.Errorf("plugin %s didn't exit after shutdown: %w", e.Path, ctx.Err())
+	}
+	e.setProcess(nil)
+	e.cmd = nil
+	return err
+}
+
+func (e *ExternalPlugin) startTimeout() time.Duration {
+	if e.StartTimeout <= 0 {
+		return DefaultStartTimeout
+	}
+	return e.StartTimeout
+}
+
+// start launches the executable, checks the handshake and initializes the
+// plugin. A process not done with both within the start timeout is killed.
+func (e *ExternalPlugin) start(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.startTimeout())
+	defer cancel()
+
+	cmd := exec.Command(e.Path)
+	cmd.Stderr = &stderrLog{plugin: e.id()}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("error creating stdin pipe for plugin %s: %v", e.Path, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error creating stdout pipe for plugin %s: %v", e.Path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting plugin %s: %v", e.Path, err)
+	}
+
+	maxLine := MaxLineSize
+	if e.maxLine > 0 {
+		maxLine = e.maxLine
+	}
+	scanner := bufio.NewScanner(stdout)
+	// Lines up to the larger of the buffer's capacity and maxLine are accepted
+	scanner.Buffer(make([]byte, 0, 4096), maxLine)
+
+	e.cmd, e.stdin, e.stdout = cmd, stdin, scanner
+	e.setProcess(cmd.Process)
+
+	line, err := e.readLine(ctx, "handshake")
+	if err != nil {
+		return err
+	}
+	var hs Handshake
+	if err := json.Unmarshal(line, &hs); err != nil || hs.Protocol != ProtocolName {
+		e.kill()
+		return fmt.Errorf("plugin %s sent an invalid handshake: %s", e.Path, line)
+	}
+	if hs.Version != ProtocolVersion {
+		e.kill()
+		return fmt.Errorf("plugin %s speaks protocol version %d, expected %d", e.Path, hs.Version, ProtocolVersion)
+	}
+
+	if err := e.call(ctx, MethodInitialize, e.config, nil); err != nil {
+		e.kill()
+		return err
+	}
+	return nil
+}
+
+// call sends a request and waits for the matching response. When ctx is done
+// first the process is killed, as the stream can no longer be trusted.
+func (e *ExternalPlugin) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	e.nextID++
+	req := Request{ID: e.nextID, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("error encoding %s params: %v", method, err)
+		}
+		req.Params = raw
+	}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := e.stdin.Write(append(line, '\n')); err != nil {
+		e.kill()
+		return fmt.Errorf("error sending %s to plugin %s: %v", method, e.Path, err)
+	}
+
+	line, err = e.readLine(ctx, method)
+	if err != nil {
+		return err
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		e.kill()
+		return fmt.Errorf("plugin %s sent an invalid %s response: %v", e.Path, method, err)
+	}
+	if resp.ID != req.ID {
+		e.kill()
+		return fmt.Errorf("plugin %s answered request %d with id %d", e.Path, req.ID, resp.ID)
+	}
+	// A failed call may still carry partial results
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("error decoding %s result of plugin %s: %v", method, e.Path, err)
+		}
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("plugin %s %s: %s", e.Path, method, resp.Error)
+	}
+	return nil
+}
+
+// readLine waits for the next line of the plugin. The process is killed when
+// it closes its output, exceeds the line limit or ctx is done first.
+func (e *ExternalPlugin) readLine(ctx context.Context, what string) ([]byte, error) {
+	done := make(chan error, 1)
+	go func() {
+		if !e.stdout.Scan() {
+			err := e.stdout.Err()
+			if err == nil {
+				err = io.EOF
+			}
+			done <- err
+			return
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			e.kill()
+			return nil, fmt.Errorf("plugin %s closed its output waiting for %s: %v", e.Path, what, err)
+		}
+		return e.stdout.Bytes(), nil
+	case <-ctx.Done():
+		e.kill()
+		<-done
+		return nil, fmt.Errorf("plugin %s %s: %w", e.Path, what, ctx.Err())
+	}
+}
+
+func (e *ExternalPlugin) kill() {
+	if e.cmd == nil {
+		return
+	}
+	_ = e.cmd.Process.Kill()
+	_ = e.cmd.Wait()
+	e.setProcess(nil)
+	e.cmd = nil
+}
+
+func (e *ExternalPlugin) setProcess(process *os.Process) {
+	e.procMu.Lock()
+	defer e.procMu.Unlock()
+	e.process = process
+}
+
+// killProcess kills the running process without waiting for mu, the call
+// holding it sees the closed output and cleans up.
+func (e *ExternalPlugin) killProcess() {
+	e.procMu.Lock()
+	defer e.procMu.Unlock()
+	if e.process != nil {
+		_ = e.process.Kill()
+	}
+}
+
+// id names the plugin in the log, by its provider instance if configured
+func (e *ExternalPlugin) id() string {
+	for _, key := range []string{"id", "name"} {
+		if id, ok := e.config[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return filepath.Base(e.Path)
+}
+
+// stderrLog logs every line a plugin writes to stderr with the plugin's id.
+type stderrLog struct {
+	plugin  string
+	partial []byte
+}
+
+func (l *stderrLog) Write(p []byte) (int, error) {
+	l.partial = append(l.partial, p...)
+	for {
+		i := strings.IndexByte(string(l.partial), '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimRight(string(l.partial[:i]), "\r"); line != "" {
+			logger.Info(line, logger.LogFields{"plugin": l.plugin, "stream": "stderr"})
+		}
+		l.partial = l.partial[i+1:]
+	}
+	// A line longer than the plugin protocol's is logged in parts
+	if len(l.partial) > 64*1024 {
+		logger.Info(string(l.partial), logger.LogFields{"plugin": l.plugin, "stream": "stderr"})
+		l.partial = nil
+	}
+	return len(p), nil
+}
+
+// DiscoverExternalPlugins registers a constructor for every executable in dir,
+// named after the file. In-tree plugins of the same name take precedence.
+func DiscoverExternalPlugins(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warning("Cannot read plugins directory", logger.LogFields{"dir": dir, "error": err.Error()})
+		}
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		name := entry.Name()
+		if _, exists := PluginConstructorRegistry[name]; exists {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		PluginConstructorRegistry[name] = func() Plugin {
+			return NewExternalPlugin(path)
+		}
+		logger.Info("Discovered external plugin", logger.LogFields{"name": name, "path": path})
+	}
+}
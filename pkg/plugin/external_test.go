@@ -0,0 +1,267 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+)
+
+// helperPlugin returns an executable running TestHelperProcess in the given
+// mode, the way a plugin executable is started: without arguments.
+func helperPlugin(t *testing.T, mode string) *ExternalPlugin {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin")
+	script := fmt.Sprintf("#!/bin/sh\nGO_WANT_HELPER_PROCESS=1 exec %q -test.run='^TestHelperProcess$' -- %s\n", os.Args[0], mode)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := NewExternalPlugin(path)
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// flavorPlugin serves the flavor named in its config, failing after it if asked to
+type flavorPlugin struct {
+	name string
+	fail bool
+}
+
+func (f *flavorPlugin) Initialize(config map[string]interface{}) error {
+	f.name, _ = config["flavor"].(string)
+	if f.name == "" {
+		return errors.New("flavor is missing")
+	}
+	f.fail, _ = config["fail"].(bool)
+	return nil
+}
+
+func (f *flavorPlugin) FetchData() (models.RawData, error) {
+	data := models.RawData{"os_flavor": {NovaFlavor{ID: "f-1", Name: f.name, VCPUs: 2}}}
+	if f.fail {
+		return data, errors.New("images unavailable")
+	}
+	return data, nil
+}
+
+// TestHelperProcess is the plugin process of the tests, it isn't a test itself
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	mode := os.Args[len(os.Args)-1]
+	handshake := func(version int) {
+		json.NewEncoder(os.Stdout).Encode(Handshake{Protocol: ProtocolName, Version: version})
+	}
+	in := bufio.NewScanner(os.Stdin)
+	// answer reads a request and answers it with line, %d is its id
+	answer := func(line string) {
+		if !in.Scan() {
+			os.Exit(0)
+		}
+		var req Request
+		json.Unmarshal(in.Bytes(), &req)
+		fmt.Printf(line+"\n", req.ID)
+	}
+
+	switch mode {
+	case "serve":
+		if err := Serve(&flavorPlugin{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "exit":
+		os.Exit(3)
+	case "silent":
+		time.Sleep(time.Minute)
+	case "bad-handshake":
+		fmt.Println("Starting collector...")
+	case "old-version":
+		handshake(0)
+	case "hang-initialize":
+		handshake(ProtocolVersion)
+		time.Sleep(time.Minute)
+	case "bad-json":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		answer(`{"id":%d,"result":`)
+	case "wrong-id":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		answer(`{"id":99%d}`)
+	case "oversized":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		answer(`{"id":%d,"result":{"os_flavor":[{"name":"` + strings.Repeat("x", 4096) + `"}]}}`)
+	case "exit-on-fetch":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		in.Scan()
+		os.Exit(2)
+	case "hang-fetch":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		time.Sleep(time.Minute)
+	case "ignore-shutdown":
+		handshake(ProtocolVersion)
+		answer(`{"id":%d}`)
+		for in.Scan() {
+		}
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func TestExternalPlugin(t *testing.T) {
+	p := helperPlugin(t, "serve")
+	if err := p.Initialize(map[string]interface{}{"flavor": "m1.small"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.RawData{"os_flavor": {NovaFlavor{ID: "f-1", Name: "m1.small", VCPUs: 2}}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("FetchData = %+v, want %+v", data, want)
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Close = %v", err)
+	}
+
+	// Partial data of a failed fetch is returned together with the error
+	p = helperPlugin(t, "serve")
+	if err := p.Initialize(map[string]interface{}{"flavor": "m1.small", "fail": true}); err != nil {
+		t.Fatal(err)
+	}
+	data, err = p.FetchData()
+	if err == nil || !strings.Contains(err.Error(), "images unavailable") {
+		t.Errorf("FetchData error = %v, want the error of the plugin", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("partial FetchData = %+v, want %+v", data, want)
+	}
+
+	// Initialize errors of the plugin are returned by Initialize
+	p = helperPlugin(t, "serve")
+	if err := p.Initialize(map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "flavor is missing") {
+		t.Errorf("Initialize = %v, want the error of the plugin", err)
+	}
+}
+
+func TestExternalPluginStartErrors(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "exit", want: "closed its output waiting for handshake"},
+		{mode: "bad-handshake", want: "invalid handshake: Starting collector..."},
+		{mode: "old-version", want: "speaks protocol version 0, expected 1"},
+		{mode: "silent", want: "handshake: context deadline exceeded"},
+		{mode: "hang-initialize", want: "initialize: context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			p := helperPlugin(t, tt.mode)
+			started := time.Now()
+			err := p.Initialize(map[string]interface{}{"start_timeout": "500ms"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Initialize = %v, want an error containing %q", err, tt.want)
+			}
+			if elapsed := time.Since(started); elapsed > 10*time.Second {
+				t.Errorf("Initialize took %s", elapsed)
+			}
+			if p.cmd != nil {
+				t.Errorf("the plugin process wasn't killed")
+			}
+		})
+	}
+}
+
+func TestExternalPluginFetchErrors(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "bad-json", want: "invalid fetch_data response"},
+		{mode: "wrong-id", want: "answered request 2 with id 992"},
+		{mode: "oversized", want: "token too long"},
+		{mode: "exit-on-fetch", want: "closed its output waiting for fetch_data: EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			p := helperPlugin(t, tt.mode)
+			p.maxLine = 1024
+			if err := p.Initialize(nil); err != nil {
+				t.Fatal(err)
+			}
+			data, err := p.FetchData()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("FetchData = %v, want an error containing %q", err, tt.want)
+			}
+			if data != nil {
+				t.Errorf("FetchData returned data: %v", data)
+			}
+			if p.cmd != nil {
+				t.Errorf("the plugin process wasn't killed")
+			}
+		})
+	}
+}
+
+// A fetch cancelled by the scan deadline kills the process, the next fetch starts a new one
+func TestExternalPluginFetchCancelled(t *testing.T) {
+	p := helperPlugin(t, "hang-initialize")
+	p.StartTimeout = 200 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.FetchDataContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchDataContext = %v, want context.Canceled", err)
+	}
+	if _, err := p.FetchData(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchData of a hanging plugin = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// Close kills a plugin that doesn't exit in time, also while a fetch holds it
+func TestExternalPluginCloseHanging(t *testing.T) {
+	for _, mode := range []string{"hang-fetch", "ignore-shutdown"} {
+		t.Run(mode, func(t *testing.T) {
+			p := helperPlugin(t, mode)
+			if err := p.Initialize(map[string]interface{}{"start_timeout": "500ms"}); err != nil {
+				t.Fatal(err)
+			}
+			fetched := make(chan error, 1)
+			if mode == "hang-fetch" {
+				go func() {
+					_, err := p.FetchData()
+					fetched <- err
+				}()
+				// Let the fetch take the plugin first
+				time.Sleep(100 * time.Millisecond)
+			}
+			started := time.Now()
+			p.Close()
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("Close took %s", elapsed)
+			}
+			if p.cmd != nil {
+				t.Errorf("the plugin process wasn't killed")
+			}
+			if mode == "hang-fetch" {
+				if err := <-fetched; err == nil || !strings.Contains(err.Error(), "closed its output waiting for fetch_data") {
+					t.Errorf("FetchData = %v, want the killed process reported", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	kubernetesServices "github.com/regulatory-transparency-monitor/kubernetes-provider-plugin/pkg/services"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KubernetesPlugin lists the nodes, pods and persistent volumes of a cluster,
+// the workload controllers owning the pods, the namespaces, services and
+// ingresses around them and the claims of the persistent volumes. The
+// kubernetes provider plugin it extends only sets up the client, its own
+// listing ignores the namespaces.
+type KubernetesPlugin struct {
+	*kubernetesServices.KubernetesPlugin
+	// Clientset is the client set up by the kubernetes provider plugin
+	Clientset kubernetes.Interface
+	// Namespace limits the listed resources, empty means all namespaces
+	Namespace string
+	// Scope limits the listed pods, controllers, services, ingresses and
+	// claims to the namespaces selected by the filters, and the persistent
+	// volumes to those claimed from them
+	Scope Scope
+}
+
+func NewKubernetesPlugin() *KubernetesPlugin {
+	return &KubernetesPlugin{KubernetesPlugin: &kubernetesServices.KubernetesPlugin{}}
+}
+
+func (k *KubernetesPlugin) Initialize(config map[string]interface{}) error {
+	k.Namespace, _ = config["namespace"].(string)
+	k.Scope = ParseScope(config)
+	if err := k.KubernetesPlugin.Initialize(config); err != nil {
+		return err
+	}
+	k.Clientset = k.KubernetesPlugin.Clientset
+	return nil
+}
+
+func (k *KubernetesPlugin) FetchData() (models.RawData, error) {
+	return k.FetchDataContext(context.Background())
+}
+
+func (k *KubernetesPlugin) FetchDataContext(ctx context.Context) (models.RawData, error) {
+	data := make(models.RawData)
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return data, fmt.Errorf("error listing nodes: %v", err)
+	}
+	for _, n := range nodes.Items {
+		data["k8s_node"] = append(data["k8s_node"], n)
+	}
+
+	opts := k.Scope.listOptions()
+	namespaces := k.Scope.namespaces(k.Namespace)
+	pvs, err := k.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return data, fmt.Errorf("error listing persistentvolumes: %v", err)
+	}
+	for _, pv := range pvs.Items {
+		if k.Scope.selectsVolume(pv, namespaces) {
+			data["k8s_pv"] = append(data["k8s_pv"], pv)
+		}
+	}
+
+	for _, namespace := range namespaces {
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return data, fmt.Errorf("error listing pods: %v", err)
+		}
+		for _, p := range pods.Items {
+			data["k8s_pod"] = append(data["k8s_pod"], p)
+		}
+		workloads, err := listWorkloads(ctx, k.Clientset, namespace, opts)
+		for key, items := range workloads {
+			data[key] = append(data[key], items...)
+		}
+		if err != nil {
+			return data, err
+		}
+		exposure, err := listExposure(ctx, k.Clientset, namespace, opts)
+		for key, items := range exposure {
+			data[key] = append(data[key], items...)
+		}
+		if err != nil {
+			return data, err
+		}
+		claims, err := k.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		if err != nil {
+			return data, fmt.Errorf("error listing persistentvolumeclaims: %v", err)
+		}
+		for _, c := range claims.Items {
+			data["k8s_pvc"] = append(data["k8s_pvc"], c)
+		}
+	}
+	return data, nil
+}
+
+// Reuse leaves out the pods terminating at the time of the fetch.
+func (k *KubernetesPlugin) Reuse(data models.RawData) models.RawData {
+	return reuseKubernetesData(data)
+}
+
+// reuseKubernetesData returns the Kubernetes data of a fetch to store again in
+// a later version. Changes observed up to the fetch, the watch plugin's
+// events and deleted pods, were stored with the version of the fetch and are
+// left out, as are pods terminating at the time.
+func reuseKubernetesData(data models.RawData) models.RawData {
+	reused := make(models.RawData, len(data))
+	for key, items := range data {
+		switch key {
+		case "k8s_event":
+			continue
+		case "k8s_pod":
+			var pods []interface{}
+			for _, item := range items {
+				if pod, ok := item.(corev1.Pod); ok && pod.DeletionTimestamp != nil {
+					continue
+				}
+				pods = append(pods, item)
+			}
+			reused[key] = pods
+		default:
+			reused[key] = items
+		}
+	}
+	return reused
+}
+
+// listWorkloads lists the workload controllers of a namespace under the
+// k8s_deployment, k8s_statefulset, k8s_daemonset, k8s_replicaset, k8s_job and
+// k8s_cronjob keys.
+func listWorkloads(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) (models.RawData, error) {
+	data := make(models.RawData)
+	apps := clientset.AppsV1()
+	batch := clientset.BatchV1()
+
+	deployments, err := apps.Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing deployments: %v", err)
+	}
+	for _, d := range deployments.Items {
+		data["k8s_deployment"] = append(data["k8s_deployment"], d)
+	}
+
+	statefulSets, err := apps.StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing statefulsets: %v", err)
+	}
+	for _, s := range statefulSets.Items {
+		data["k8s_statefulset"] = append(data["k8s_statefulset"], s)
+	}
+
+	daemonSets, err := apps.DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing daemonsets: %v", err)
+	}
+	for _, d := range daemonSets.Items {
+		data["k8s_daemonset"] = append(data["k8s_daemonset"], d)
+	}
+
+	replicaSets, err := apps.ReplicaSets(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing replicasets: %v", err)
+	}
+	for _, r := range replicaSets.Items {
+		data["k8s_replicaset"] = append(data["k8s_replicaset"], r)
+	}
+
+	jobs, err := batch.Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing jobs: %v", err)
+	}
+	for _, j := range jobs.Items {
+		data["k8s_job"] = append(data["k8s_job"], j)
+	}
+
+	cronJobs, err := batch.CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing cronjobs: %v", err)
+	}
+	for _, c := range cronJobs.Items {
+		data["k8s_cronjob"] = append(data["k8s_cronjob"], c)
+	}
+
+	return data, nil
+}
+
+// listExposure lists the namespaces, services and ingresses of a namespace
+// under the k8s_namespace, k8s_service and k8s_ingress keys.
+func listExposure(ctx context.Context, clientset kubernetes.Interface, namespace string, opts metav1.ListOptions) (models.RawData, error) {
+	data := make(models.RawData)
+	core := clientset.CoreV1()
+
+	// Listing namespaces needs cluster-wide access, a namespaced scan only gets its own
+	if namespace != "" {
+		ns, err := core.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			return data, fmt.Errorf("error getting namespace %s: %v", namespace, err)
+		}
+		data["k8s_namespace"] = append(data["k8s_namespace"], *ns)
+	} else {
+		// Namespaces aren't namespaced, opts doesn't apply
+		namespaces, err := core.Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return data, fmt.Errorf("error listing namespaces: %v", err)
+		}
+		for _, n := range namespaces.Items {
+			data["k8s_namespace"] = append(data["k8s_namespace"], n)
+		}
+	}
+
+	services, err := core.Services(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing services: %v", err)
+	}
+	for _, s := range services.Items {
+		data["k8s_service"] = append(data["k8s_service"], s)
+	}
+
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+	if err != nil {
+		return data, fmt.Errorf("error listing ingresses: %v", err)
+	}
+	for _, i := range ingresses.Items {
+		data["k8s_ingress"] = append(data["k8s_ingress"], i)
+	}
+
+	return data, nil
+}
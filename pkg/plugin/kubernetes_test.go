@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	kubernetesServices "github.com/regulatory-transparency-monitor/kubernetes-provider-plugin/pkg/services"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// scopedCluster holds the resources of two tenants and a system namespace
+func scopedCluster() []runtime.Object {
+	var objects []runtime.Object
+	for _, namespace := range []string{"shop", "billing", "kube-system"} {
+		objects = append(objects,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: namespace + "-pod"}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: namespace + "-data"}},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: namespace + "-pv"},
+				Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: namespace, Name: namespace + "-data"}},
+			},
+		)
+	}
+	return append(objects,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "unclaimed-pv"}},
+	)
+}
+
+// objectNames returns the sorted names of the Kubernetes objects of a key
+func objectNames(data models.RawData, key string) []string {
+	var names []string
+	for _, item := range data[key] {
+		value := reflect.ValueOf(item)
+		meta, ok := value.FieldByName("ObjectMeta").Interface().(metav1.ObjectMeta)
+		if !ok {
+			continue
+		}
+		names = append(names, meta.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scopeTests are the objects of scopedCluster listed per key by the
+// Kubernetes plugins for a namespace and scope
+var scopeTests = []struct {
+	name      string
+	namespace string
+	scope     Scope
+	want      map[string][]string
+}{
+	{
+		name: "all namespaces",
+		want: map[string][]string{
+			"k8s_node": {"worker-1"},
+			"k8s_pod":  {"billing-pod", "kube-system-pod", "shop-pod"},
+			"k8s_pv":   {"billing-pv", "kube-system-pv", "shop-pv", "unclaimed-pv"},
+			"k8s_pvc":  {"billing-data", "kube-system-data", "shop-data"},
+		},
+	},
+	{
+		name:  "selected namespaces",
+		scope: Scope{Namespaces: []string{"shop", "billing"}},
+		want: map[string][]string{
+			"k8s_node":      {"worker-1"},
+			"k8s_pod":       {"billing-pod", "shop-pod"},
+			"k8s_pv":        {"billing-pv", "shop-pv", "unclaimed-pv"},
+			"k8s_pvc":       {"billing-data", "shop-data"},
+			"k8s_namespace": {"billing", "shop"},
+		},
+	},
+	{
+		name:      "configured namespace",
+		namespace: "shop",
+		scope:     Scope{Namespaces: []string{"billing"}},
+		want: map[string][]string{
+			"k8s_pod": {"shop-pod"},
+			"k8s_pv":  {"shop-pv", "unclaimed-pv"},
+			"k8s_pvc": {"shop-data"},
+		},
+	},
+	{
+		// The fake clientset ignores field selectors, only the claims of
+		// the persistent volumes are checked here
+		name:  "excluded namespace",
+		scope: Scope{ExcludeNamespaces: []string{"kube-system"}},
+		want: map[string][]string{
+			"k8s_pv": {"billing-pv", "shop-pv", "unclaimed-pv"},
+		},
+	},
+}
+
+func TestKubernetesPluginScope(t *testing.T) {
+	for _, tt := range scopeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &KubernetesPlugin{
+				KubernetesPlugin: &kubernetesServices.KubernetesPlugin{},
+				Clientset:        fake.NewSimpleClientset(scopedCluster()...),
+				Namespace:        tt.namespace,
+				Scope:            tt.scope,
+			}
+			data, err := k.FetchDataContext(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				if got := objectNames(data, key); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScopeListOptions(t *testing.T) {
+	if got := (Scope{}).listOptions().FieldSelector; got != "" {
+		t.Errorf("unscoped field selector = %q", got)
+	}
+	scope := Scope{ExcludeNamespaces: []string{"kube-system", "monitoring"}}
+	if got, want := scope.listOptions().FieldSelector, "metadata.namespace!=kube-system,metadata.namespace!=monitoring"; got != want {
+		t.Errorf("field selector = %q, want %q", got, want)
+	}
+}
+
+func TestPluginManagerReused(t *testing.T) {
+	deleted := metav1.Now()
+	data := models.RawData{
+		"k8s_pod": {
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "old", DeletionTimestamp: &deleted}},
+		},
+		"k8s_event": {ResourceEvent{Kind: "Pod", Action: EventDeleted, Name: "old"}},
+		"k8s_node":  {corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}},
+	}
+	pm := NewPluginManager()
+	pm.ActivePlugins["cluster"] = &KubernetesWatchPlugin{}
+	pm.ActivePlugins["collector"] = &ExternalPlugin{}
+	pm.ActivePlugins["recorded"] = NewRecordingPlugin(&KubernetesWatchPlugin{}, "recorded", t.TempDir())
+	pm.ActivePlugins["recorded-collector"] = NewRecordingPlugin(&ExternalPlugin{}, "recorded-collector", t.TempDir())
+
+	reused := pm.Reused(ProviderData{Provider: "cluster", Data: data}).Data
+	if _, ok := reused["k8s_event"]; ok {
+		t.Errorf("events were reused")
+	}
+	if got := objectNames(reused, "k8s_pod"); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("reused pods = %v, want the running one", got)
+	}
+	if got := objectNames(reused, "k8s_node"); !reflect.DeepEqual(got, []string{"worker-1"}) {
+		t.Errorf("reused nodes = %v", got)
+	}
+	if len(data["k8s_pod"]) != 2 {
+		t.Errorf("the fetched data was changed")
+	}
+
+	// Plugins that don't implement ReusingPlugin are stored unchanged
+	if other := pm.Reused(ProviderData{Provider: "collector", Data: data}).Data; !reflect.DeepEqual(other, data) {
+		t.Errorf("data of another plugin was changed: %v", other)
+	}
+
+	// Recording a provider passes the wrapped plugin's Reuse through
+	recorded := pm.Reused(ProviderData{Provider: "recorded", Data: data}).Data
+	if !reflect.DeepEqual(recorded, reused) {
+		t.Errorf("reused data of a recorded plugin = %v, want %v", recorded, reused)
+	}
+	if other := pm.Reused(ProviderData{Provider: "recorded-collector", Data: data}).Data; !reflect.DeepEqual(other, data) {
+		t.Errorf("data of a recorded plugin without Reuse was changed: %v", other)
+	}
+}
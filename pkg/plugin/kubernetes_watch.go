@@ -0,0 +1,491 @@
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Actions of a ResourceEvent
+const (
+	EventAdded    = "ADDED"
+	EventModified = "MODIFIED"
+	EventDeleted  = "DELETED"
+)
+
+// ResourceEvent is a change of a watched Kubernetes object observed between two scans.
+type ResourceEvent struct {
+	Kind       string    `json:"kind"`
+	Action     string    `json:"action"`
+	UID        string    `json:"uid"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// KubernetesWatchPlugin keeps a live cache of nodes, pods, persistent volumes,
+// workload controllers, namespaces, services and ingresses using informers.
+// Every scan takes a snapshot of that cache together with the changes observed
+// since the previous scan, so pods that lived only between two scans are still
+// reported.
+type KubernetesWatchPlugin struct {
+	Clientset kubernetes.Interface
+	// MaxEvents bounds the events and the deleted pods buffered between two
+	// scans, the oldest are dropped first
+	MaxEvents int
+	// Namespace limits the watched resources, empty means all namespaces
+	Namespace string
+	// Scope limits the watched namespaced resources to the namespaces
+	// selected by the filters, like it limits the KubernetesPlugin lists
+	Scope Scope
+
+	// cluster watches nodes, persistent volumes and namespaces, namespaced
+	// holds a factory per listed namespace
+	cluster    informers.SharedInformerFactory
+	namespaced []informers.SharedInformerFactory
+	stopCh     chan struct{}
+
+	mu          sync.Mutex
+	events      []ResourceEvent
+	deletedPods map[types.UID]corev1.Pod
+	// deletedOrder holds the UIDs of deletedPods in deletion order
+	deletedOrder []types.UID
+}
+
+func (k *KubernetesWatchPlugin) Initialize(config map[string]interface{}) error {
+	apiAccess, ok := config["api_access"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("kubernetes api_access configuration is missing or invalid")
+	}
+	kubeURL, ok := apiAccess["base_url"].(string)
+	if !ok || kubeURL == "" {
+		return fmt.Errorf("kubernetes kubeURL configuration is missing or invalid")
+	}
+	token, ok := apiAccess["token"].(string)
+	if !ok || token == "" {
+		return fmt.Errorf("kubernetes token configuration is missing or invalid")
+	}
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{
+		Host: kubeURL,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: true,
+		},
+		BearerToken: token,
+	})
+	if err != nil {
+		return err
+	}
+	k.Clientset = clientset
+
+	k.MaxEvents = 10000
+	if max, ok := config["max_events"].(int); ok && max > 0 {
+		k.MaxEvents = max
+	}
+	syncTimeout := time.Minute
+	if s, ok := config["sync_timeout"].(string); ok && s != "" {
+		if syncTimeout, err = time.ParseDuration(s); err != nil {
+			return fmt.Errorf("kubernetes sync_timeout is invalid: %v", err)
+		}
+	}
+
+	k.Namespace, _ = config["namespace"].(string)
+	k.Scope = ParseScope(config)
+
+	return k.start(syncTimeout)
+}
+
+// start registers the event handlers and blocks until the initial list is cached.
+func (k *KubernetesWatchPlugin) start(syncTimeout time.Duration) error {
+	k.deletedPods, k.deletedOrder = make(map[types.UID]corev1.Pod), nil
+	k.stopCh = make(chan struct{})
+
+	// Resync is disabled, the cache is kept current by the watches alone
+	k.cluster = informers.NewSharedInformerFactory(k.Clientset, 0)
+	k.namespaced = nil
+	opts := k.Scope.listOptions()
+	for _, namespace := range k.Scope.namespaces(k.Namespace) {
+		k.namespaced = append(k.namespaced, informers.NewSharedInformerFactoryWithOptions(k.Clientset, 0,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(func(o *metav1.ListOptions) { o.FieldSelector = opts.FieldSelector }),
+		))
+	}
+
+	core := k.cluster.Core().V1()
+	handlers := map[cache.SharedIndexInformer]string{
+		core.Nodes().Informer():             "Node",
+		core.PersistentVolumes().Informer(): "PersistentVolume",
+		core.Namespaces().Informer():        "Namespace",
+	}
+	for _, factory := range k.namespaced {
+		core := factory.Core().V1()
+		apps := factory.Apps().V1()
+		batch := factory.Batch().V1()
+		handlers[core.Pods().Informer()] = "Pod"
+		handlers[core.PersistentVolumeClaims().Informer()] = "PersistentVolumeClaim"
+		handlers[apps.Deployments().Informer()] = "Deployment"
+		handlers[apps.StatefulSets().Informer()] = "StatefulSet"
+		handlers[apps.DaemonSets().Informer()] = "DaemonSet"
+		handlers[apps.ReplicaSets().Informer()] = "ReplicaSet"
+		handlers[batch.Jobs().Informer()] = "Job"
+		handlers[batch.CronJobs().Informer()] = "CronJob"
+		handlers[core.Services().Informer()] = "Service"
+		handlers[factory.Networking().V1().Ingresses().Informer()] = "Ingress"
+	}
+	var registrations []cache.InformerSynced
+	for informer, kind := range handlers {
+		registration, err := informer.AddEventHandler(k.eventHandler(kind))
+		if err != nil {
+			return fmt.Errorf("error adding %s event handler: %v", kind, err)
+		}
+		registrations = append(registrations, registration.HasSynced)
+	}
+
+	factories := append([]informers.SharedInformerFactory{k.cluster}, k.namespaced...)
+	for _, factory := range factories {
+		factory.Start(k.stopCh)
+	}
+
+	// Waiting is abandoned after syncTimeout. The watches are only stopped
+	// if a cache isn't synced by then, a sync completing while the timer
+	// fires keeps them running.
+	syncCh := make(chan struct{})
+	timeout := time.AfterFunc(syncTimeout, func() { close(syncCh) })
+	defer timeout.Stop()
+	synced := make(map[reflect.Type]bool)
+	for _, factory := range factories {
+		for informerType, ok := range factory.WaitForCacheSync(syncCh) {
+			synced[informerType] = ok
+		}
+	}
+	// The handlers are notified after the caches are synced, wait until
+	// they have seen the initial list as well
+	handled := cache.WaitForCacheSync(syncCh, registrations...)
+	for informerType, ok := range synced {
+		if !ok {
+			k.Close()
+			return fmt.Errorf("kubernetes cache for %v not synced within %s", informerType, syncTimeout)
+		}
+	}
+	if !handled {
+		k.Close()
+		return fmt.Errorf("kubernetes event handlers not synced within %s", syncTimeout)
+	}
+	return nil
+}
+
+// FetchData returns a consistent snapshot of the cache and the changes since the last call.
+func (k *KubernetesWatchPlugin) FetchData() (models.RawData, error) {
+	core := k.cluster.Core().V1()
+	namespaces := k.Scope.namespaces(k.Namespace)
+
+	// The events are taken before the cache is listed. The informers update
+	// the cache before notifying the handlers, so every change an event
+	// refers to is part of the snapshot. Changes listed whose events arrive
+	// later are reported with the next scan.
+	k.mu.Lock()
+	events, deletedPods, deletedOrder := k.events, k.deletedPods, k.deletedOrder
+	k.events, k.deletedPods, k.deletedOrder = nil, make(map[types.UID]corev1.Pod), nil
+	k.mu.Unlock()
+
+	nodes, err := core.Nodes().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pvs, err := core.PersistentVolumes().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var pods []*corev1.Pod
+	var pvcs []*corev1.PersistentVolumeClaim
+	for _, factory := range k.namespaced {
+		namespacePods, err := factory.Core().V1().Pods().Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, namespacePods...)
+		namespacePVCs, err := factory.Core().V1().PersistentVolumeClaims().Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		pvcs = append(pvcs, namespacePVCs...)
+	}
+	workloads, err := k.workloads()
+	if err != nil {
+		return nil, err
+	}
+	exposure, err := k.exposure(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(models.RawData)
+	data["k8s_node"] = make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		data["k8s_node"] = append(data["k8s_node"], *node.DeepCopy())
+	}
+	data["k8s_pv"] = make([]interface{}, 0, len(pvs))
+	for _, pv := range pvs {
+		if k.Scope.selectsVolume(*pv, namespaces) {
+			data["k8s_pv"] = append(data["k8s_pv"], *pv.DeepCopy())
+		}
+	}
+	data["k8s_pvc"] = make([]interface{}, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		data["k8s_pvc"] = append(data["k8s_pvc"], *pvc.DeepCopy())
+	}
+	data["k8s_pod"] = make([]interface{}, 0, len(pods)+len(deletedPods))
+	for _, pod := range pods {
+		data["k8s_pod"] = append(data["k8s_pod"], *pod.DeepCopy())
+	}
+	for _, uid := range deletedOrder {
+		data["k8s_pod"] = append(data["k8s_pod"], deletedPods[uid])
+	}
+	for key, items := range workloads {
+		data[key] = items
+	}
+	for key, items := range exposure {
+		data[key] = items
+	}
+	data["k8s_event"] = make([]interface{}, 0, len(events))
+	for _, event := range events {
+		data["k8s_event"] = append(data["k8s_event"], event)
+	}
+
+	return data, nil
+}
+
+// Reuse leaves out the events and deleted pods, they were stored with the
+// version of the fetch that took them.
+func (k *KubernetesWatchPlugin) Reuse(data models.RawData) models.RawData {
+	return reuseKubernetesData(data)
+}
+
+// workloads lists the cached workload controllers like listWorkloads does.
+func (k *KubernetesWatchPlugin) workloads() (models.RawData, error) {
+	data := make(models.RawData)
+	for _, factory := range k.namespaced {
+		if err := namespaceWorkloads(factory, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// namespaceWorkloads adds the workload controllers cached by a factory to data.
+func namespaceWorkloads(factory informers.SharedInformerFactory, data models.RawData) error {
+	apps := factory.Apps().V1()
+	batch := factory.Batch().V1()
+
+	deployments, err := apps.Deployments().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, d := range deployments {
+		data["k8s_deployment"] = append(data["k8s_deployment"], *d.DeepCopy())
+	}
+	statefulSets, err := apps.StatefulSets().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, s := range statefulSets {
+		data["k8s_statefulset"] = append(data["k8s_statefulset"], *s.DeepCopy())
+	}
+	daemonSets, err := apps.DaemonSets().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, d := range daemonSets {
+		data["k8s_daemonset"] = append(data["k8s_daemonset"], *d.DeepCopy())
+	}
+	replicaSets, err := apps.ReplicaSets().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, r := range replicaSets {
+		data["k8s_replicaset"] = append(data["k8s_replicaset"], *r.DeepCopy())
+	}
+	jobs, err := batch.Jobs().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		data["k8s_job"] = append(data["k8s_job"], *j.DeepCopy())
+	}
+	cronJobs, err := batch.CronJobs().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, c := range cronJobs {
+		data["k8s_cronjob"] = append(data["k8s_cronjob"], *c.DeepCopy())
+	}
+	return nil
+}
+
+// exposure lists the cached namespaces, services and ingresses like listExposure does.
+func (k *KubernetesWatchPlugin) exposure(selected []string) (models.RawData, error) {
+	data := make(models.RawData)
+
+	namespaces, err := k.cluster.Core().V1().Namespaces().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range namespaces {
+		if k.Scope.selectsNamespace(n.Name, selected) {
+			data["k8s_namespace"] = append(data["k8s_namespace"], *n.DeepCopy())
+		}
+	}
+	for _, factory := range k.namespaced {
+		services, err := factory.Core().V1().Services().Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range services {
+			data["k8s_service"] = append(data["k8s_service"], *s.DeepCopy())
+		}
+		ingresses, err := factory.Networking().V1().Ingresses().Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range ingresses {
+			data["k8s_ingress"] = append(data["k8s_ingress"], *i.DeepCopy())
+		}
+	}
+	return data, nil
+}
+
+// Close stops all watches.
+func (k *KubernetesWatchPlugin) Close() error {
+	select {
+	case <-k.stopCh:
+	default:
+		close(k.stopCh)
+	}
+	k.shutdown()
+	return nil
+}
+
+// shutdown waits for the informers of all factories to stop.
+func (k *KubernetesWatchPlugin) shutdown() {
+	k.cluster.Shutdown()
+	for _, factory := range k.namespaced {
+		factory.Shutdown()
+	}
+}
+
+func (k *KubernetesWatchPlugin) eventHandler(kind string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			// The initial list is part of the first snapshot, not a change
+			if !isInInitialList {
+				k.record(kind, EventAdded, obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if changed(oldObj, newObj) {
+				k.record(kind, EventModified, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			k.record(kind, EventDeleted, obj)
+		},
+	}
+}
+
+func (k *KubernetesWatchPlugin) record(kind string, action string, obj interface{}) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		logger.Warning("Unexpected object in watch event", logger.LogFields{"kind": kind, "type": fmt.Sprintf("%T", obj)})
+		return
+	}
+	now := time.Now().UTC()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.events = append(k.events, ResourceEvent{
+		Kind:       kind,
+		Action:     action,
+		UID:        string(meta.GetUID()),
+		Namespace:  meta.GetNamespace(),
+		Name:       meta.GetName(),
+		ObservedAt: now,
+	})
+	if len(k.events) > k.MaxEvents {
+		k.events = k.events[len(k.events)-k.MaxEvents:]
+	}
+
+	// Keep deleted pods until the next scan, they may have processed personal data
+	if pod, ok := obj.(*corev1.Pod); ok && action == EventDeleted {
+		deleted := *pod.DeepCopy()
+		if deleted.DeletionTimestamp == nil {
+			deleted.DeletionTimestamp = &metav1.Time{Time: now}
+		}
+		if _, ok := k.deletedPods[deleted.UID]; !ok {
+			k.deletedOrder = append(k.deletedOrder, deleted.UID)
+		}
+		k.deletedPods[deleted.UID] = deleted
+		for len(k.deletedOrder) > k.MaxEvents {
+			delete(k.deletedPods, k.deletedOrder[0])
+			k.deletedOrder = k.deletedOrder[1:]
+		}
+	}
+}
+
+// changed filters out updates that don't matter for the graph, like node heartbeats.
+func changed(oldObj, newObj interface{}) bool {
+	switch o := oldObj.(type) {
+	case *corev1.Node:
+		n := newObj.(*corev1.Node)
+		return o.Generation != n.Generation || !labels.Equals(o.Labels, n.Labels)
+	case *corev1.Pod:
+		n := newObj.(*corev1.Pod)
+		return o.Generation != n.Generation || o.Status.Phase != n.Status.Phase || o.Spec.NodeName != n.Spec.NodeName ||
+			!labels.Equals(o.Labels, n.Labels) || o.Annotations["has_pd"] != n.Annotations["has_pd"]
+	case *corev1.PersistentVolume:
+		n := newObj.(*corev1.PersistentVolume)
+		return o.Generation != n.Generation || o.Status.Phase != n.Status.Phase || claimUID(o) != claimUID(n)
+	case *corev1.Namespace:
+		n := newObj.(*corev1.Namespace)
+		return o.Status.Phase != n.Status.Phase || !labels.Equals(o.Labels, n.Labels) || o.Annotations["has_pd"] != n.Annotations["has_pd"]
+	case *corev1.PersistentVolumeClaim:
+		n := newObj.(*corev1.PersistentVolumeClaim)
+		return o.Status.Phase != n.Status.Phase || o.Spec.VolumeName != n.Spec.VolumeName || o.Annotations["has_pd"] != n.Annotations["has_pd"]
+	case *corev1.Service:
+		// Services have no generation, their spec is compared instead
+		n := newObj.(*corev1.Service)
+		return !reflect.DeepEqual(o.Spec, n.Spec) || !labels.Equals(o.Labels, n.Labels)
+	default:
+		// Controllers update their status with every pod they manage, only
+		// spec, label and has_pd changes are recorded
+		oldMeta, ok := oldObj.(metav1.Object)
+		newMeta, newOk := newObj.(metav1.Object)
+		if !ok || !newOk {
+			return true
+		}
+		return oldMeta.GetGeneration() != newMeta.GetGeneration() || !labels.Equals(oldMeta.GetLabels(), newMeta.GetLabels()) ||
+			oldMeta.GetAnnotations()["has_pd"] != newMeta.GetAnnotations()["has_pd"]
+	}
+}
+
+func claimUID(pv *corev1.PersistentVolume) types.UID {
+	if pv.Spec.ClaimRef == nil {
+		return ""
+	}
+	return pv.Spec.ClaimRef.UID
+}
@@ -0,0 +1,240 @@
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// startWatch starts a watch plugin on a fake cluster holding objects
+func startWatch(t *testing.T, k *KubernetesWatchPlugin) {
+	t.Helper()
+	if k.MaxEvents == 0 {
+		k.MaxEvents = 100
+	}
+	if err := k.start(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { k.Close() })
+}
+
+// waitForEvents waits until n events are buffered and returns them
+func waitForEvents(t *testing.T, k *KubernetesWatchPlugin, n int) []ResourceEvent {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		k.mu.Lock()
+		events := append([]ResourceEvent(nil), k.events...)
+		k.mu.Unlock()
+		if len(events) >= n {
+			return events
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got events %+v, want %d", events, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// eventActions returns the actions of the events as "Kind ACTION name"
+func eventActions(events []ResourceEvent) []string {
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Kind+" "+e.Action+" "+e.Name)
+	}
+	return actions
+}
+
+func TestKubernetesWatchPlugin(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", UID: "web-uid"}},
+	)
+	k := &KubernetesWatchPlugin{Clientset: clientset}
+	startWatch(t, k)
+
+	// The initial list is a snapshot, not a change
+	data, err := k.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := objectNames(data, "k8s_pod"); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("pods = %v, want the listed one", got)
+	}
+	if got := objectNames(data, "k8s_node"); !reflect.DeepEqual(got, []string{"worker-1"}) {
+		t.Errorf("nodes = %v", got)
+	}
+	if len(data["k8s_event"]) != 0 {
+		t.Errorf("events of the initial list: %v", data["k8s_event"])
+	}
+
+	ctx := context.Background()
+	pods := clientset.CoreV1().Pods("shop")
+	job := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "job", UID: "job-uid"}}
+	if _, err := pods.Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvents(t, k, 1)
+
+	// Updates the graph doesn't show are left out
+	web, err := pods.Get(ctx, "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	web.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+	if web, err = pods.Update(ctx, web, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	web.Labels = map[string]string{"app": "web"}
+	if web, err = pods.Update(ctx, web, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvents(t, k, 2)
+	web.Annotations["has_pd"] = `{"dataCategories":[{"name":"health"}]}`
+	if _, err = pods.Update(ctx, web, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvents(t, k, 3)
+
+	if err := pods.Delete(ctx, "job", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvents(t, k, 4)
+
+	data, err = k.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []ResourceEvent
+	for _, item := range data["k8s_event"] {
+		events = append(events, item.(ResourceEvent))
+	}
+	want := []string{"Pod ADDED job", "Pod MODIFIED web", "Pod MODIFIED web", "Pod DELETED job"}
+	if got := eventActions(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	// The deleted pod is reported once more, marked deleted
+	if got := objectNames(data, "k8s_pod"); !reflect.DeepEqual(got, []string{"job", "web"}) {
+		t.Errorf("pods = %v, want the running and the deleted one", got)
+	}
+	for _, item := range data["k8s_pod"] {
+		if pod := item.(corev1.Pod); pod.Name == "job" && pod.DeletionTimestamp == nil {
+			t.Errorf("deleted pod has no deletion timestamp")
+		}
+	}
+
+	// Events and deleted pods are only reported once
+	data, err = k.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data["k8s_event"]) != 0 {
+		t.Errorf("events reported again: %v", data["k8s_event"])
+	}
+	if got := objectNames(data, "k8s_pod"); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("pods = %v, want the running one", got)
+	}
+}
+
+func TestKubernetesWatchPluginScope(t *testing.T) {
+	for _, tt := range scopeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &KubernetesWatchPlugin{
+				Clientset: fake.NewSimpleClientset(scopedCluster()...),
+				Namespace: tt.namespace,
+				Scope:     tt.scope,
+			}
+			startWatch(t, k)
+			data, err := k.FetchData()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				if got := objectNames(data, key); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKubernetesWatchPluginMaxEvents(t *testing.T) {
+	k := &KubernetesWatchPlugin{MaxEvents: 2, deletedPods: make(map[types.UID]corev1.Pod)}
+	for _, name := range []string{"a", "b", "c"} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, UID: types.UID(name)}}
+		k.record("Pod", EventDeleted, pod)
+	}
+	if got, want := eventActions(k.events), []string{"Pod DELETED b", "Pod DELETED c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if len(k.deletedPods) != 2 || !reflect.DeepEqual(k.deletedOrder, []types.UID{"b", "c"}) {
+		t.Errorf("deleted pods = %v, want the last two", k.deletedOrder)
+	}
+	for _, uid := range k.deletedOrder {
+		if _, ok := k.deletedPods[uid]; !ok {
+			t.Errorf("deleted pod %s is missing", uid)
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	pod := func(change func(p *corev1.Pod)) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}, Annotations: map[string]string{"has_pd": "{}"}},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if change != nil {
+			change(p)
+		}
+		return p
+	}
+	tests := []struct {
+		name   string
+		change func(p *corev1.Pod)
+		want   bool
+	}{
+		{name: "unchanged", want: false},
+		{name: "resource version", change: func(p *corev1.Pod) { p.ResourceVersion = "2" }, want: false},
+		{name: "other annotation", change: func(p *corev1.Pod) { p.Annotations["restartedAt"] = "now" }, want: false},
+		{name: "condition", change: func(p *corev1.Pod) { p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady}} }, want: false},
+		{name: "phase", change: func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded }, want: true},
+		{name: "node", change: func(p *corev1.Pod) { p.Spec.NodeName = "worker-2" }, want: true},
+		{name: "label", change: func(p *corev1.Pod) { p.Labels["app"] = "api" }, want: true},
+		{name: "has_pd", change: func(p *corev1.Pod) { p.Annotations["has_pd"] = `{"dataCategories":[]}` }, want: true},
+		{name: "has_pd removed", change: func(p *corev1.Pod) { delete(p.Annotations, "has_pd") }, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changed(pod(nil), pod(tt.change)); got != tt.want {
+				t.Errorf("changed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A timeout firing after the caches synced leaves the watches running
+func TestKubernetesWatchPluginStartTimeout(t *testing.T) {
+	k := &KubernetesWatchPlugin{Clientset: fake.NewSimpleClientset(), MaxEvents: 10}
+	if err := k.start(time.Nanosecond); err == nil {
+		// The sync won the race, the watches must still deliver changes
+		defer k.Close()
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"}}
+		if _, err := k.Clientset.CoreV1().Pods("shop").Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		waitForEvents(t, k, 1)
+		return
+	}
+	select {
+	case <-k.stopCh:
+	default:
+		t.Errorf("the watches of a failed start weren't stopped")
+	}
+}
@@ -0,0 +1,635 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/api"
+	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/httpwrapper"
+	osModels "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+	openstackServices "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/services"
+)
+
+var errNotFound = errors.New("not found")
+
+// swiftListingLimit is the default maximum of containers Swift lists per request
+const swiftListingLimit = 10000
+
+// listPageLimit is the number of items requested per page of a Neutron or Nova listing
+const listPageLimit = 1000
+
+// NeutronNetwork is a network as returned by the Neutron API.
+type NeutronNetwork struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	ProjectID string   `json:"project_id"`
+	Status    string   `json:"status"`
+	Shared    bool     `json:"shared"`
+	External  bool     `json:"router:external"`
+	Subnets   []string `json:"subnets"`
+}
+
+// NeutronSubnet is an IP range of a network.
+type NeutronSubnet struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ProjectID string `json:"project_id"`
+	NetworkID string `json:"network_id"`
+	CIDR      string `json:"cidr"`
+	IPVersion int    `json:"ip_version"`
+	GatewayIP string `json:"gateway_ip"`
+}
+
+// NeutronPort connects a device, like an instance or a router, to a network.
+type NeutronPort struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ProjectID   string `json:"project_id"`
+	NetworkID   string `json:"network_id"`
+	DeviceID    string `json:"device_id"`
+	DeviceOwner string `json:"device_owner"`
+	MACAddress  string `json:"mac_address"`
+	Status      string `json:"status"`
+	FixedIPs    []struct {
+		SubnetID  string `json:"subnet_id"`
+		IPAddress string `json:"ip_address"`
+	} `json:"fixed_ips"`
+	SecurityGroups []string `json:"security_groups"`
+}
+
+// NeutronRouter routes between networks, its gateway connects them to an external network.
+type NeutronRouter struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	ProjectID           string `json:"project_id"`
+	Status              string `json:"status"`
+	ExternalGatewayInfo *struct {
+		NetworkID string `json:"network_id"`
+	} `json:"external_gateway_info"`
+}
+
+// NeutronFloatingIP is a public address of an external network mapped onto a port.
+type NeutronFloatingIP struct {
+	ID                string `json:"id"`
+	ProjectID         string `json:"project_id"`
+	FloatingIPAddress string `json:"floating_ip_address"`
+	FloatingNetworkID string `json:"floating_network_id"`
+	FixedIPAddress    string `json:"fixed_ip_address"`
+	PortID            string `json:"port_id"`
+	RouterID          string `json:"router_id"`
+	Status            string `json:"status"`
+}
+
+// NeutronSecurityGroup is a set of firewall rules applied to ports.
+type NeutronSecurityGroup struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	ProjectID   string                     `json:"project_id"`
+	Description string                     `json:"description"`
+	Rules       []NeutronSecurityGroupRule `json:"security_group_rules"`
+}
+
+// NeutronSecurityGroupRule allows traffic, rules without remote allow any source.
+type NeutronSecurityGroupRule struct {
+	ID             string `json:"id"`
+	Direction      string `json:"direction"`
+	EtherType      string `json:"ethertype"`
+	Protocol       string `json:"protocol"`
+	PortRangeMin   *int   `json:"port_range_min"`
+	PortRangeMax   *int   `json:"port_range_max"`
+	RemoteIPPrefix string `json:"remote_ip_prefix"`
+	RemoteGroupID  string `json:"remote_group_id"`
+}
+
+// GlanceImage is an image as returned by the Glance API. Owner is the project
+// that uploaded it, public images are usually owned by the cloud operator.
+type GlanceImage struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	Visibility   string `json:"visibility"`
+	Owner        string `json:"owner"`
+	OSDistro     string `json:"os_distro"`
+	OSVersion    string `json:"os_version"`
+	OSType       string `json:"os_type"`
+	Architecture string `json:"architecture"`
+	Hidden       bool   `json:"os_hidden"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// NovaFlavor is the hardware template of an instance.
+type NovaFlavor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	VCPUs     int    `json:"vcpus"`
+	RAM       int    `json:"ram"`
+	Disk      int    `json:"disk"`
+	Ephemeral int    `json:"OS-FLV-EXT-DATA:ephemeral"`
+	IsPublic  bool   `json:"os-flavor-access:is_public"`
+	// ExtraSpecs is listed by Nova 2.61 and later
+	ExtraSpecs map[string]string `json:"extra_specs,omitempty"`
+}
+
+// SwiftContainer is an object storage container, its ACLs and metadata come
+// from the container headers. S3 buckets served by Swift are containers too.
+type SwiftContainer struct {
+	Name          string `json:"name"`
+	ProjectID     string `json:"project_id"`
+	Count         int64  `json:"count"`
+	Bytes         int64  `json:"bytes"`
+	LastModified  string `json:"last_modified"`
+	ReadACL       string `json:"read_acl,omitempty"`
+	WriteACL      string `json:"write_acl,omitempty"`
+	StoragePolicy string `json:"storage_policy,omitempty"`
+	// HasPD is the has_pd annotation set as X-Container-Meta-Has-Pd
+	HasPD string `json:"has_pd,omitempty"`
+}
+
+// OpenStackPlugin extends the openstack provider plugin, which lists the
+// project, its instances, volumes and snapshots, with the Neutron networking
+// and Swift containers of the project and the images and flavors of its
+// instances.
+type OpenStackPlugin struct {
+	*openstackServices.OpenStackPlugin
+	// NetworkAPI is the path of the Neutron API, empty disables networking
+	NetworkAPI string
+	// ImageAPI is the path of the Glance API, empty disables images
+	ImageAPI string
+	// ObjectAPI is the path of the Swift account, {project_id} is replaced by
+	// the project ID. Empty disables object storage
+	ObjectAPI  string
+	ComputeAPI string
+	Client     *httpwrapper.HTTPClient
+	// Scope skips the fetch if the project isn't selected by the filters
+	Scope Scope
+}
+
+func NewOpenStackPlugin() *OpenStackPlugin {
+	return &OpenStackPlugin{OpenStackPlugin: &openstackServices.OpenStackPlugin{}}
+}
+
+func (o *OpenStackPlugin) Initialize(config map[string]interface{}) error {
+	if err := o.OpenStackPlugin.Initialize(config); err != nil {
+		return err
+	}
+	o.Scope = ParseScope(config)
+	if apiAccess, ok := config["api_access"].(map[string]interface{}); ok {
+		o.NetworkAPI, _ = apiAccess["network_api"].(string)
+		o.ImageAPI, _ = apiAccess["image_api"].(string)
+		o.ObjectAPI, _ = apiAccess["object_api"].(string)
+		o.ComputeAPI, _ = apiAccess["compute_api"].(string)
+	}
+	// Neutron, Glance, Swift and Nova are called with the token Keystone hands to the shared client
+	if keystone, ok := o.Keystone.(*api.KeystoneService); ok {
+		o.Client = keystone.Client
+	}
+	return nil
+}
+
+func (o *OpenStackPlugin) FetchData() (models.RawData, error) {
+	return o.FetchDataContext(context.Background())
+}
+
+// FetchDataContext adds the networks, subnets, ports, routers, floating IPs
+// and security groups of the project under os_network, os_subnet, os_port,
+// os_router, os_floatingip and os_security_group, its containers under
+// os_container, and the images and flavors of its instances under os_image
+// and os_flavor. A project the filters leave out is returned alone.
+func (o *OpenStackPlugin) FetchDataContext(ctx context.Context) (models.RawData, error) {
+	data, selected, err := o.fetchProject()
+	if err != nil || !selected || o.Client == nil {
+		return data, err
+	}
+	if err := o.fetchNetworking(ctx, data); err != nil {
+		return data, err
+	}
+	if err := o.fetchContainers(ctx, data); err != nil {
+		return data, err
+	}
+	return data, o.fetchImagesAndFlavors(ctx, data)
+}
+
+// fetchProject fetches what the openstack provider plugin does once the
+// project is known to be selected, only the project otherwise.
+func (o *OpenStackPlugin) fetchProject() (data models.RawData, selected bool, err error) {
+	data = make(models.RawData)
+	apiCtx := &openstackServices.APIContext{}
+	project, err := o.FetchKeystoneData(apiCtx)
+	if err != nil {
+		return data, false, err
+	}
+	data["os_project"] = []interface{}{project}
+	if !o.Scope.SelectsProject(project.Project.ID, project.Project.Name) {
+		logger.Info("Skipping project excluded by filters", logger.LogFields{"project": project.Project.Name})
+		return data, false, nil
+	}
+
+	instances, err := o.FetchNovaData(apiCtx)
+	if err != nil {
+		return data, true, err
+	}
+	data["os_instance"] = instances
+	volumes, err := o.FetchCinderData(apiCtx)
+	if err != nil {
+		return data, true, err
+	}
+	data["os_volume"] = volumes
+	snapshots, err := o.FetchCinderSnapshots(apiCtx)
+	if err != nil {
+		return data, true, err
+	}
+	data["os_snapshot"] = snapshots
+	return data, true, nil
+}
+
+// projectID returns the ID of the scanned project.
+func projectID(data models.RawData) string {
+	if projects := data["os_project"]; len(projects) > 0 {
+		if project, ok := projects[0].(*osModels.ProjectDetails); ok {
+			return project.Project.ID
+		}
+	}
+	return ""
+}
+
+func (o *OpenStackPlugin) fetchNetworking(ctx context.Context, data models.RawData) error {
+	if o.NetworkAPI == "" {
+		return nil
+	}
+
+	projectID := projectID(data)
+
+	resources := []struct {
+		key  string
+		path string
+		list func(body []byte) ([]interface{}, string, error)
+	}{
+		{"os_network", "networks", decodeList[NeutronNetwork]("networks")},
+		{"os_subnet", "subnets", decodeList[NeutronSubnet]("subnets")},
+		{"os_port", "ports", decodeList[NeutronPort]("ports")},
+		{"os_router", "routers", decodeList[NeutronRouter]("routers")},
+		{"os_floatingip", "floatingips", decodeList[NeutronFloatingIP]("floatingips")},
+		{"os_security_group", "security-groups", decodeList[NeutronSecurityGroup]("security_groups")},
+	}
+	for _, r := range resources {
+		query := url.Values{}
+		// Networks shared with the project, like the external network, are listed as well
+		if projectID != "" && r.path != "networks" {
+			query.Set("project_id", projectID)
+		}
+		items, err := o.listPages(ctx, o.NetworkAPI+r.path, query, r.list)
+		if err != nil {
+			return fmt.Errorf("error fetching %s: %v", r.path, err)
+		}
+		data[r.key] = items
+	}
+	return nil
+}
+
+// fetchContainers lists the containers of the project's Swift account and
+// reads ACLs, storage policy and metadata from the headers of each.
+func (o *OpenStackPlugin) fetchContainers(ctx context.Context, data models.RawData) error {
+	if o.ObjectAPI == "" {
+		return nil
+	}
+	project := projectID(data)
+	account := strings.ReplaceAll(o.ObjectAPI, "{project_id}", project)
+
+	containers, err := o.listContainers(ctx, account)
+	if err != nil {
+		return fmt.Errorf("error listing containers: %v", err)
+	}
+	for _, container := range containers {
+		header, err := o.head(ctx, account+url.PathEscape(container.Name))
+		if err == errNotFound {
+			// Deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error reading container %s: %v", container.Name, err)
+		}
+		container.ProjectID = project
+		container.ReadACL = header.Get("X-Container-Read")
+		container.WriteACL = header.Get("X-Container-Write")
+		container.StoragePolicy = header.Get("X-Storage-Policy")
+		container.HasPD = header.Get("X-Container-Meta-Has-Pd")
+		data["os_container"] = append(data["os_container"], container)
+	}
+	return nil
+}
+
+// listContainers pages through the container listing of a Swift account,
+// which returns at most swiftListingLimit containers per request.
+func (o *OpenStackPlugin) listContainers(ctx context.Context, account string) ([]SwiftContainer, error) {
+	var containers []SwiftContainer
+	marker := ""
+	for {
+		query := url.Values{"format": {"json"}, "limit": {strconv.Itoa(swiftListingLimit)}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		var page []SwiftContainer
+		if err := o.getJSON(ctx, account+"?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		containers = append(containers, page...)
+		if len(page) < swiftListingLimit {
+			return containers, nil
+		}
+		marker = page[len(page)-1].Name
+	}
+}
+
+// fetchImagesAndFlavors gets the images and flavors the instances were booted
+// with. Images are not listed, most of a cloud's public images are unused.
+func (o *OpenStackPlugin) fetchImagesAndFlavors(ctx context.Context, data models.RawData) error {
+	seenImages := make(map[string]bool)
+	seenFlavors := make(map[string]bool)
+	var embedded *embeddedFlavors
+	for i, item := range data["os_instance"] {
+		server, ok := item.(osModels.ServerDetails)
+		if !ok {
+			continue
+		}
+		// Instances booted from a volume have no image
+		if id := server.Server.Image.ID; o.ImageAPI != "" && id != "" && !seenImages[id] {
+			seenImages[id] = true
+			var image GlanceImage
+			switch err := o.getJSON(ctx, o.ImageAPI+"images/"+id, &image); err {
+			case nil:
+				data["os_image"] = append(data["os_image"], image)
+			case errNotFound:
+				// Deleted images are still referenced by the instances booted from them
+				logger.Warning("Image of instance not found", logger.LogFields{"image": id, "instance": server.Server.ID})
+			default:
+				return fmt.Errorf("error fetching image %s: %v", id, err)
+			}
+		}
+		if o.ComputeAPI == "" {
+			continue
+		}
+		if id := server.Server.Flavor.ID; id == "" {
+			// Nova 2.47 and later embed the flavor without its id
+			if embedded == nil {
+				var err error
+				if embedded, err = o.fetchEmbeddedFlavors(ctx, projectID(data)); err != nil {
+					return err
+				}
+			}
+			flavor, ok := embedded.flavor(server.Server.ID)
+			if !ok {
+				continue
+			}
+			server.Server.Flavor.ID = flavor.ID
+			data["os_instance"][i] = server
+			if !seenFlavors[flavor.ID] {
+				seenFlavors[flavor.ID] = true
+				data["os_flavor"] = append(data["os_flavor"], flavor)
+			}
+		} else if !seenFlavors[id] {
+			seenFlavors[id] = true
+			var flavor struct {
+				Flavor NovaFlavor `json:"flavor"`
+			}
+			switch err := o.getJSON(ctx, o.ComputeAPI+"flavors/"+id, &flavor); err {
+			case nil:
+				data["os_flavor"] = append(data["os_flavor"], flavor.Flavor)
+			case errNotFound:
+				// Deleted flavors are still referenced by the instances created with them
+				logger.Warning("Flavor of instance not found", logger.LogFields{"flavor": id, "instance": server.Server.ID})
+			default:
+				return fmt.Errorf("error fetching flavor %s: %v", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NovaServerFlavor is a server of the listing with the flavor newer Nova
+// microversions embed, a copy of the flavor at boot time named instead of
+// keyed by its id.
+type NovaServerFlavor struct {
+	ID     string         `json:"id"`
+	Flavor EmbeddedFlavor `json:"flavor"`
+}
+
+// EmbeddedFlavor is the flavor a server was booted with.
+type EmbeddedFlavor struct {
+	OriginalName string            `json:"original_name"`
+	VCPUs        int               `json:"vcpus"`
+	RAM          int               `json:"ram"`
+	Disk         int               `json:"disk"`
+	Ephemeral    int               `json:"ephemeral"`
+	ExtraSpecs   map[string]string `json:"extra_specs,omitempty"`
+}
+
+// embeddedFlavors resolves the flavors embedded in the instances of a project
+// to the flavors of the cloud, so instances of all microversions key a flavor
+// by the same id
+type embeddedFlavors struct {
+	servers map[string]EmbeddedFlavor // embedded flavor by server ID
+	byName  map[string]NovaFlavor     // flavor by name, names are unique within a cloud
+}
+
+// fetchEmbeddedFlavors reads the embedded flavors from the server listing of
+// the project and the flavors they name from the flavor listing, instead of
+// fetching every server.
+func (o *OpenStackPlugin) fetchEmbeddedFlavors(ctx context.Context, project string) (*embeddedFlavors, error) {
+	e := &embeddedFlavors{servers: make(map[string]EmbeddedFlavor), byName: make(map[string]NovaFlavor)}
+
+	query := url.Values{}
+	if project != "" {
+		query.Set("project_id", project)
+	}
+	servers, err := o.listPages(ctx, o.ComputeAPI+"servers/detail", query, decodeList[NovaServerFlavor]("servers"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing flavors of instances: %v", err)
+	}
+	for _, item := range servers {
+		server := item.(NovaServerFlavor)
+		e.servers[server.ID] = server.Flavor
+	}
+
+	// Admins list the private flavors of all projects with is_public=None
+	flavors, err := o.listPages(ctx, o.ComputeAPI+"flavors/detail", url.Values{"is_public": {"None"}}, decodeList[NovaFlavor]("flavors"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing flavors: %v", err)
+	}
+	for _, item := range flavors {
+		flavor := item.(NovaFlavor)
+		e.byName[flavor.Name] = flavor
+	}
+	return e, nil
+}
+
+// flavor returns the flavor embedded in a server. It is the flavor of the
+// cloud of the same name as long as that has the same spec. A flavor deleted
+// or recreated with another spec since the server was booted is returned from
+// the embedded copy, keyed by its spec. Servers created after the listing are
+// logged and left out.
+func (e *embeddedFlavors) flavor(serverID string) (NovaFlavor, bool) {
+	embedded, ok := e.servers[serverID]
+	if !ok || embedded.OriginalName == "" {
+		logger.Warning("Flavor of instance not listed", logger.LogFields{"instance": serverID})
+		return NovaFlavor{}, false
+	}
+	if flavor, ok := e.byName[embedded.OriginalName]; ok && embedded.sameSpec(flavor) {
+		return flavor, true
+	}
+	spec, _ := json.Marshal(embedded)
+	return NovaFlavor{
+		ID:         "embedded-" + sha256Hex(spec)[:16],
+		Name:       embedded.OriginalName,
+		VCPUs:      embedded.VCPUs,
+		RAM:        embedded.RAM,
+		Disk:       embedded.Disk,
+		Ephemeral:  embedded.Ephemeral,
+		ExtraSpecs: embedded.ExtraSpecs,
+	}, true
+}
+
+// sameSpec reports whether flavor still has the spec the server was booted
+// with. Extra specs are only compared when the listing has them.
+func (f EmbeddedFlavor) sameSpec(flavor NovaFlavor) bool {
+	if f.VCPUs != flavor.VCPUs || f.RAM != flavor.RAM || f.Disk != flavor.Disk || f.Ephemeral != flavor.Ephemeral {
+		return false
+	}
+	if flavor.ExtraSpecs == nil || len(f.ExtraSpecs) == 0 && len(flavor.ExtraSpecs) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(f.ExtraSpecs, flavor.ExtraSpecs)
+}
+
+// listPages pages through a Neutron or Nova listing, requesting pages of
+// listPageLimit items and following the next links decoded by list.
+func (o *OpenStackPlugin) listPages(ctx context.Context, endpoint string, query url.Values, list func(body []byte) ([]interface{}, string, error)) ([]interface{}, error) {
+	query.Set("limit", strconv.Itoa(listPageLimit))
+	var items []interface{}
+	for {
+		body, err := o.get(ctx, endpoint+"?"+query.Encode())
+		if err != nil {
+			return nil, err
+		}
+		page, next, err := list(body)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" || len(page) == 0 || next == query.Get("marker") {
+			return items, nil
+		}
+		query.Set("marker", next)
+	}
+}
+
+func (o *OpenStackPlugin) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	body, err := o.get(ctx, endpoint)
+	if err != nil || body == nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// head sends an authenticated HEAD request and returns the response headers.
+func (o *OpenStackPlugin) head(ctx context.Context, endpoint string) (http.Header, error) {
+	req, err := o.Client.NewRequest(http.MethodHead, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := o.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return res.Header, nil
+	case http.StatusNotFound:
+		return nil, errNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+}
+
+// get sends an authenticated GET request and returns the response body.
+func (o *OpenStackPlugin) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := o.Client.NewRequest(http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := o.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		// Swift answers listings of empty accounts without a body
+		return nil, nil
+	case http.StatusNotFound:
+		return nil, errNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// decodeList returns a decoder for Neutron and Nova list responses, which
+// wrap the items in an object under the plural of the resource. Paginated responses
+// link the next page under the plural with _links, the decoder returns the
+// marker of that page or "" on the last page.
+func decodeList[T any](field string) func(body []byte) ([]interface{}, string, error) {
+	return func(body []byte) ([]interface{}, string, error) {
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, "", err
+		}
+		var list []T
+		if raw, ok := page[field]; ok {
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, "", err
+			}
+		}
+		items := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			items = append(items, item)
+		}
+
+		var links []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		}
+		if raw, ok := page[field+"_links"]; ok {
+			if err := json.Unmarshal(raw, &links); err != nil {
+				return nil, "", err
+			}
+		}
+		for _, link := range links {
+			if link.Rel != "next" {
+				continue
+			}
+			next, err := url.Parse(link.Href)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid next link %q: %v", link.Href, err)
+			}
+			return items, next.Query().Get("marker"), nil
+		}
+		return items, "", nil
+	}
+}
@@ -0,0 +1,170 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/httpwrapper"
+	osModels "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/models"
+)
+
+// openStackServer serves handler and returns a plugin calling it
+func openStackServer(t *testing.T, handler http.HandlerFunc) *OpenStackPlugin {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &OpenStackPlugin{Client: httpwrapper.NewClient(srv.URL)}
+}
+
+// openStackData is the raw data of a scanned project
+func openStackData(id string) models.RawData {
+	project := &osModels.ProjectDetails{}
+	project.Project.ID = id
+	return models.RawData{"os_project": {project}}
+}
+
+func TestFetchNetworkingPages(t *testing.T) {
+	// Ports are served two per page the way Neutron does with
+	// pagination_max_limit, the other resources fit into a page
+	ports := []string{"p1", "p2", "p3", "p4", "p5"}
+	o := openStackServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("limit") != strconv.Itoa(listPageLimit) {
+			t.Errorf("%s requested without limit", r.URL)
+		}
+		if r.URL.Path != "/v2.0/ports" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		if query.Get("project_id") != "project-1" {
+			t.Errorf("ports listed without project: %s", r.URL)
+		}
+		start := 0
+		for i, id := range ports {
+			if id == query.Get("marker") {
+				start = i + 1
+			}
+		}
+		end := start + 2
+		if end > len(ports) {
+			end = len(ports)
+		}
+		page := map[string]interface{}{}
+		var items []map[string]string
+		for _, id := range ports[start:end] {
+			items = append(items, map[string]string{"id": id})
+		}
+		page["ports"] = items
+		if end < len(ports) {
+			page["ports_links"] = []map[string]string{
+				{"rel": "previous", "href": "http://neutron/v2.0/ports?limit=2&marker=" + ports[start] + "&page_reverse=True"},
+				{"rel": "next", "href": "http://neutron/v2.0/ports?limit=2&marker=" + ports[end-1]},
+			}
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+	o.NetworkAPI = "/v2.0/"
+
+	data := openStackData("project-1")
+	if err := o.fetchNetworking(context.Background(), data); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range data["os_port"] {
+		got = append(got, item.(NeutronPort).ID)
+	}
+	if !reflect.DeepEqual(got, ports) {
+		t.Errorf("ports = %v, want %v", got, ports)
+	}
+	if len(data["os_network"]) != 0 {
+		t.Errorf("networks = %v, want none", data["os_network"])
+	}
+}
+
+func TestFetchEmbeddedFlavors(t *testing.T) {
+	var requests []string
+	o := openStackServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/compute/servers/detail":
+			if r.URL.Query().Get("project_id") != "project-1" {
+				t.Errorf("servers listed without project: %s", r.URL)
+			}
+			// s3 was booted with a flavor deleted since, s5 with m1.large
+			// before it was recreated with more memory
+			w.Write([]byte(`{"servers": [
+				{"id": "s1", "flavor": {"original_name": "m1.small", "vcpus": 1}},
+				{"id": "s2", "flavor": {"original_name": "m1.small", "vcpus": 1}},
+				{"id": "s3", "flavor": {"original_name": "m1.deleted", "vcpus": 2, "extra_specs": {"hw:cpu_policy": "dedicated"}}},
+				{"id": "s5", "flavor": {"original_name": "m1.large", "vcpus": 4, "ram": 4096}}
+			]}`))
+		case "/compute/flavors/detail":
+			w.Write([]byte(`{"flavors": [
+				{"id": "f-small", "name": "m1.small", "vcpus": 1},
+				{"id": "f-large-2", "name": "m1.large", "vcpus": 4, "ram": 8192}
+			]}`))
+		case "/compute/flavors/f-small":
+			w.Write([]byte(`{"flavor": {"id": "f-small", "name": "m1.small", "vcpus": 1}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	o.ComputeAPI = "/compute/"
+
+	data := openStackData("project-1")
+	// s4 was created with an older microversion and names the flavor by its id
+	for _, id := range []string{"s1", "s2", "s3", "s4", "s5", "s6"} {
+		server := osModels.ServerDetails{}
+		server.Server.ID = id
+		if id == "s4" {
+			server.Server.Flavor.ID = "f-small"
+		}
+		data["os_instance"] = append(data["os_instance"], server)
+	}
+	if err := o.fetchImagesAndFlavors(context.Background(), data); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"/compute/servers/detail", "/compute/flavors/detail"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	flavors := make(map[string]NovaFlavor)
+	for _, item := range data["os_flavor"] {
+		flavor := item.(NovaFlavor)
+		if _, ok := flavors[flavor.ID]; ok {
+			t.Errorf("flavor %s listed twice", flavor.ID)
+		}
+		flavors[flavor.ID] = flavor
+	}
+	var got []NovaFlavor
+	for _, item := range data["os_instance"] {
+		got = append(got, flavors[item.(osModels.ServerDetails).Server.Flavor.ID])
+	}
+	small := NovaFlavor{ID: "f-small", Name: "m1.small", VCPUs: 1}
+	// Flavors not found by name with the same spec come from the embedded copy
+	want := []NovaFlavor{
+		small,
+		small,
+		{Name: "m1.deleted", VCPUs: 2, ExtraSpecs: map[string]string{"hw:cpu_policy": "dedicated"}},
+		small,
+		{Name: "m1.large", VCPUs: 4, RAM: 4096},
+		// s6 was created after the listing
+		{},
+	}
+	for i := range got {
+		if strings.HasPrefix(got[i].ID, "embedded-") {
+			got[i].ID = ""
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flavors of the instances = %+v, want %+v", got, want)
+	}
+}
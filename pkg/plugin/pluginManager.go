@@ -2,32 +2,72 @@ package plugin
 
 import (
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
-	kubernetesServices "github.com/regulatory-transparency-monitor/kubernetes-provider-plugin/pkg/services"
-
-	openstackServices "github.com/regulatory-transparency-monitor/openstack-provider-plugin/pkg/services"
+	"github.com/regulatory-transparency-monitor/commons/models"
 	"github.com/spf13/viper"
 )
 
+// PluginManager holds the active plugins keyed by provider instance ID, so
+// the same provider can be configured several times, e.g. one per cluster.
 type PluginManager struct {
 	RegisteredPlugins map[string]Plugin
 	ActivePlugins     map[string]Plugin
+	// ProviderTypes maps an instance ID to the provider name it was configured with
+	ProviderTypes map[string]string
+	// ScanTimeouts holds the per-plugin deadline for a single FetchData call
+	ScanTimeouts map[string]time.Duration
+	// Schedules holds the configured scan schedule of each plugin instance
+	Schedules map[string]Schedule
+	// Settings holds the provider entry of each plugin instance for settings
+	// applied outside of the plugins, like filters
+	Settings map[string]map[string]interface{}
+	// order keeps the active plugins in the order they are configured in
+	order []string
+	// RecordFilter returns the filter of a provider entry applied to its
+	// recordings, see RecordingPlugin. It is set before InitializePlugins.
+	RecordFilter func(config map[string]interface{}) func(models.RawData) models.RawData
+	// fetching holds the plugins whose FetchData hasn't returned yet, a fetch
+	// that timed out may still be running
+	mu       sync.Mutex
+	fetching map[string]bool
 }
 
 func NewPluginManager() *PluginManager {
 	return &PluginManager{
 		RegisteredPlugins: make(map[string]Plugin),
 		ActivePlugins:     make(map[string]Plugin),
+		ProviderTypes:     make(map[string]string),
+		ScanTimeouts:      make(map[string]time.Duration),
+		Schedules:         make(map[string]Schedule),
+		Settings:          make(map[string]map[string]interface{}),
+		fetching:          make(map[string]bool),
 	}
 }
 
 func (pm *PluginManager) RegisterPluginConstructors() {
 	PluginConstructorRegistry["openstack"] = func() Plugin {
-		return &openstackServices.OpenStackPlugin{}
+		return NewOpenStackPlugin()
 	}
 	PluginConstructorRegistry["kubernetes"] = func() Plugin {
-		return &kubernetesServices.KubernetesPlugin{}
+		return NewKubernetesPlugin()
+	}
+	PluginConstructorRegistry["kubernetes-watch"] = func() Plugin {
+		return &KubernetesWatchPlugin{}
+	}
+	PluginConstructorRegistry["aws"] = func() Plugin {
+		return &AWSPlugin{}
+	}
+	PluginConstructorRegistry["replay"] = func() Plugin {
+		return &ReplayPlugin{}
+	}
+	PluginConstructorRegistry["tilt"] = func() Plugin {
+		return &TILTPlugin{}
 	}
+	// Executables in the plugins directory are served out of process
+	DiscoverExternalPlugins(viper.GetString("PLUGINS_DIR"))
 }
 
 func (pm *PluginManager) InitializePlugins() error {
@@ -37,21 +77,44 @@ func (pm *PluginManager) InitializePlugins() error {
 	for _, provider := range providers {
 		p := provider.(map[string]interface{})
 		name := p["name"].(string)
+		id := instanceID(p)
 
 		if p["enabled"].(bool) {
-			pluginConstructor, exists := PluginConstructorRegistry[name]
-			if !exists {
-				fmt.Printf("Plugin %s not found", name)
+			if _, exists := pm.ActivePlugins[id]; exists {
+				fmt.Printf("Provider instance %s is configured more than once, set a unique id", id)
 				continue
 			}
-			pluginInstance := pluginConstructor()
+			var pluginInstance Plugin
+			if executable, ok := p["executable"].(string); ok && executable != "" {
+				// An explicitly configured executable overrides any in-tree plugin
+				pluginInstance = NewExternalPlugin(executable)
+			} else {
+				pluginConstructor, exists := PluginConstructorRegistry[constructorName(p)]
+				if !exists {
+					fmt.Printf("Plugin %s not found", constructorName(p))
+					continue
+				}
+				pluginInstance = pluginConstructor()
+			}
 			err := pluginInstance.Initialize(p)
 
 			if err != nil {
-				fmt.Printf("Error initializing plugin %s: %v", name, err)
+				fmt.Printf("Error initializing plugin %s: %v", id, err)
 				continue
 			}
-			pm.ActivePlugins[name] = pluginInstance
+			if dir, ok := p["record"].(string); ok && dir != "" {
+				recording := NewRecordingPlugin(pluginInstance, id, dir)
+				if pm.RecordFilter != nil {
+					recording.Filter = pm.RecordFilter(p)
+				}
+				pluginInstance = recording
+			}
+			pm.ActivePlugins[id] = pluginInstance
+			pm.ProviderTypes[id] = name
+			pm.ScanTimeouts[id] = parseDuration("scan_timeout", p["scan_timeout"])
+			pm.Schedules[id] = parseSchedule(p["schedule"])
+			pm.Settings[id] = p
+			pm.order = append(pm.order, id)
 		}
 	}
 	return nil
@@ -66,3 +129,109 @@ func (pm *PluginManager) GetPlugin(name string) (Plugin, error) {
 	}
 	return pluginInstance, nil
 }
+
+// claimFetch marks a plugin as fetching, unless its previous fetch is still running
+func (pm *PluginManager) claimFetch(name string) bool {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if pm.fetching[name] {
+		return false
+	}
+	pm.fetching[name] = true
+	return true
+}
+
+func (pm *PluginManager) releaseFetch(name string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	delete(pm.fetching, name)
+}
+
+// Close releases plugins holding background resources like watches or processes.
+func (pm *PluginManager) Close() {
+	for name, p := range pm.ActivePlugins {
+		if closer, ok := p.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				fmt.Printf("Error closing plugin %s: %v", name, err)
+			}
+		}
+	}
+}
+
+// ActivePluginNames returns the instance IDs of the active plugins in configuration order.
+func (pm *PluginManager) ActivePluginNames() []string {
+	names := make([]string, len(pm.order))
+	copy(names, pm.order)
+	return names
+}
+
+// ScanTimeout returns the deadline for a single scan of the plugin instance.
+func (pm *PluginManager) ScanTimeout(name string) time.Duration {
+	if timeout, ok := pm.ScanTimeouts[name]; ok && timeout > 0 {
+		return timeout
+	}
+	return viper.GetDuration("SCAN_TIMEOUT")
+}
+
+// parseDuration reads an optional duration setting like scan_timeout, 0 means unset.
+func parseDuration(key string, value interface{}) time.Duration {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		fmt.Printf("Invalid %s %q: %v", key, s, err)
+		return 0
+	}
+	return d
+}
+
+// Schedule is the schedule section of a provider entry. Spec is a cron spec
+// like "@every 10m", Jitter delays every run by a random duration up to its
+// value and Blackouts lists windows like "Mon-Fri 08:00-18:00" during which the
+// provider is not scanned.
+type Schedule struct {
+	Spec      string
+	Jitter    time.Duration
+	Blackouts []string
+}
+
+// parseSchedule reads the optional schedule of a provider entry, an empty Spec
+// means use the global default.
+func parseSchedule(value interface{}) Schedule {
+	var schedule Schedule
+	v, ok := value.(map[string]interface{})
+	if !ok {
+		return schedule
+	}
+	schedule.Spec, _ = v["spec"].(string)
+	schedule.Jitter = parseDuration("jitter", v["jitter"])
+	if blackouts, ok := v["blackouts"].([]interface{}); ok {
+		for _, b := range blackouts {
+			if window, ok := b.(string); ok {
+				schedule.Blackouts = append(schedule.Blackouts, window)
+			}
+		}
+	}
+	return schedule
+}
+
+// constructorName selects the constructor of a provider entry, an optional mode
+// picks a variant of the provider, e.g. "kubernetes" with mode "watch".
+func constructorName(p map[string]interface{}) string {
+	name := p["name"].(string)
+	if mode, ok := p["mode"].(string); ok && mode != "" {
+		return name + "-" + mode
+	}
+	return name
+}
+
+// instanceID returns the configured id of a provider entry, defaulting to its
+// name for setups with a single instance per provider.
+func instanceID(p map[string]interface{}) string {
+	if id, ok := p["id"].(string); ok && id != "" {
+		return id
+	}
+	return p["name"].(string)
+}
@@ -0,0 +1,51 @@
+package plugin
+
+import "encoding/json"
+
+// Wire protocol spoken between graph-builder and out-of-process provider plugins.
+//
+// The plugin executable reads requests from stdin and writes responses to
+// stdout, one JSON document per line. Right after start it announces itself
+// with a Handshake line. Every line the plugin writes to stderr is logged by
+// graph-builder with the plugin's provider id.
+//
+//	plugin -> {"protocol":"graph-builder-plugin","version":1}
+//	host   -> {"id":1,"method":"initialize","params":{...provider config...}}
+//	plugin -> {"id":1}
+//	host   -> {"id":2,"method":"fetch_data"}
+//	plugin -> {"id":2,"result":{"os_instance":[{...},{...}]}}
+//	host   -> {"id":3,"method":"shutdown"}
+//
+// A failed call is answered with {"id":N,"error":"message"}.
+const (
+	ProtocolName    = "graph-builder-plugin"
+	ProtocolVersion = 1
+
+	MethodInitialize = "initialize"
+	MethodFetchData  = "fetch_data"
+	MethodShutdown   = "shutdown"
+
+	// MaxLineSize is the longest line either side reads, raw data of large
+	// inventories easily exceeds the default line limit
+	MaxLineSize = 256 * 1024 * 1024
+)
+
+// Handshake is the first line written by a plugin process.
+type Handshake struct {
+	Protocol string `json:"protocol"`
+	Version  int    `json:"version"`
+}
+
+// Request is sent from graph-builder to the plugin process.
+type Request struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is sent from the plugin process back to graph-builder.
+type Response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
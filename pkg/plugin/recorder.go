@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
+)
+
+// Recording is the on-disk format written by RecordingPlugin and read by ReplayPlugin.
+type Recording struct {
+	Provider   string         `json:"provider"`
+	RecordedAt time.Time      `json:"recorded_at"`
+	Error      string         `json:"error,omitempty"`
+	Data       EncodedRawData `json:"data"`
+}
+
+// RecordingPlugin wraps any plugin and dumps what FetchData returned to Dir, one file per scan.
+type RecordingPlugin struct {
+	Plugin
+	Provider string
+	Dir      string
+	// Filter selects the data written to disk, data filtered out of the scan
+	// is never recorded
+	Filter func(models.RawData) models.RawData
+}
+
+// NewRecordingPlugin wraps p so that every scan of provider is recorded to dir.
+func NewRecordingPlugin(p Plugin, provider string, dir string) *RecordingPlugin {
+	return &RecordingPlugin{Plugin: p, Provider: provider, Dir: dir}
+}
+
+func (r *RecordingPlugin) FetchData() (models.RawData, error) {
+	data, err := r.Plugin.FetchData()
+	r.record(data, err)
+	return data, err
+}
+
+func (r *RecordingPlugin) FetchDataContext(ctx context.Context) (models.RawData, error) {
+	cp, ok := r.Plugin.(ContextPlugin)
+	if !ok {
+		return r.FetchData()
+	}
+	data, err := cp.FetchDataContext(ctx)
+	r.record(data, err)
+	return data, err
+}
+
+// Reuse forwards to the wrapped plugin, recording doesn't change what is
+// stored again for a provider that isn't due.
+func (r *RecordingPlugin) Reuse(data models.RawData) models.RawData {
+	if rp, ok := r.Plugin.(ReusingPlugin); ok {
+		return rp.Reuse(data)
+	}
+	return data
+}
+
+// Close closes the wrapped plugin if it holds any resources.
+func (r *RecordingPlugin) Close() error {
+	if closer, ok := r.Plugin.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// record writes a scan to disk, failing to record never fails the scan itself.
+func (r *RecordingPlugin) record(data models.RawData, fetchErr error) {
+	if err := r.write(data, fetchErr); err != nil {
+		logger.Error("Error recording scan", logger.LogFields{"provider": r.Provider}, err)
+	}
+}
+
+func (r *RecordingPlugin) write(data models.RawData, fetchErr error) error {
+	if r.Filter != nil && data != nil {
+		data = r.Filter(data)
+	}
+	encoded, err := EncodeRawData(data)
+	if err != nil {
+		return err
+	}
+	rec := Recording{
+		Provider:   r.Provider,
+		RecordedAt: time.Now().UTC(),
+		Data:       encoded,
+	}
+	if fetchErr != nil {
+		rec.Error = fetchErr.Error()
+	}
+
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return fmt.Errorf("error creating recording directory %s: %v", r.Dir, err)
+	}
+	// The timestamp keeps recordings in scan order when sorted by name
+	name := fmt.Sprintf("%s-%s.json", r.Provider, rec.RecordedAt.Format("20060102T150405.000000000"))
+	f, err := os.Create(filepath.Join(r.Dir, name))
+	if err != nil {
+		return fmt.Errorf("error creating recording file: %v", err)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(rec)
+}
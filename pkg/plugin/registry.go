@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"context"
+
 	"github.com/regulatory-transparency-monitor/commons/models"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
 )
@@ -11,6 +13,22 @@ type Plugin interface {
 	FetchData() (models.RawData, error)
 }
 
+// ContextPlugin is implemented by plugins that can abort a running fetch
+// when the scan deadline expires or the scan is cancelled.
+type ContextPlugin interface {
+	Plugin
+	FetchDataContext(ctx context.Context) (models.RawData, error)
+}
+
+// ReusingPlugin is implemented by plugins whose fetches hold data belonging
+// to the version of the fetch only, like the changes observed since the
+// previous fetch. Reuse returns the data to store again in versions the
+// plugin isn't fetched for.
+type ReusingPlugin interface {
+	Plugin
+	Reuse(data models.RawData) models.RawData
+}
+
 type PluginConstructor func() Plugin
 
 // Registry for plugin constructors.
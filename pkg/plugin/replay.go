@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+)
+
+// ReplayPlugin serves recordings from disk instead of scanning a live provider.
+// Every FetchData call returns the next recording, the last one is repeated
+// once all have been replayed unless loop is enabled.
+type ReplayPlugin struct {
+	Files []string
+	Loop  bool
+	next  int
+}
+
+// Initialize reads the replay config. path may point to a single recording or
+// to a directory, whose *.json files are replayed in name order.
+func (r *ReplayPlugin) Initialize(config map[string]interface{}) error {
+	path, ok := config["path"].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("replay path configuration is missing or invalid")
+	}
+	r.Loop, _ = config["loop"].(bool)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading replay path %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		r.Files = []string{path}
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no recordings found in %s", path)
+	}
+	sort.Strings(files)
+	r.Files = files
+	return nil
+}
+
+func (r *ReplayPlugin) FetchData() (models.RawData, error) {
+	file := r.Files[r.next]
+	if r.next < len(r.Files)-1 {
+		r.next++
+	} else if r.Loop {
+		r.next = 0
+	}
+
+	rec, err := ReadRecording(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := DecodeRawData(rec.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding recording %s: %v", file, err)
+	}
+	// Replay the original scan outcome, including partial failures
+	if rec.Error != "" {
+		return data, fmt.Errorf("recorded scan failed: %s", rec.Error)
+	}
+	return data, nil
+}
+
+// Reuse treats replayed Kubernetes data like the Kubernetes plugins do, the
+// keys of other providers are kept.
+func (r *ReplayPlugin) Reuse(data models.RawData) models.RawData {
+	return reuseKubernetesData(data)
+}
+
+// ReadRecording loads a single recording file.
+func ReadRecording(file string) (*Recording, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening recording %s: %v", file, err)
+	}
+	defer f.Close()
+
+	var rec Recording
+	if err := json.NewDecoder(f).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("error reading recording %s: %v", file, err)
+	}
+	return &rec, nil
+}
@@ -1,26 +1,111 @@
 package plugin
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/regulatory-transparency-monitor/commons/models"
 	"github.com/regulatory-transparency-monitor/graph-builder/pkg/logger"
 	"github.com/spf13/viper"
 )
 
-// Fetch data from all enabled plugins.
-func Scanner(pm *PluginManager, pluginName string) models.RawData {
+// ProviderData holds the outcome of scanning a single provider plugin instance.
+type ProviderData struct {
+	Provider  string // instance ID
+	Type      string // provider name, e.g. kubernetes
+	Data      models.RawData
+	Err       error
+	FetchedAt time.Time
+	Duration  time.Duration
+}
+
+// Reused returns the data to store again in a version the provider wasn't
+// fetched for. Plugins implementing ReusingPlugin leave out what only belongs
+// to the version of the fetch, the data of other plugins is stored unchanged.
+func (pm *PluginManager) Reused(d ProviderData) ProviderData {
+	if d.Data == nil {
+		return d
+	}
+	if p, ok := pm.ActivePlugins[d.Provider].(ReusingPlugin); ok {
+		d.Data = p.Reuse(d.Data)
+	}
+	return d
+}
+
+// ScanAll fetches data from all active plugins concurrently.
+// The results are returned in the order the providers are configured in.
+func ScanAll(ctx context.Context, pm *PluginManager) []ProviderData {
+	return ScanProviders(ctx, pm, pm.ActivePluginNames())
+}
+
+// ScanProviders fetches data from the given plugin instances concurrently,
+// returning the results in the order of names.
+func ScanProviders(ctx context.Context, pm *PluginManager, names []string) []ProviderData {
+	results := make([]ProviderData, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			start := time.Now()
+			d, err := Scanner(ctx, pm, name)
+			results[i] = ProviderData{Provider: name, Type: pm.ProviderTypes[name], Data: d, Err: err, FetchedAt: start, Duration: time.Since(start)}
+		}(i, name)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Scanner fetches data from a single plugin, giving up once the plugin's
+// scan timeout expires or ctx is cancelled.
+func Scanner(ctx context.Context, pm *PluginManager, pluginName string) (models.RawData, error) {
 	pluginInstance, err := pm.GetPlugin(pluginName)
 	if err != nil {
 		logger.Error("Error fetching plugin %v", err)
-		return nil
+		return nil, err
 	}
 
-	d, err := pluginInstance.FetchData()
-	if err != nil {
-		logger.Error("Error scanning plugin %v", err)
+	// Plugins aren't safe for concurrent fetches, one that timed out in an
+	// earlier scan is skipped until its fetch returns
+	if !pm.claimFetch(pluginName) {
+		return nil, fmt.Errorf("scanning plugin %s: previous fetch is still running", pluginName)
 	}
 
-	//fmt.Printf("Data from plugin %s: %+v\n", pluginName, d)
-	return d
+	ctx, cancel := context.WithTimeout(ctx, pm.ScanTimeout(pluginName))
+	defer cancel()
+
+	type fetchResult struct {
+		data models.RawData
+		err  error
+	}
+	done := make(chan fetchResult, 1)
+	go func() {
+		defer pm.releaseFetch(pluginName)
+		var res fetchResult
+		if cp, ok := pluginInstance.(ContextPlugin); ok {
+			res.data, res.err = cp.FetchDataContext(ctx)
+		} else {
+			res.data, res.err = pluginInstance.FetchData()
+		}
+		done <- res
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			logger.Error("Error scanning plugin %v", res.err)
+		}
+		//fmt.Printf("Data from plugin %s: %+v\n", pluginName, d)
+		return res.data, res.err
+	case <-ctx.Done():
+		// Plugins without context support keep running in the background,
+		// their result is dropped once it arrives and they are skipped until then.
+		return nil, fmt.Errorf("scanning plugin %s: %w", pluginName, ctx.Err())
+	}
 }
 
 // TODO add tor end of req list
@@ -0,0 +1,111 @@
+package plugin
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scope holds the namespace and project selectors of a provider's filters.
+// Plugins apply it while fetching, so tenants that must not be scanned aren't
+// fetched at all where the API allows it. The filter is applied again to
+// everything fetched, see dataparser.Filter.
+type Scope struct {
+	Namespaces        []string
+	ExcludeNamespaces []string
+	Projects          []string // project IDs or names, AWS account IDs
+	ExcludeProjects   []string
+}
+
+// ParseScope reads the selectors from the filters section of a provider entry.
+func ParseScope(config map[string]interface{}) Scope {
+	filters, ok := config["filters"].(map[string]interface{})
+	if !ok {
+		return Scope{}
+	}
+	return Scope{
+		Namespaces:        scopeList(filters["namespaces"]),
+		ExcludeNamespaces: scopeList(filters["exclude_namespaces"]),
+		Projects:          scopeList(filters["projects"]),
+		ExcludeProjects:   scopeList(filters["exclude_projects"]),
+	}
+}
+
+// SelectsProject tells whether a project, given by its ID and name, is scanned.
+func (s Scope) SelectsProject(idOrName ...string) bool {
+	included := len(s.Projects) == 0
+	for _, p := range idOrName {
+		if p == "" {
+			continue
+		}
+		if scopeContains(s.ExcludeProjects, p) {
+			return false
+		}
+		if scopeContains(s.Projects, p) {
+			included = true
+		}
+	}
+	return included
+}
+
+// namespaces returns the namespaces to list: the configured namespace, the
+// selected namespaces or all namespaces ("").
+func (s Scope) namespaces(namespace string) []string {
+	if namespace != "" {
+		return []string{namespace}
+	}
+	if len(s.Namespaces) > 0 {
+		return s.Namespaces
+	}
+	return []string{""}
+}
+
+// listOptions leaves the excluded namespaces out of lists of namespaced resources.
+func (s Scope) listOptions() metav1.ListOptions {
+	var selectors []string
+	for _, namespace := range s.ExcludeNamespaces {
+		selectors = append(selectors, "metadata.namespace!="+namespace)
+	}
+	return metav1.ListOptions{FieldSelector: strings.Join(selectors, ",")}
+}
+
+// selectsVolume tells whether a persistent volume is claimed from one of the
+// listed namespaces. Unclaimed volumes belong to no namespace and are kept,
+// like the filter keeps them.
+func (s Scope) selectsVolume(pv corev1.PersistentVolume, namespaces []string) bool {
+	if pv.Spec.ClaimRef == nil {
+		return true
+	}
+	return s.selectsNamespace(pv.Spec.ClaimRef.Namespace, namespaces)
+}
+
+// selectsNamespace tells whether a namespace is one of the listed namespaces
+// and not excluded.
+func (s Scope) selectsNamespace(name string, namespaces []string) bool {
+	if scopeContains(s.ExcludeNamespaces, name) {
+		return false
+	}
+	return scopeContains(namespaces, "") || scopeContains(namespaces, name)
+}
+
+func scopeList(value interface{}) []string {
+	var list []string
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+func scopeContains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
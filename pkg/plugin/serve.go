@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Serve runs p as an out-of-process plugin on stdin/stdout. It is meant to be
+// called from the main function of a plugin executable and returns once the
+// host asks for shutdown or closes stdin.
+func Serve(p Plugin) error {
+	return serve(p, os.Stdin, os.Stdout)
+}
+
+func serve(p Plugin, in io.Reader, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	if err := enc.Encode(Handshake{Protocol: ProtocolName, Version: ProtocolVersion}); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("invalid request: %v", err)
+		}
+
+		resp := Response{ID: req.ID}
+		switch req.Method {
+		case MethodInitialize:
+			var config map[string]interface{}
+			if err := json.Unmarshal(req.Params, &config); err != nil {
+				resp.Error = fmt.Sprintf("invalid config: %v", err)
+			} else if err := p.Initialize(config); err != nil {
+				resp.Error = err.Error()
+			}
+		case MethodFetchData:
+			resp.Result, resp.Error = fetchEncoded(p)
+		case MethodShutdown:
+			return enc.Encode(resp)
+		default:
+			resp.Error = fmt.Sprintf("unknown method: %s", req.Method)
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// fetchEncoded returns partial data together with the fetch error, like in-process plugins do.
+func fetchEncoded(p Plugin) (json.RawMessage, string) {
+	var errMsg string
+	data, err := p.FetchData()
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if data == nil {
+		return nil, errMsg
+	}
+	encoded, err := EncodeRawData(data)
+	if err != nil {
+		return nil, err.Error()
+	}
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return raw, errMsg
+}
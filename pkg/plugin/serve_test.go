@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+)
+
+// serveLines runs serve on the given request lines and returns the lines written
+func serveLines(t *testing.T, p Plugin, requests ...string) ([]string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := serve(p, strings.NewReader(strings.Join(requests, "\n")), &out)
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"), err
+}
+
+func TestServe(t *testing.T) {
+	lines, err := serveLines(t, &flavorPlugin{},
+		`{"id":1,"method":"initialize","params":{"flavor":"m1.small"}}`,
+		`{"id":2,"method":"fetch_data"}`,
+		`{"id":3,"method":"reboot"}`,
+		`{"id":4,"method":"shutdown"}`,
+		`{"id":5,"method":"fetch_data"}`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"protocol":"graph-builder-plugin","version":1}`,
+		`{"id":1}`,
+		`{"id":2,"result":{"os_flavor":[{"id":"f-1","name":"m1.small","vcpus":2,"ram":0,"disk":0,"OS-FLV-EXT-DATA:ephemeral":0,"os-flavor-access:is_public":false}]}}`,
+		`{"id":3,"error":"unknown method: reboot"}`,
+		`{"id":4}`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("serve wrote\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	// Errors of the plugin are answered, partial data is sent along
+	lines, err := serveLines(t, &flavorPlugin{},
+		`{"id":1,"method":"initialize","params":{}}`,
+		`{"id":2,"method":"initialize","params":["not","a","config"]}`,
+		`{"id":3,"method":"initialize","params":{"flavor":"m1.small","fail":true}}`,
+		`{"id":4,"method":"fetch_data"}`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lines[1], `{"id":1,"error":"flavor is missing"}`; got != want {
+		t.Errorf("initialize answered %s, want %s", got, want)
+	}
+	if !strings.HasPrefix(lines[2], `{"id":2,"error":"invalid config: `) {
+		t.Errorf("initialize with invalid params answered %s", lines[2])
+	}
+	var resp Response
+	if err := json.Unmarshal([]byte(lines[4]), &resp); err != nil {
+		t.Fatal(err)
+	}
+	var encoded EncodedRawData
+	if err := json.Unmarshal(resp.Result, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	data, err := DecodeRawData(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := models.RawData{"os_flavor": {NovaFlavor{ID: "f-1", Name: "m1.small", VCPUs: 2}}}
+	if resp.Error != "images unavailable" || !reflect.DeepEqual(data, wantData) {
+		t.Errorf("failed fetch answered %+v, want partial data and the error", resp)
+	}
+
+	// A line that isn't a request ends serving, the stream can't be trusted
+	if _, err := serveLines(t, &flavorPlugin{}, `{"id":1,"method":`); err == nil || !strings.Contains(err.Error(), "invalid request") {
+		t.Errorf("serve of bad JSON = %v, want an invalid request error", err)
+	}
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/regulatory-transparency-monitor/commons/models"
+)
+
+// TILTDocument is the part of a TILT (Transparency Information Language and
+// Toolkit) document the graph is built from.
+type TILTDocument struct {
+	Meta struct {
+		ID     string `json:"_id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		URL    string `json:"url"`
+		Hash   string `json:"_hash"`
+	} `json:"meta"`
+	Controller struct {
+		Name     string `json:"name"`
+		Division string `json:"division"`
+		Address  string `json:"address"`
+		Country  string `json:"country"`
+	} `json:"controller"`
+	DataProtectionOfficer struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"dataProtectionOfficer"`
+	DataDisclosed []TILTDataDisclosed `json:"dataDisclosed"`
+	// File is the document's file name, it names the document when meta has no name
+	File string `json:"file"`
+}
+
+// TILTDataDisclosed is a category of personal data a service discloses.
+type TILTDataDisclosed struct {
+	ID       string `json:"_id"`
+	Category string `json:"category"`
+	Purposes []struct {
+		Purpose     string `json:"purpose"`
+		Description string `json:"description"`
+	} `json:"purposes"`
+	LegalBases []struct {
+		Reference   string `json:"reference"`
+		Description string `json:"description"`
+	} `json:"legalBases"`
+	Recipients []struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+	} `json:"recipients"`
+	Storage []struct {
+		Temporal []struct {
+			Description string `json:"description"`
+			TTL         string `json:"ttl"`
+		} `json:"temporal"`
+	} `json:"storage"`
+}
+
+// TILTPlugin reads the TILT documents maintained by the data protection
+// officers from a directory, one *.json file per service.
+type TILTPlugin struct {
+	Path string
+}
+
+func (t *TILTPlugin) Initialize(config map[string]interface{}) error {
+	path, ok := config["path"].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("tilt path configuration is missing or invalid")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading tilt path %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("tilt path %s is not a directory", path)
+	}
+	t.Path = path
+	return nil
+}
+
+// FetchData reads the documents under the tilt_document key, documents that
+// can't be read are skipped and reported in the error.
+func (t *TILTPlugin) FetchData() (models.RawData, error) {
+	files, err := filepath.Glob(filepath.Join(t.Path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	data := make(models.RawData)
+	var failed []string
+	for _, file := range files {
+		doc, err := readTILTDocument(file)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		data["tilt_document"] = append(data["tilt_document"], doc)
+	}
+	if len(failed) > 0 {
+		return data, fmt.Errorf("error reading tilt documents: %s", strings.Join(failed, "; "))
+	}
+	return data, nil
+}
+
+func readTILTDocument(file string) (TILTDocument, error) {
+	var doc TILTDocument
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return doc, err
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return doc, fmt.Errorf("%s: %v", file, err)
+	}
+	doc.File = filepath.Base(file)
+	return doc, nil
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTILTPluginFetchData(t *testing.T) {
+	p := &TILTPlugin{}
+	if err := p.Initialize(map[string]interface{}{"path": "testdata/tilt"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, item := range data["tilt_document"] {
+		files = append(files, item.(TILTDocument).File)
+	}
+	if want := []string{"newsletter.json", "shop.json"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("documents = %v, want %v", files, want)
+	}
+	shop := data["tilt_document"][1].(TILTDocument)
+	if shop.Meta.ID != "tilt-shop" || shop.Controller.Name != "Example Retail GmbH" || len(shop.DataDisclosed) != 2 {
+		t.Errorf("shop.json read as %+v", shop)
+	}
+}
+
+// Documents that can't be read are reported, the others are still returned
+func TestTILTPluginUnreadableDocuments(t *testing.T) {
+	dir := t.TempDir()
+	fixture, err := os.ReadFile("testdata/tilt/shop.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"shop.json":   string(fixture),
+		"broken.json": `{"meta": {`,
+		"notes.txt":   "not a document",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A directory matching the pattern can't be read either
+	if err := os.Mkdir(filepath.Join(dir, "archive.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &TILTPlugin{}
+	if err := p.Initialize(map[string]interface{}{"path": dir}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.FetchData()
+	if err == nil {
+		t.Fatal("FetchData didn't report the unreadable documents")
+	}
+	for _, want := range []string{"archive.json", "broken.json"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("FetchData error %q doesn't name %s", err, want)
+		}
+	}
+	if len(data["tilt_document"]) != 1 || data["tilt_document"][0].(TILTDocument).File != "shop.json" {
+		t.Errorf("documents = %+v, want shop.json", data["tilt_document"])
+	}
+}
+
+func TestTILTPluginInitialize(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{},
+		{"path": ""},
+		{"path": "testdata/missing"},
+		{"path": "testdata/tilt/shop.json"},
+	} {
+		if err := (&TILTPlugin{}).Initialize(config); err == nil {
+			t.Errorf("Initialize(%v) accepted the path", config)
+		}
+	}
+}